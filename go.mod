@@ -3,7 +3,9 @@ module github.com/kralicky/post-init
 go 1.17
 
 require (
+	github.com/creack/pty v1.1.17
 	github.com/golang/mock v1.6.0
+	github.com/google/go-cmp v0.5.7
 	github.com/kralicky/spellbook v0.0.0-20220204185758-6c5ad9d29ee2
 	github.com/kralicky/totem v0.0.0-20220102221247-a834498478bb
 	github.com/magefile/mage v1.12.1
@@ -14,7 +16,9 @@ require (
 	github.com/phayes/freeport v0.0.0-20180830031419-95f893ade6f2
 	github.com/sirupsen/logrus v1.8.1
 	github.com/spf13/cobra v1.3.0
+	go.etcd.io/bbolt v1.3.6
 	golang.org/x/crypto v0.0.0-20211215153901-e495a2d5b3d3
+	golang.org/x/term v0.0.0-20201126162022-7de9c90e9dd1
 	google.golang.org/grpc v1.44.0
 	google.golang.org/protobuf v1.27.1
 )
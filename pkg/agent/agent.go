@@ -3,7 +3,9 @@ package agent
 import (
 	"context"
 	"crypto/tls"
+	"io"
 	"os/user"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -14,8 +16,11 @@ import (
 	"github.com/kralicky/totem"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type AgentOptions struct {
@@ -24,6 +29,14 @@ type AgentOptions struct {
 	insecure            bool
 	timeout             time.Duration
 	extraAuthorizedKeys []string
+	reconnectDelay      time.Duration
+	maxReconnectDelay   time.Duration
+	labels              map[string]string
+	labelsFile          string
+	metadataCollector   *host.MetadataCollector
+	cloudInitReader     *host.CloudInitReader
+	bootConditions      []host.BootCondition
+	bootTimeout         time.Duration
 }
 
 type AgentOption func(*AgentOptions)
@@ -64,18 +77,92 @@ func WithExtraAuthorizedKeys(keys ...string) AgentOption {
 	}
 }
 
+// WithLabels sets labels to include in the announcement. These take
+// precedence over labels from the labels file and environment.
+func WithLabels(labels map[string]string) AgentOption {
+	return func(o *AgentOptions) {
+		o.labels = labels
+	}
+}
+
+// WithLabelsFile sets the path to a file containing labels to include in the
+// announcement. The file is read each time the agent announces.
+func WithLabelsFile(path string) AgentOption {
+	return func(o *AgentOptions) {
+		o.labelsFile = path
+	}
+}
+
+// WithMetadataCollector sets the collector used to obtain cloud instance
+// metadata for the announcement. Collection is off by default, since it
+// queries the link-local metadata service, which only exists in the cloud.
+func WithMetadataCollector(c *host.MetadataCollector) AgentOption {
+	return func(o *AgentOptions) {
+		o.metadataCollector = c
+	}
+}
+
+// WithCloudInitReader sets the reader used to obtain the status of cloud-init
+// for the announcement. If nil, the status is not reported.
+func WithCloudInitReader(r *host.CloudInitReader) AgentOption {
+	return func(o *AgentOptions) {
+		o.cloudInitReader = r
+	}
+}
+
+// WithBootWait makes the agent wait before announcing until any of the given
+// conditions is met, or until the timeout expires. A timeout of 0 waits
+// indefinitely.
+func WithBootWait(timeout time.Duration, conditions ...host.BootCondition) AgentOption {
+	return func(o *AgentOptions) {
+		o.bootTimeout = timeout
+		o.bootConditions = conditions
+	}
+}
+
+// WithReconnectBackoff sets the initial and maximum delay between attempts to
+// reconnect to the relay after the connection is lost.
+func WithReconnectBackoff(initial, max time.Duration) AgentOption {
+	return func(o *AgentOptions) {
+		o.reconnectDelay = initial
+		o.maxReconnectDelay = max
+	}
+}
+
+// How often the agent checks the status of cloud-init after announcing, until
+// cloud-init has finished.
+const cloudInitPollInterval = 10 * time.Second
+
 type Agent struct {
 	api.UnimplementedInstructionServer
-	options     AgentOptions
-	relayClient api.RelayClient
-	sharedTimer *util.SharedTimer
+	options      AgentOptions
+	relayClient  api.RelayClient
+	sharedTimer  *util.SharedTimer
+	cloud        *api.CloudMetadata
+	bootWait     *api.BootWait
+	shells       *shellSessions
+	files        *fileTransfers
+	instructions *runningInstructions
+	// The user the agent runs as, set by Start
+	username string
+
+	mu sync.Mutex
+	// Replaced each time the agent reconnects
+	agentClient api.AgentAPIClient
 }
 
 func New(opts ...AgentOption) *Agent {
-	options := AgentOptions{}
+	options := AgentOptions{
+		reconnectDelay:    time.Second,
+		maxReconnectDelay: 30 * time.Second,
+		cloudInitReader:   host.NewCloudInitReader(),
+	}
 	options.Apply(opts...)
 	return &Agent{
-		options: options,
+		options:      options,
+		shells:       newShellSessions(),
+		files:        newFileTransfers(),
+		instructions: newRunningInstructions(),
 	}
 }
 
@@ -98,79 +185,324 @@ func (a *Agent) Start(ctx context.Context) error {
 			creds = credentials.NewTLS(&tls.Config{})
 		}
 	}
-	logrus.Info("Connecting to relay at ", a.options.relayAddress)
+	currentUser, err := user.Current()
+	if err != nil {
+		return err
+	}
+	a.username = currentUser.Username
+	extraKeys, err := a.extraAuthorizedKeys()
+	if err != nil {
+		return err
+	}
+	if _, err := a.collectLabels(); err != nil {
+		return err
+	}
+	if a.options.metadataCollector != nil {
+		// Instance metadata does not change, so it is only collected once
+		md, err := a.options.metadataCollector.Collect(ctx)
+		if err != nil {
+			logrus.WithError(err).Info("Cloud instance metadata not available")
+		} else {
+			logrus.Infof("Running on %s instance %s", md.Provider, md.InstanceID)
+			a.cloud = md
+		}
+	}
+	if len(a.options.bootConditions) > 0 {
+		a.bootWait, err = a.waitForBoot(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	// The dial does not block; connection failures are handled by retrying
+	// the stream below.
 	cc, err := grpc.DialContext(ctx, a.options.relayAddress,
 		grpc.WithTransportCredentials(creds),
-		grpc.WithBlock(),
 	)
 	if err != nil {
 		return err
 	}
 	defer cc.Close()
-	logrus.Info("Connected")
 	a.relayClient = api.NewRelayClient(cc)
-	return a.announce(ctx)
+
+	// The timer is shared across reconnects. Time spent disconnected counts
+	// towards the timeout, and instructions which are still running when the
+	// connection is lost keep the agent alive until they complete.
+	a.sharedTimer = util.NewSharedTimer(a.options.timeout)
+	backoff := util.Backoff{
+		Initial: a.options.reconnectDelay,
+		Max:     a.options.maxReconnectDelay,
+	}
+	for {
+		logrus.Info("Connecting to relay at ", a.options.relayAddress)
+		announced, err := a.announce(ctx, extraKeys)
+		if err == nil {
+			logrus.Infof("No commands received within %s, exiting", a.options.timeout)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if announced {
+			backoff.Reset()
+		}
+		delay := backoff.Next()
+		logrus.WithError(err).Warnf("Lost connection to relay, reconnecting in %s", delay.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-a.sharedTimer.C():
+			logrus.Infof("No commands received within %s, exiting", a.options.timeout)
+			return nil
+		case <-time.After(delay):
+		}
+	}
 }
 
-func (a *Agent) announce(ctx context.Context) error {
-	logrus.Infof("Announcing to relay")
+func (a *Agent) extraAuthorizedKeys() ([]*api.AuthorizedKey, error) {
 	extraKeys := []*api.AuthorizedKey{}
 	for _, extraKey := range a.options.extraAuthorizedKeys {
 		key, comment, options, _, err := ssh.ParseAuthorizedKey([]byte(extraKey))
 		if err != nil {
-			return err
-		}
-		currentUser, err := user.Current()
-		if err != nil {
-			return err
+			return nil, err
 		}
 		extraKeys = append(extraKeys, &api.AuthorizedKey{
-			User:        currentUser.Name,
+			User:        a.username,
 			Type:        key.Type(),
 			Fingerprint: ssh.FingerprintSHA256(key),
 			Comment:     comment,
 			Options:     options,
 		})
 	}
+	return extraKeys, nil
+}
+
+// collectLabels merges labels from the labels file, the environment, and the
+// agent options, in increasing order of precedence.
+func (a *Agent) collectLabels() (map[string]string, error) {
+	labels := map[string]string{}
+	if a.options.labelsFile != "" {
+		fileLabels, err := host.GetLabelsFromFile(a.options.labelsFile)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range fileLabels {
+			labels[k] = v
+		}
+	}
+	envLabels, err := host.GetLabelsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range envLabels {
+		labels[k] = v
+	}
+	for k, v := range a.options.labels {
+		labels[k] = v
+	}
+	return labels, nil
+}
+
+// buildAnnouncement describes the host as it is now. The preferred host key
+// is sent in wire format, which is what the relay parses to obtain the agent's
+// fingerprint (see api.Announcement.Fingerprint).
+func (a *Agent) buildAnnouncement(ctx context.Context, extraKeys []*api.AuthorizedKey) (*api.Announcement, error) {
+	labels, err := a.collectLabels()
+	if err != nil {
+		return nil, err
+	}
 	announcement := &api.Announcement{
 		Uname:                  host.GetUnameInfo(),
 		Network:                host.GetNetworkInfo(),
-		PreferredHostPublicKey: ssh.MarshalAuthorizedKey(host.GetPreferredHostPublicKey()),
+		PreferredHostPublicKey: host.GetPreferredHostPublicKey().Marshal(),
 		AuthorizedKeys:         append(host.GetAuthorizedKeys(), extraKeys...),
+		Labels:                 labels,
+		Cloud:                  a.cloud,
+		BootWait:               a.bootWait,
+		AgentUser:              a.username,
+	}
+	if a.options.cloudInitReader != nil {
+		announcement.CloudInit = a.options.cloudInitReader.Status(ctx)
+		logrus.Infof("cloud-init status: %s", announcement.CloudInit.State)
+	}
+	return announcement, nil
+}
+
+// announce opens a stream to the relay and announces the agent, then serves
+// instructions until the stream is lost or the shared timer expires. It
+// returns a nil error only if the timer expired, and reports whether the
+// announcement was accepted before the stream was lost.
+func (a *Agent) announce(ctx context.Context, extraKeys []*api.AuthorizedKey) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	announcement, err := a.buildAnnouncement(ctx, extraKeys)
+	if err != nil {
+		return false, err
 	}
 	stream, err := a.relayClient.AgentStream(ctx)
 	if err != nil {
-		return err
+		return false, err
 	}
 	ts := totem.NewServer(stream)
 	api.RegisterInstructionServer(ts, a)
-	clientConn, _ := ts.Serve()
-	apiClient := api.NewAgentAPIClient(clientConn)
-	_, err = apiClient.Announce(ctx, announcement)
-	if err != nil {
-		return err
+	clientConn, errC := ts.Serve()
+	agentClient := api.NewAgentAPIClient(clientConn)
+	a.mu.Lock()
+	a.agentClient = agentClient
+	a.mu.Unlock()
+
+	logrus.Infof("Announcing to relay")
+	if _, err := agentClient.Announce(ctx, announcement); err != nil {
+		return false, err
 	}
 	logrus.Info("Successfully announced to relay")
-	a.sharedTimer = util.NewSharedTimer(a.options.timeout)
+	if announcement.CloudInit != nil && !announcement.CloudInit.Finished() {
+		go a.watchCloudInit(ctx, agentClient, announcement.CloudInit)
+	}
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return true, ctx.Err()
 	case <-a.sharedTimer.C():
-		logrus.Infof("No commands received within %s, exiting", a.options.timeout)
-		return nil
+		// Close the stream gracefully so the relay knows the agent is exiting,
+		// and give it a moment to finish before the connection is torn down.
+		stream.CloseSend()
+		totem.WaitErrOrTimeout(errC, 1*time.Second)
+		return true, nil
+	case err := <-errC:
+		if err == nil {
+			err = io.EOF
+		}
+		return true, err
 	}
 }
 
+// watchCloudInit polls the status of cloud-init until it has finished,
+// sending a status update to the relay whenever it changes.
+func (a *Agent) watchCloudInit(ctx context.Context, client api.AgentAPIClient, last *api.CloudInitStatus) {
+	ticker := time.NewTicker(cloudInitPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		current := a.options.cloudInitReader.Status(ctx)
+		if current.State == last.State {
+			continue
+		}
+		logrus.Infof("cloud-init status changed: %s", current.State)
+		if _, err := client.UpdateStatus(ctx, &api.StatusUpdate{
+			CloudInit: current,
+		}); err != nil {
+			logrus.WithError(err).Warn("Failed to send status update")
+			return
+		}
+		if current.Finished() {
+			return
+		}
+		last = current
+	}
+}
+
+// client returns the client for the current stream to the relay.
+func (a *Agent) client() api.AgentAPIClient {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.agentClient
+}
+
 func (a *Agent) Command(ctx context.Context, req *api.CommandRequest) (*api.CommandResponse, error) {
+	if err := a.authorizeCommand(req.Meta, req.Command); err != nil {
+		return nil, err
+	}
 	logrus.Infof("Executing command %s", req.Command)
 	a.sharedTimer.Block()
 	defer a.sharedTimer.Unblock()
-	return RunCommand(req.Command)
+	ctx, done, err := a.instructions.start(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return RunCommand(ctx, req.Command)
 }
 
 func (a *Agent) Script(ctx context.Context, req *api.ScriptRequest) (*api.ScriptResponse, error) {
+	if err := a.authorizeScript(req.Meta, req.Script); err != nil {
+		return nil, err
+	}
 	logrus.Infof("Executing script %s", req.Script)
 	a.sharedTimer.Block()
 	defer a.sharedTimer.Unblock()
-	return RunScript(req.Script)
+	ctx, done, err := a.instructions.start(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return RunScript(ctx, req.Script)
+}
+
+func (a *Agent) CommandStream(ctx context.Context, req *api.CommandRequest) (*emptypb.Empty, error) {
+	if err := a.authorizeCommand(req.Meta, req.Command); err != nil {
+		return nil, err
+	}
+	logrus.Infof("Executing command %s (streaming)", req.Command)
+	a.sharedTimer.Block()
+	defer a.sharedTimer.Unblock()
+	runCtx, done, err := a.instructions.start(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	out := newOutputStream(ctx, a.client(), req.Meta.GetStreamID())
+	exit, err := StreamCommand(runCtx, req.Command,
+		out.Writer(api.OutputSource_Stdout), out.Writer(api.OutputSource_Stderr))
+	if err != nil {
+		return nil, err
+	}
+	if err := out.Exit(exit); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *Agent) ScriptStream(ctx context.Context, req *api.ScriptRequest) (*emptypb.Empty, error) {
+	if err := a.authorizeScript(req.Meta, req.Script); err != nil {
+		return nil, err
+	}
+	logrus.Infof("Executing script %s (streaming)", req.Script)
+	a.sharedTimer.Block()
+	defer a.sharedTimer.Unblock()
+	runCtx, done, err := a.instructions.start(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	out := newOutputStream(ctx, a.client(), req.Meta.GetStreamID())
+	exit, err := StreamScript(runCtx, req.Script,
+		out.Writer(api.OutputSource_Stdout), out.Writer(api.OutputSource_Stderr))
+	if err != nil {
+		return nil, err
+	}
+	if err := out.Exit(exit); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *Agent) Cancel(ctx context.Context, req *api.CancelRequest) (*emptypb.Empty, error) {
+	id := req.Meta.GetInstructionID()
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing instruction ID")
+	}
+	// Instructions are tracked by the client which started them, so only
+	// that client can find and cancel them
+	if req.Meta.GetClientFingerprint() == "" {
+		return nil, status.Error(codes.PermissionDenied, "missing client fingerprint")
+	}
+	logrus.Infof("Canceling instruction %s", id)
+	if err := a.instructions.cancel(req.Meta); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
 }
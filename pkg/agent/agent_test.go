@@ -0,0 +1,35 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/kralicky/post-init/pkg/host"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+)
+
+var _ = Describe("Agent Options", func() {
+	It("should not collect cloud metadata unless enabled", func() {
+		Expect(New().options.metadataCollector).To(BeNil())
+
+		collector := host.NewMetadataCollector()
+		a := New(WithMetadataCollector(collector))
+		Expect(a.options.metadataCollector).To(BeIdenticalTo(collector))
+	})
+})
+
+var _ = Describe("Announcement", func() {
+	It("should have the fingerprint of the preferred host key", func() {
+		if keys, _ := filepath.Glob("/etc/ssh/ssh_host_*_key.pub"); len(keys) == 0 {
+			Skip("no host keys")
+		}
+		a, _, _ := newTestAgent()
+		an, err := a.buildAnnouncement(context.Background(), nil)
+		Expect(err).NotTo(HaveOccurred())
+		fp, err := an.Fingerprint()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fp).To(Equal(ssh.FingerprintSHA256(host.GetPreferredHostPublicKey())))
+	})
+})
@@ -0,0 +1,150 @@
+package agent
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/kralicky/post-init/pkg/host"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The relay authorizes instructions against the keys the agent announced, so
+// the checks here are a second line of defense. They are made against the
+// keys currently on the host, so that keys removed since the agent announced
+// are no longer accepted.
+//
+// The options of the client's authorized key are only enforced here, since
+// the relay does not know the current time on the host. The same goes for
+// client certificates authorized by cert-authority entries, which the relay
+// only checks when the client connects. They have the same
+// meaning as in sshd(8), with these differences:
+//  - command= replaces commands and scripts, and rejects shells and file
+//    transfers. The original command line is only passed to the forced
+//    command (in SSH_ORIGINAL_COMMAND) for commands.
+//  - from= only matches address patterns, since hostnames are not known.
+//  - no-port-forwarding has no effect, since agents do not forward ports.
+
+// authorizeCommand checks that the client which sent the command is
+// authorized on the host, and sets the user it runs as (see
+// api.Announcement.RunAsUser). If the client's key has a forced command, it
+// replaces the command.
+func (a *Agent) authorizeCommand(meta *api.InstructionMeta, cmd *api.Command) error {
+	runAs, opts, err := a.runAsUser(meta, cmd.GetUser(), cmd.GetGroup())
+	if err != nil {
+		return err
+	}
+	cmd.User = runAs
+	if opts.Command != "" {
+		logrus.Infof("Running forced command instead of %s", cmd.Command)
+		cmd.Env = append(cmd.Env, "SSH_ORIGINAL_COMMAND="+strings.Join(append([]string{cmd.Command}, cmd.Args...), " "))
+		cmd.Command = "/bin/sh"
+		cmd.Args = []string{"-c", opts.Command}
+	}
+	return nil
+}
+
+// authorizeScript is like authorizeCommand, for scripts.
+func (a *Agent) authorizeScript(meta *api.InstructionMeta, script *api.Script) error {
+	runAs, opts, err := a.runAsUser(meta, script.GetUser(), script.GetGroup())
+	if err != nil {
+		return err
+	}
+	script.User = runAs
+	if opts.Command != "" {
+		logrus.Info("Running forced command instead of script")
+		script.Interpreter = "/bin/sh"
+		script.Script = opts.Command
+		script.Args = nil
+	}
+	return nil
+}
+
+// runAsUser returns the user an instruction runs as, and the options of the
+// authorized key which grants access.
+func (a *Agent) runAsUser(meta *api.InstructionMeta, user, group string) (string, *api.KeyOptions, error) {
+	an, client, err := a.currentAuthorization(meta)
+	if err != nil {
+		return "", nil, err
+	}
+	user, key, err := an.AuthorizeKey(client, user, group)
+	if err != nil {
+		return "", nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	opts, err := key.ClientOptions(client)
+	if err != nil {
+		return "", nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return user, opts, nil
+}
+
+// authorizeAgentUser checks that the client which sent an instruction is
+// authorized for the agent's own user, for instructions which can only run
+// as that user. These are interactive, so they are rejected if the key has
+// a forced command, or, if pty is true, if it has the no-pty option.
+func (a *Agent) authorizeAgentUser(meta *api.InstructionMeta, pty bool) error {
+	an, client, err := a.currentAuthorization(meta)
+	if err != nil {
+		return err
+	}
+	key, err := an.AuthorizeAgentUser(client)
+	if err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	opts, err := key.ClientOptions(client)
+	if err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	switch {
+	case opts.Command != "":
+		return status.Error(codes.PermissionDenied, "client key is restricted to a forced command")
+	case pty && opts.NoPty:
+		return status.Error(codes.PermissionDenied, "client key does not allow a pty")
+	}
+	return nil
+}
+
+// currentAuthorization returns an announcement containing only the fields
+// used to authorize instructions, and the key of the client which sent the
+// instruction. Entries authorizing the client which it cannot use at this
+// time, according to their options or its certificate, are left out.
+func (a *Agent) currentAuthorization(meta *api.InstructionMeta) (*api.Announcement, *api.ClientKey, error) {
+	if meta.GetClientFingerprint() == "" {
+		return nil, nil, status.Error(codes.PermissionDenied, "missing client fingerprint")
+	}
+	client, err := meta.ClientKey()
+	if err != nil {
+		return nil, nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	extraKeys, err := a.extraAuthorizedKeys()
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+	now := time.Now()
+	keys := []*api.AuthorizedKey{}
+	var rejected error
+	accepted := false
+	for _, key := range append(host.GetAuthorizedKeys(), extraKeys...) {
+		if key.Authorizes(client) {
+			opts, err := key.ClientOptions(client)
+			if err == nil {
+				err = opts.Check(meta.ClientAddress, now)
+			}
+			if err != nil {
+				rejected = err
+				continue
+			}
+			accepted = true
+		}
+		keys = append(keys, key)
+	}
+	if !accepted && rejected != nil {
+		return nil, nil, status.Error(codes.PermissionDenied, rejected.Error())
+	}
+	return &api.Announcement{
+		AuthorizedKeys: keys,
+		AgentUser:      a.username,
+	}, client, nil
+}
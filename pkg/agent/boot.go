@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/kralicky/post-init/pkg/host"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// How often boot conditions are checked
+	bootPollInterval = 5 * time.Second
+	// How often progress is logged while waiting for boot conditions
+	bootReportInterval = time.Minute
+)
+
+// waitForBoot blocks until any of the configured boot conditions is met, or
+// the boot timeout expires. Timing out is not an error; the agent announces
+// anyway, and the announcement records that the wait timed out.
+func (a *Agent) waitForBoot(ctx context.Context) (*api.BootWait, error) {
+	conditions := a.options.bootConditions
+	start := time.Now()
+	var timeout <-chan time.Time
+	if a.options.bootTimeout > 0 {
+		timer := time.NewTimer(a.options.bootTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+		logrus.Infof("Waiting up to %s for: %s", a.options.bootTimeout, describeConditions(conditions))
+	} else {
+		logrus.Infof("Waiting for: %s", describeConditions(conditions))
+	}
+	poll := time.NewTicker(bootPollInterval)
+	defer poll.Stop()
+	report := time.NewTicker(bootReportInterval)
+	defer report.Stop()
+	for {
+		for _, c := range conditions {
+			ok, err := c.Check(ctx)
+			if err != nil {
+				logrus.WithError(err).Debugf("Failed to check %s", c)
+				continue
+			}
+			if ok {
+				elapsed := time.Since(start)
+				logrus.Infof("Finished waiting after %s: %s", elapsed.Round(time.Second), c)
+				return &api.BootWait{
+					Duration:  durationpb.New(elapsed),
+					Condition: c.String(),
+				}, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeout:
+			elapsed := time.Since(start)
+			logrus.Warnf("Timed out after %s waiting for: %s; announcing anyway",
+				elapsed.Round(time.Second), describeConditions(conditions))
+			return &api.BootWait{
+				Duration: durationpb.New(elapsed),
+				TimedOut: true,
+			}, nil
+		case <-report.C:
+			logrus.Infof("Still waiting after %s for: %s",
+				time.Since(start).Round(time.Second), describeConditions(conditions))
+		case <-poll.C:
+		}
+	}
+}
+
+func describeConditions(conditions []host.BootCondition) string {
+	names := make([]string, len(conditions))
+	for i, c := range conditions {
+		names[i] = c.String()
+	}
+	return strings.Join(names, ", ")
+}
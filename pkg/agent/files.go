@@ -0,0 +1,379 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/kralicky/post-init/pkg/util"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	fileChunkSize = 1024 * 1024
+	// Transfers which have not received a request in this amount of time are
+	// cleaned up.
+	fileTransferIdleTimeout = 5 * time.Minute
+)
+
+type fileTransfer struct {
+	mu     sync.Mutex
+	info   *api.FileInfo
+	file   *os.File
+	hash   hash.Hash
+	offset int64
+	// Whether file is a temporary file which should be removed when the
+	// transfer ends
+	temp  bool
+	timer *time.Timer
+}
+
+func (t *fileTransfer) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	t.file.Close()
+	if t.temp {
+		os.Remove(t.file.Name())
+	}
+}
+
+// write appends a chunk of data to the file being received.
+func (t *fileTransfer) write(offset int64, data []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if offset != t.offset {
+		return status.Errorf(codes.InvalidArgument, "expected offset %d, got %d", t.offset, offset)
+	}
+	if _, err := t.file.Write(data); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	t.hash.Write(data)
+	t.offset += int64(len(data))
+	return nil
+}
+
+// commit verifies the contents of the received file and moves it to its
+// destination.
+func (t *fileTransfer) commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.offset != t.info.Size {
+		return status.Errorf(codes.DataLoss, "expected %d bytes, got %d", t.info.Size, t.offset)
+	}
+	if sum := hex.EncodeToString(t.hash.Sum(nil)); sum != t.info.Checksum {
+		return status.Error(codes.DataLoss, "checksum mismatch")
+	}
+	uid, gid, err := lookupOwner(t.info.Owner, t.info.Group)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if t.info.Directory {
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := util.ExtractTar(t.file, t.info.Path); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if uid == -1 && gid == -1 {
+			return nil
+		}
+		err := filepath.WalkDir(t.info.Path, func(path string, _ fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			return os.Lchown(path, uid, gid)
+		})
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		return nil
+	}
+	if err := t.file.Chmod(fs.FileMode(t.info.Mode).Perm()); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if err := t.file.Chown(uid, gid); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if err := os.Rename(t.file.Name(), t.info.Path); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	t.temp = false
+	return nil
+}
+
+// read reads the chunk of the file being sent starting at offset.
+func (t *fileTransfer) read(offset int64) ([]byte, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	buf := make([]byte, fileChunkSize)
+	n, err := t.file.ReadAt(buf, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, false, status.Error(codes.Internal, err.Error())
+	}
+	return buf[:n], offset+int64(n) >= t.info.Size, nil
+}
+
+// fileTransfers keeps track of in-progress file transfers by stream ID.
+type fileTransfers struct {
+	mu        sync.Mutex
+	transfers map[string]*fileTransfer
+}
+
+func newFileTransfers() *fileTransfers {
+	return &fileTransfers{
+		transfers: make(map[string]*fileTransfer),
+	}
+}
+
+func (ft *fileTransfers) add(id string, t *fileTransfer) error {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	if _, ok := ft.transfers[id]; ok {
+		return status.Error(codes.AlreadyExists, "transfer already exists")
+	}
+	t.timer = time.AfterFunc(fileTransferIdleTimeout, func() {
+		logrus.Warnf("File transfer for %s timed out", t.info.Path)
+		ft.remove(id)
+	})
+	ft.transfers[id] = t
+	return nil
+}
+
+func (ft *fileTransfers) get(id string) (*fileTransfer, error) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	t, ok := ft.transfers[id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "transfer not found")
+	}
+	t.timer.Reset(fileTransferIdleTimeout)
+	return t, nil
+}
+
+func (ft *fileTransfers) remove(id string) {
+	ft.mu.Lock()
+	t, ok := ft.transfers[id]
+	delete(ft.transfers, id)
+	ft.mu.Unlock()
+	if ok {
+		t.close()
+	}
+}
+
+func (a *Agent) PutFile(ctx context.Context, req *api.PutFileRequest) (*emptypb.Empty, error) {
+	id := req.Meta.GetStreamID()
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing stream ID")
+	}
+	a.sharedTimer.Block()
+	defer a.sharedTimer.Unblock()
+
+	var t *fileTransfer
+	var err error
+	if req.Info != nil {
+		logrus.Infof("Receiving file %s", req.Info.Path)
+		t, err = a.startTransfer(req.Meta, func() (*fileTransfer, error) {
+			return newPutTransfer(req.Info)
+		})
+	} else {
+		t, err = a.files.get(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := t.write(req.Offset, req.Data); err != nil {
+		a.files.remove(id)
+		return nil, err
+	}
+	if req.Done {
+		defer a.files.remove(id)
+		if err := t.commit(); err != nil {
+			return nil, err
+		}
+		logrus.Infof("Wrote %s", t.info.Path)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *Agent) GetFile(ctx context.Context, req *api.GetFileRequest) (*api.GetFileResponse, error) {
+	id := req.Meta.GetStreamID()
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing stream ID")
+	}
+	a.sharedTimer.Block()
+	defer a.sharedTimer.Unblock()
+
+	resp := &api.GetFileResponse{}
+	var t *fileTransfer
+	var err error
+	if req.Offset == 0 && req.Path != "" {
+		logrus.Infof("Sending file %s", req.Path)
+		t, err = a.startTransfer(req.Meta, func() (*fileTransfer, error) {
+			return newGetTransfer(req.Path)
+		})
+	} else {
+		t, err = a.files.get(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if req.Path != "" {
+		resp.Info = t.info
+	}
+	resp.Data, resp.EOF, err = t.read(req.Offset)
+	if err != nil || resp.EOF {
+		a.files.remove(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// startTransfer starts a new transfer for the stream identified in meta.
+// Only the start of a transfer is authorized, since later chunks must belong
+// to a transfer which has already been started.
+func (a *Agent) startTransfer(meta *api.InstructionMeta, newTransfer func() (*fileTransfer, error)) (*fileTransfer, error) {
+	if err := a.authorizeAgentUser(meta, false); err != nil {
+		return nil, err
+	}
+	id := meta.GetStreamID()
+	t, err := newTransfer()
+	if err != nil {
+		return nil, err
+	}
+	if err := a.files.add(id, t); err != nil {
+		t.close()
+		return nil, err
+	}
+	return t, nil
+}
+
+func newPutTransfer(info *api.FileInfo) (*fileTransfer, error) {
+	if !filepath.IsAbs(info.Path) {
+		return nil, status.Error(codes.InvalidArgument, "path must be absolute")
+	}
+	// Regular files are written next to their destination so they can be
+	// moved into place atomically.
+	dir := filepath.Dir(info.Path)
+	if info.Directory {
+		dir = ""
+	}
+	f, err := os.CreateTemp(dir, ".post-init-*")
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &fileTransfer{
+		info: info,
+		file: f,
+		hash: sha256.New(),
+		temp: true,
+	}, nil
+}
+
+func newGetTransfer(path string) (*fileTransfer, error) {
+	if !filepath.IsAbs(path) {
+		return nil, status.Error(codes.InvalidArgument, "path must be absolute")
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	info := &api.FileInfo{
+		Path:      path,
+		Mode:      uint32(stat.Mode().Perm()),
+		Directory: stat.IsDir(),
+	}
+	info.Owner, info.Group = ownerNames(stat)
+
+	var f *os.File
+	temp := false
+	if stat.IsDir() {
+		f, err = os.CreateTemp("", "post-init-*")
+		if err == nil {
+			temp = true
+			err = util.WriteTar(f, path)
+		}
+	} else if stat.Mode().IsRegular() {
+		f, err = os.Open(path)
+	} else {
+		return nil, status.Error(codes.InvalidArgument, "not a regular file or directory")
+	}
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err == nil {
+		info.Size, info.Checksum, err = util.Checksum(f)
+	}
+	if err != nil {
+		if f != nil {
+			f.Close()
+			if temp {
+				os.Remove(f.Name())
+			}
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &fileTransfer{
+		info: info,
+		file: f,
+		temp: temp,
+	}, nil
+}
+
+// lookupOwner returns the uid and gid for the given user and group names.
+// If a name is empty, -1 is returned in its place.
+func lookupOwner(owner, group string) (int, int, error) {
+	uid, gid := -1, -1
+	if owner != "" {
+		u, err := user.Lookup(owner)
+		if err != nil {
+			return 0, 0, err
+		}
+		uid, _ = strconv.Atoi(u.Uid)
+	}
+	if group != "" {
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			return 0, 0, err
+		}
+		gid, _ = strconv.Atoi(g.Gid)
+	}
+	return uid, gid, nil
+}
+
+func ownerNames(info fs.FileInfo) (string, string) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", ""
+	}
+	var owner, group string
+	if u, err := user.LookupId(strconv.Itoa(int(st.Uid))); err == nil {
+		owner = u.Username
+	}
+	if g, err := user.LookupGroupId(strconv.Itoa(int(st.Gid))); err == nil {
+		group = g.Name
+	}
+	return owner, group
+}
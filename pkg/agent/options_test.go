@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/kralicky/post-init/pkg/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// currentUmask returns the agent's umask.
+func currentUmask() int {
+	umask := syscall.Umask(0)
+	syscall.Umask(umask)
+	return umask
+}
+
+var _ = Describe("Process options", func() {
+	umask := func(mask uint32) *uint32 {
+		return &mask
+	}
+
+	It("should set the umask of the process only", func() {
+		before := currentUmask()
+		resp, err := RunCommand(context.Background(), &api.Command{
+			Command: "sh",
+			Args:    []string{"-c", `umask; echo "$0 $1"`, "a b", "c"},
+			Umask:   umask(0027),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Stdout).To(Equal("0027\na b c\n"))
+		Expect(currentUmask()).To(Equal(before))
+
+		scriptResp, err := RunScript(context.Background(), &api.Script{
+			Interpreter: "/bin/sh",
+			Script:      "umask",
+			Umask:       umask(0077),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scriptResp.Stdout).To(Equal("0077\n"))
+	})
+	It("should reject invalid umasks and missing commands", func() {
+		_, err := RunCommand(context.Background(), &api.Command{
+			Command: "true",
+			Umask:   umask(01000),
+		})
+		Expect(err).To(HaveOccurred())
+		_, err = RunCommand(context.Background(), &api.Command{
+			Command: "post-init-missing-command",
+			Umask:   umask(0022),
+		})
+		Expect(err).To(HaveOccurred())
+	})
+	It("should run in the working directory", func() {
+		dir, err := filepath.EvalSymlinks(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := RunCommand(context.Background(), &api.Command{
+			Command:    "pwd",
+			WorkingDir: dir,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Stdout).To(Equal(dir + "\n"))
+
+		_, err = RunCommand(context.Background(), &api.Command{
+			Command:    "pwd",
+			WorkingDir: "relative",
+		})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+	It("should write stdin to the process", func() {
+		resp, err := RunCommand(context.Background(), &api.Command{
+			Command: "cat",
+			Stdin:   []byte("hello\n"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Stdout).To(Equal("hello\n"))
+
+		// More than fits in a pipe's buffer
+		large := bytes.Repeat([]byte("x"), 1000000)
+		scriptResp, err := RunScript(context.Background(), &api.Script{
+			Interpreter: "/bin/sh",
+			Script:      "wc -c",
+			Stdin:       large,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.TrimSpace(scriptResp.Stdout)).To(Equal("1000000"))
+
+		// Processes which do not read stdin are not blocked by it
+		resp, err = RunCommand(context.Background(), &api.Command{
+			Command: "true",
+			Stdin:   large,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.ExitCode).To(BeEquivalentTo(0))
+	})
+	It("should reject unknown users and groups", func() {
+		_, err := RunCommand(context.Background(), &api.Command{
+			Command: "true",
+			User:    "post-init-missing-user",
+		})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		_, err = RunCommand(context.Background(), &api.Command{
+			Command: "true",
+			Group:   "post-init-missing-group",
+		})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+	It("should run as the agent's own user without root", func() {
+		current, err := user.Current()
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := RunCommand(context.Background(), &api.Command{
+			Command: "id",
+			Args:    []string{"-u"},
+			User:    current.Username,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Stdout).To(Equal(current.Uid + "\n"))
+	})
+	It("should not run as other users without root", func() {
+		if os.Geteuid() == 0 {
+			Skip("running as root")
+		}
+		_, err := RunCommand(context.Background(), &api.Command{
+			Command: "true",
+			User:    "root",
+		})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	})
+	When("running as root", func() {
+		var nobody *user.User
+		BeforeEach(func() {
+			if os.Geteuid() != 0 {
+				Skip("not running as root")
+			}
+			var err error
+			nobody, err = user.Lookup("nobody")
+			if err != nil {
+				Skip("no nobody user")
+			}
+		})
+
+		It("should run commands as the user", func() {
+			for _, name := range []string{nobody.Username, nobody.Uid} {
+				resp, err := RunCommand(context.Background(), &api.Command{
+					Command: "sh",
+					Args:    []string{"-c", `echo "$(id -u) $(id -g) $USER $HOME"`},
+					User:    name,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Stdout).To(Equal(strings.Join([]string{
+					nobody.Uid, nobody.Gid, nobody.Username, nobody.HomeDir,
+				}, " ") + "\n"))
+			}
+		})
+		It("should run commands as the group", func() {
+			resp, err := RunCommand(context.Background(), &api.Command{
+				Command: "sh",
+				Args:    []string{"-c", `echo "$(id -u) $(id -g)"`},
+				User:    nobody.Username,
+				Group:   "0",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Stdout).To(Equal(nobody.Uid + " 0\n"))
+
+			resp, err = RunCommand(context.Background(), &api.Command{
+				Command: "id",
+				Args:    []string{"-g"},
+				Group:   nobody.Gid,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Stdout).To(Equal(nobody.Gid + "\n"))
+		})
+		It("should run scripts as the user", func() {
+			resp, err := RunScript(context.Background(), &api.Script{
+				Interpreter: "/bin/sh",
+				Script:      "id -u",
+				User:        nobody.Username,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.ExitCode).To(BeEquivalentTo(0))
+			Expect(resp.Stdout).To(Equal(nobody.Uid + "\n"))
+		})
+	})
+})
@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// outputStream sends the output of a running instruction back to the relay
+// as an ordered sequence of events tagged with the request's stream ID.
+type outputStream struct {
+	ctx    context.Context
+	client api.AgentAPIClient
+	id     string
+
+	mu  sync.Mutex
+	seq uint64
+	err error
+}
+
+func newOutputStream(ctx context.Context, client api.AgentAPIClient, id string) *outputStream {
+	return &outputStream{
+		ctx:    ctx,
+		client: client,
+		id:     id,
+	}
+}
+
+func (s *outputStream) send(ev *api.OutputEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.seq++
+	ev.StreamID = s.id
+	ev.Sequence = s.seq
+	ev.Timestamp = timestamppb.Now()
+	if _, err := s.client.WriteOutput(s.ctx, ev); err != nil {
+		logrus.Errorf("Failed to write output for stream %s: %v", s.id, err)
+		s.err = err
+	}
+	return s.err
+}
+
+// Writer returns an io.Writer which sends everything written to it as
+// output chunks from the given source.
+func (s *outputStream) Writer(source api.OutputSource) io.Writer {
+	return &outputWriter{
+		stream: s,
+		source: source,
+	}
+}
+
+// Exit sends the final event in the stream containing the exit status. If any
+// earlier event could not be sent, that error is returned instead.
+func (s *outputStream) Exit(status *api.ExitStatus) error {
+	return s.send(&api.OutputEvent{
+		Event: &api.OutputEvent_Exit{
+			Exit: status,
+		},
+	})
+}
+
+type outputWriter struct {
+	stream *outputStream
+	source api.OutputSource
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	// Errors are reported by Exit. If output can no longer be sent, it is
+	// discarded so that the process does not block on a full pipe.
+	w.stream.send(&api.OutputEvent{
+		Event: &api.OutputEvent_Output{
+			Output: &api.OutputChunk{
+				Source: w.source,
+				Data:   data,
+			},
+		},
+	})
+	return len(p), nil
+}
@@ -2,35 +2,84 @@ package agent
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/kralicky/post-init/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
-func RunCommand(cmd *api.Command) (*api.CommandResponse, error) {
-	c := exec.Command(cmd.Command, cmd.Args...)
-	c.Env = append(os.Environ(), cmd.Env...)
+func RunCommand(ctx context.Context, cmd *api.Command) (*api.CommandResponse, error) {
 	stdoutBuf := &bytes.Buffer{}
 	stderrBuf := &bytes.Buffer{}
-	c.Stdin = nil
-	c.Stdout = stdoutBuf
-	c.Stderr = stderrBuf
-	err := c.Run()
+	exit, err := StreamCommand(ctx, cmd, stdoutBuf, stderrBuf)
 	if err != nil {
-		// Do not treat non-zero return code (ExitError) as an error here
-		if _, ok := err.(*exec.ExitError); !ok {
-			return nil, err
-		}
+		return nil, err
 	}
 	return &api.CommandResponse{
-		Stdout:   stdoutBuf.String(),
-		Stderr:   stderrBuf.String(),
-		ExitCode: int32(c.ProcessState.ExitCode()),
+		Stdout:            stdoutBuf.String(),
+		Stderr:            stderrBuf.String(),
+		ExitCode:          exit.ExitCode,
+		Terminated:        exit.Terminated,
+		TerminationReason: exit.TerminationReason,
+	}, nil
+}
+
+func RunScript(ctx context.Context, cmd *api.Script) (*api.ScriptResponse, error) {
+	stdoutBuf := &bytes.Buffer{}
+	stderrBuf := &bytes.Buffer{}
+	exit, err := StreamScript(ctx, cmd, stdoutBuf, stderrBuf)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ScriptResponse{
+		Stdout:            stdoutBuf.String(),
+		Stderr:            stderrBuf.String(),
+		ExitCode:          exit.ExitCode,
+		Terminated:        exit.Terminated,
+		TerminationReason: exit.TerminationReason,
 	}, nil
 }
 
-func RunScript(cmd *api.Script) (*api.ScriptResponse, error) {
+// StreamCommand runs the command, writing its output to stdout and stderr as
+// it is produced. It returns the exit status of the process once it exits.
+// If the command's timeout expires or ctx is canceled first, the process
+// group is killed.
+func StreamCommand(ctx context.Context, cmd *api.Command, stdout, stderr io.Writer) (*api.ExitStatus, error) {
+	c := exec.Command(cmd.Command, cmd.Args...)
+	opts := processOptions{
+		timeout:    cmd.Timeout,
+		user:       cmd.User,
+		group:      cmd.Group,
+		workingDir: cmd.WorkingDir,
+		umask:      cmd.Umask,
+		stdin:      cmd.Stdin,
+	}
+	if err := opts.apply(c); err != nil {
+		return nil, err
+	}
+	c.Env = append(c.Env, cmd.Env...)
+	return run(ctx, c, opts, stdout, stderr)
+}
+
+// StreamScript writes the script to a temporary file and runs it with the
+// given interpreter, writing its output to stdout and stderr as it is
+// produced. It returns the exit status of the process once it exits, and is
+// terminated in the same way as StreamCommand.
+func StreamScript(ctx context.Context, cmd *api.Script, stdout, stderr io.Writer) (*api.ExitStatus, error) {
 	// Write a temporary file with the script
 	f, err := os.CreateTemp("", "post-init-*")
 	if err != nil {
@@ -44,21 +93,351 @@ func RunScript(cmd *api.Script) (*api.ScriptResponse, error) {
 	f.Close()
 
 	c := exec.Command(cmd.Interpreter, append([]string{f.Name()}, cmd.Args...)...)
-	stdoutBuf := &bytes.Buffer{}
-	stderrBuf := &bytes.Buffer{}
-	c.Stdin = nil
-	c.Stdout = stdoutBuf
-	c.Stderr = stderrBuf
-	err = c.Run()
+	opts := processOptions{
+		timeout:    cmd.Timeout,
+		user:       cmd.User,
+		group:      cmd.Group,
+		workingDir: cmd.WorkingDir,
+		umask:      cmd.Umask,
+		stdin:      cmd.Stdin,
+	}
+	if err := opts.apply(c); err != nil {
+		return nil, err
+	}
+	if cred := c.SysProcAttr.Credential; cred != nil {
+		// The script must be readable by the user it runs as
+		if err := os.Chown(f.Name(), int(cred.Uid), int(cred.Gid)); err != nil {
+			return nil, err
+		}
+	}
+	return run(ctx, c, opts, stdout, stderr)
+}
+
+// processOptions holds the fields common to commands and scripts which
+// control how their process is run.
+type processOptions struct {
+	timeout    *durationpb.Duration
+	user       string
+	group      string
+	workingDir string
+	umask      *uint32
+	stdin      []byte
+}
+
+// apply sets up the process's environment, working directory and
+// credentials. Options which can only be applied when the process is started
+// are handled by run.
+func (o processOptions) apply(c *exec.Cmd) error {
+	c.Env = os.Environ()
+	c.SysProcAttr = &syscall.SysProcAttr{}
+	if o.workingDir != "" {
+		if !filepath.IsAbs(o.workingDir) {
+			return status.Error(codes.InvalidArgument, "working directory must be an absolute path")
+		}
+		c.Dir = o.workingDir
+	}
+	if o.timeout != nil && o.timeout.AsDuration() <= 0 {
+		return status.Error(codes.InvalidArgument, "timeout must be positive")
+	}
+	if o.umask != nil && *o.umask > 0777 {
+		return status.Errorf(codes.InvalidArgument, "invalid umask %#o", *o.umask)
+	}
+	if o.user == "" && o.group == "" {
+		return nil
+	}
+	cred, u, err := lookupCredential(o.user, o.group)
+	if err != nil {
+		return err
+	}
+	c.SysProcAttr.Credential = cred
+	if u != nil {
+		c.Env = append(c.Env,
+			"HOME="+u.HomeDir,
+			"USER="+u.Username,
+			"LOGNAME="+u.Username,
+		)
+	}
+	return nil
+}
+
+// lookupCredential resolves the user and group a process should run as. If
+// only the user is given, the user's primary and supplementary groups are
+// used. The returned credential is nil if no change of user or group is
+// needed, and the returned user is nil if only the group was given.
+func lookupCredential(username, groupname string) (*syscall.Credential, *user.User, error) {
+	cred := &syscall.Credential{
+		Uid:         uint32(os.Geteuid()),
+		Gid:         uint32(os.Getegid()),
+		NoSetGroups: true,
+	}
+	var u *user.User
+	if username != "" {
+		var err error
+		u, err = user.Lookup(username)
+		if _, numeric := parseID(username); err != nil && numeric {
+			u, err = user.LookupId(username)
+		}
+		if err != nil {
+			return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		cred.Uid, _ = parseID(u.Uid)
+		cred.Gid, _ = parseID(u.Gid)
+		cred.NoSetGroups = false
+		// Without supplementary groups, the process only loses privileges, so
+		// failing to look them up is not an error.
+		groupIds, _ := u.GroupIds()
+		for _, id := range groupIds {
+			if gid, ok := parseID(id); ok {
+				cred.Groups = append(cred.Groups, gid)
+			}
+		}
+	}
+	if groupname != "" {
+		g, err := user.LookupGroup(groupname)
+		if _, numeric := parseID(groupname); err != nil && numeric {
+			g, err = user.LookupGroupId(groupname)
+		}
+		if err != nil {
+			return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		cred.Gid, _ = parseID(g.Gid)
+	}
+	if os.Geteuid() != 0 {
+		if cred.Uid != uint32(os.Geteuid()) || cred.Gid != uint32(os.Getegid()) {
+			return nil, nil, status.Error(codes.PermissionDenied, "the agent must run as root to run as another user or group")
+		}
+		return nil, u, nil
+	}
+	return cred, u, nil
+}
+
+func parseID(id string) (uint32, bool) {
+	n, err := strconv.ParseUint(id, 10, 32)
+	return uint32(n), err == nil
+}
+
+// withUmask makes the process set the given umask before running its
+// command. The umask is shared by all threads of the agent, so it cannot be
+// changed around starting the process without affecting files the agent
+// creates at the same time. Instead, the command is run by a shell which
+// sets the umask and replaces itself with the command.
+func withUmask(c *exec.Cmd, umask uint32) error {
+	path := c.Path
+	if !strings.Contains(path, "/") {
+		// Report a missing command the same way as exec.Cmd does
+		var err error
+		if path, err = exec.LookPath(path); err != nil {
+			return err
+		}
+	}
+	c.Args = append([]string{
+		"/bin/sh", "-c", fmt.Sprintf(`umask %04o && exec "$0" "$@"`, umask), path,
+	}, c.Args[1:]...)
+	c.Path = "/bin/sh"
+	return nil
+}
+
+func run(
+	ctx context.Context,
+	c *exec.Cmd,
+	opts processOptions,
+	stdout, stderr io.Writer,
+) (*api.ExitStatus, error) {
+	timeout := opts.timeout
+	if timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout.AsDuration())
+		defer cancel()
+	}
+	p := &pipes{}
+	defer p.close()
+	var err error
+	if len(opts.stdin) > 0 {
+		if c.Stdin, err = p.input(opts.stdin); err != nil {
+			return nil, err
+		}
+	}
+	if c.Stdout, err = p.output(stdout); err != nil {
+		return nil, err
+	}
+	if c.Stderr, err = p.output(stderr); err != nil {
+		return nil, err
+	}
+	// Run the process in its own process group, so that it can be terminated
+	// along with any children it started.
+	c.SysProcAttr.Setpgid = true
+	if opts.umask != nil {
+		if err := withUmask(c, *opts.umask); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	p.started()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Wait()
+	}()
+	exit := &api.ExitStatus{}
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		syscall.Kill(-c.Process.Pid, syscall.SIGKILL)
+		err = <-done
+		exit.Terminated = true
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && timeout != nil {
+			exit.TerminationReason = fmt.Sprintf("timed out after %s", timeout.AsDuration().Round(time.Millisecond))
+		} else {
+			exit.TerminationReason = "canceled"
+		}
+	}
+	p.wait(outputWaitDelay)
 	if err != nil {
 		// Do not treat non-zero return code (ExitError) as an error here
 		if _, ok := err.(*exec.ExitError); !ok {
 			return nil, err
 		}
 	}
-	return &api.ScriptResponse{
-		Stdout:   stdoutBuf.String(),
-		Stderr:   stderrBuf.String(),
-		ExitCode: int32(c.ProcessState.ExitCode()),
+	exit.ExitCode = int32(c.ProcessState.ExitCode())
+	return exit, nil
+}
+
+// outputWaitDelay is how long the output of a process is read after it exits.
+// Processes it started in other process groups may keep its output open, and
+// are not waited for any longer than this.
+var outputWaitDelay = 5 * time.Second
+
+// pipes connects a process's stdin, stdout and stderr to pipes which are
+// managed by the agent. Otherwise, exec.Cmd waits for its own pipes to be
+// closed, which never happens if they are held open by a process that
+// escaped the process group.
+type pipes struct {
+	// Ends of the pipes used by the process, closed once it has started
+	child []*os.File
+	// Ends of the pipes used by the agent
+	parent []*os.File
+	copies sync.WaitGroup
+}
+
+// input returns a pipe to use as stdin, from which the process can read
+// data.
+func (p *pipes) input(data []byte) (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	p.child = append(p.child, r)
+	p.parent = append(p.parent, w)
+	go func() {
+		w.Write(data)
+		w.Close()
+	}()
+	return r, nil
+}
+
+// output returns a pipe to use as stdout or stderr, which is copied to out.
+func (p *pipes) output(out io.Writer) (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	p.child = append(p.child, w)
+	p.parent = append(p.parent, r)
+	p.copies.Add(1)
+	go func() {
+		defer p.copies.Done()
+		io.Copy(out, r)
+	}()
+	return w, nil
+}
+
+// started closes the process's ends of the pipes in the agent.
+func (p *pipes) started() {
+	for _, f := range p.child {
+		f.Close()
+	}
+	p.child = nil
+}
+
+// wait waits until the output has been copied, or until the delay has
+// passed, after which the pipes are closed and the rest of the output is
+// discarded.
+func (p *pipes) wait(delay time.Duration) {
+	copied := make(chan struct{})
+	go func() {
+		p.copies.Wait()
+		close(copied)
+	}()
+	select {
+	case <-copied:
+	case <-time.After(delay):
+	}
+	p.close()
+	<-copied
+}
+
+func (p *pipes) close() {
+	p.started()
+	for _, f := range p.parent {
+		f.Close()
+	}
+}
+
+// runningInstructions keeps track of running commands and scripts by the
+// client which started them and instruction ID, so that they can be
+// canceled. Instruction IDs are chosen by clients, so a client can only see
+// and cancel its own instructions.
+type runningInstructions struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newRunningInstructions() *runningInstructions {
+	return &runningInstructions{
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+// instructionKey returns the key the instruction identified in meta is
+// tracked under: "<client fingerprint>/<instruction id>".
+func instructionKey(meta *api.InstructionMeta) string {
+	return meta.GetClientFingerprint() + "/" + meta.GetInstructionID()
+}
+
+// start returns a context for the instruction which is canceled by cancel,
+// and a function to call once the instruction has finished. Instructions
+// without an ID cannot be canceled.
+func (r *runningInstructions) start(ctx context.Context, meta *api.InstructionMeta) (context.Context, func(), error) {
+	ctx, cancel := context.WithCancel(ctx)
+	if meta.GetInstructionID() == "" {
+		return ctx, cancel, nil
+	}
+	key := instructionKey(meta)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.cancels[key]; ok {
+		cancel()
+		return nil, nil, status.Error(codes.AlreadyExists, "instruction already exists")
+	}
+	r.cancels[key] = cancel
+	return ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.cancels, key)
+		cancel()
 	}, nil
 }
+
+// cancel cancels the instruction identified in meta, if it was started by the
+// same client.
+func (r *runningInstructions) cancel(meta *api.InstructionMeta) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cancel, ok := r.cancels[instructionKey(meta)]
+	if !ok {
+		return status.Error(codes.NotFound, "instruction not found")
+	}
+	cancel()
+	return nil
+}
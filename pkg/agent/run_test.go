@@ -0,0 +1,201 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// processRunning returns true if the process exists and is not a zombie.
+func processRunning(pid int) bool {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	// The state follows the command name, which is in parentheses
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
+
+// backgroundPid returns the pid printed by a command which runs
+// "sleep 60 & echo $!".
+func backgroundPid(stdout string) int {
+	pid, err := strconv.Atoi(strings.TrimSpace(stdout))
+	Expect(err).NotTo(HaveOccurred())
+	return pid
+}
+
+var _ = Describe("Running processes", func() {
+	It("should not wait for processes which keep the output open after exiting", func() {
+		delay := outputWaitDelay
+		outputWaitDelay = 100 * time.Millisecond
+		DeferCleanup(func() {
+			outputWaitDelay = delay
+		})
+		start := time.Now()
+		resp, err := RunCommand(context.Background(), &api.Command{
+			Command: "sh",
+			Args:    []string{"-c", "setsid sleep 5 & echo done"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Stdout).To(Equal("done\n"))
+		Expect(resp.ExitCode).To(BeEquivalentTo(0))
+		Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+	})
+	It("should read all output of processes which exit normally", func() {
+		stdout := &bytes.Buffer{}
+		exit, err := StreamCommand(context.Background(), &api.Command{
+			Command: "sh",
+			Args:    []string{"-c", "head -c 1000000 /dev/zero; exit 3"},
+		}, stdout, &bytes.Buffer{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exit.ExitCode).To(BeEquivalentTo(3))
+		Expect(stdout.Len()).To(Equal(1000000))
+	})
+	It("should kill the process group when the timeout expires", func() {
+		start := time.Now()
+		resp, err := RunCommand(context.Background(), &api.Command{
+			Command: "sh",
+			Args:    []string{"-c", "sleep 60 & echo $!; wait"},
+			Timeout: durationpb.New(500 * time.Millisecond),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		Expect(resp.Terminated).To(BeTrue())
+		Expect(resp.TerminationReason).To(Equal("timed out after 500ms"))
+		Expect(resp.ExitCode).To(BeEquivalentTo(-1))
+		pid := backgroundPid(resp.Stdout)
+		Eventually(func() bool {
+			return processRunning(pid)
+		}).Should(BeFalse())
+	})
+	It("should kill the process group when the context is canceled", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		resp, err := RunScript(ctx, &api.Script{
+			Interpreter: "/bin/sh",
+			Script:      "sleep 60 & echo $!; wait",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Terminated).To(BeTrue())
+		Expect(resp.TerminationReason).To(Equal("canceled"))
+		pid := backgroundPid(resp.Stdout)
+		Eventually(func() bool {
+			return processRunning(pid)
+		}).Should(BeFalse())
+	})
+	It("should reject timeouts which are not positive", func() {
+		for _, timeout := range []time.Duration{0, -time.Second} {
+			_, err := RunCommand(context.Background(), &api.Command{
+				Command: "true",
+				Timeout: durationpb.New(timeout),
+			})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			_, err = RunScript(context.Background(), &api.Script{
+				Interpreter: "/bin/sh",
+				Script:      "true",
+				Timeout:     durationpb.New(timeout),
+			})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		}
+	})
+	It("should not terminate processes which finish before the timeout", func() {
+		resp, err := RunCommand(context.Background(), &api.Command{
+			Command: "true",
+			Timeout: durationpb.New(time.Minute),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Terminated).To(BeFalse())
+		Expect(resp.TerminationReason).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Instruction cancellation", func() {
+	var a *Agent
+	var fp string
+	BeforeEach(func() {
+		a, _, fp = newTestAgent()
+	})
+
+	It("should cancel running commands by instruction ID", func() {
+		meta := &api.InstructionMeta{
+			ClientFingerprint: fp,
+			InstructionID:     "instruction",
+		}
+		type result struct {
+			resp *api.CommandResponse
+			err  error
+		}
+		results := make(chan result, 1)
+		go func() {
+			resp, err := a.Command(context.Background(), &api.CommandRequest{
+				Meta:    meta,
+				Command: &api.Command{Command: "sleep", Args: []string{"60"}},
+			})
+			results <- result{resp, err}
+		}()
+		Eventually(func() error {
+			_, err := a.Cancel(context.Background(), &api.CancelRequest{Meta: meta})
+			return err
+		}, 5*time.Second).Should(Succeed())
+		var r result
+		Eventually(results, 5*time.Second).Should(Receive(&r))
+		Expect(r.err).NotTo(HaveOccurred())
+		Expect(r.resp.Terminated).To(BeTrue())
+		Expect(r.resp.TerminationReason).To(Equal("canceled"))
+
+		_, err := a.Cancel(context.Background(), &api.CancelRequest{Meta: meta})
+		Expect(err).To(HaveOccurred())
+	})
+	It("should reject duplicate instruction IDs", func() {
+		meta := &api.InstructionMeta{
+			ClientFingerprint: fp,
+			InstructionID:     "instruction",
+		}
+		ctx, done, err := a.instructions.start(context.Background(), meta)
+		Expect(err).NotTo(HaveOccurred())
+		defer done()
+		_, err = a.Command(ctx, &api.CommandRequest{
+			Meta:    meta,
+			Command: &api.Command{Command: "true"},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+	It("should only let clients cancel their own instructions", func() {
+		meta := &api.InstructionMeta{
+			ClientFingerprint: fp,
+			InstructionID:     "instruction",
+		}
+		ctx, done, err := a.instructions.start(context.Background(), meta)
+		Expect(err).NotTo(HaveOccurred())
+		defer done()
+
+		// Another client can use the same instruction ID without conflicts,
+		// and cannot cancel the first client's instruction with it
+		other := &api.InstructionMeta{
+			ClientFingerprint: "SHA256:other",
+			InstructionID:     "instruction",
+		}
+		_, otherDone, err := a.instructions.start(context.Background(), other)
+		Expect(err).NotTo(HaveOccurred())
+		otherDone()
+		_, err = a.Cancel(context.Background(), &api.CancelRequest{Meta: other})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+		Expect(ctx.Err()).NotTo(HaveOccurred())
+
+		_, err = a.Cancel(context.Background(), &api.CancelRequest{Meta: meta})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ctx.Err()).To(HaveOccurred())
+	})
+})
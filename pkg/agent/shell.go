@@ -0,0 +1,213 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/creack/pty"
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var signals = map[string]syscall.Signal{
+	"ABRT": syscall.SIGABRT,
+	"ALRM": syscall.SIGALRM,
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"KILL": syscall.SIGKILL,
+	"QUIT": syscall.SIGQUIT,
+	"TERM": syscall.SIGTERM,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+}
+
+type shellSession struct {
+	pty *os.File
+	cmd *exec.Cmd
+	// Fingerprint of the client which opened the shell. Only that client can
+	// send it input.
+	clientFingerprint string
+}
+
+// shellSessions keeps track of running shells by stream ID.
+type shellSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*shellSession
+}
+
+func newShellSessions() *shellSessions {
+	return &shellSessions{
+		sessions: make(map[string]*shellSession),
+	}
+}
+
+func (s *shellSessions) add(id string, session *shellSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[id]; ok {
+		return status.Error(codes.AlreadyExists, "shell already exists")
+	}
+	s.sessions[id] = session
+	return nil
+}
+
+func (s *shellSessions) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+}
+
+func (s *shellSessions) get(id string) (*shellSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "shell not found")
+	}
+	return session, nil
+}
+
+func (a *Agent) Shell(ctx context.Context, req *api.ShellRequest) (*emptypb.Empty, error) {
+	id := req.Meta.GetStreamID()
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing stream ID")
+	}
+	if err := a.authorizeAgentUser(req.Meta, true); err != nil {
+		return nil, err
+	}
+	logrus.Info("Starting shell")
+	a.sharedTimer.Block()
+	defer a.sharedTimer.Unblock()
+	runCtx, done, err := a.instructions.start(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	c := exec.Command(shell)
+	// Start the shell as a login shell
+	c.Args[0] = "-" + filepath.Base(shell)
+	c.Env = os.Environ()
+	if term := req.Shell.GetTerm(); term != "" {
+		c.Env = append(c.Env, "TERM="+term)
+	}
+	c.Env = append(c.Env, req.Shell.GetEnv()...)
+	if home, err := os.UserHomeDir(); err == nil {
+		c.Dir = home
+	}
+
+	var f *os.File
+	if size := req.Shell.GetSize(); size != nil {
+		f, err = pty.StartWithSize(c, &pty.Winsize{
+			Rows: uint16(size.Rows),
+			Cols: uint16(size.Cols),
+		})
+	} else {
+		f, err = pty.Start(c)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer f.Close()
+	if err := a.shells.add(id, &shellSession{
+		pty:               f,
+		cmd:               c,
+		clientFingerprint: req.Meta.GetClientFingerprint(),
+	}); err != nil {
+		c.Process.Kill()
+		c.Wait()
+		return nil, err
+	}
+	defer a.shells.remove(id)
+
+	// If the relay's stream is lost or the shell is canceled, nothing will
+	// read its output or end it, so it is killed along with any processes it
+	// started, and the pty is closed.
+	exited := make(chan struct{})
+	defer close(exited)
+	go func() {
+		select {
+		case <-runCtx.Done():
+			logrus.Info("Shell canceled, killing process")
+			syscall.Kill(-c.Process.Pid, syscall.SIGKILL)
+			f.Close()
+		case <-exited:
+		}
+	}()
+
+	out := newOutputStream(ctx, a.client(), id)
+	// An empty first event lets the client know the shell is ready for input
+	out.Writer(api.OutputSource_Stdout).Write(nil)
+	// Reading from the pty returns EIO once the shell exits
+	if _, err := io.Copy(out.Writer(api.OutputSource_Stdout), f); err != nil &&
+		!errors.Is(err, syscall.EIO) && runCtx.Err() == nil {
+		logrus.Errorf("Error reading from shell: %v", err)
+	}
+	if err := c.Wait(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, err
+		}
+	}
+	exit := &api.ExitStatus{
+		ExitCode: int32(c.ProcessState.ExitCode()),
+	}
+	if runCtx.Err() != nil {
+		exit.Terminated = true
+		exit.TerminationReason = "canceled"
+	}
+	logrus.Info("Shell exited")
+	if err := out.Exit(exit); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *Agent) ShellInput(ctx context.Context, req *api.ShellInputRequest) (*emptypb.Empty, error) {
+	session, err := a.shells.get(req.Meta.GetStreamID())
+	if err != nil {
+		return nil, err
+	}
+	switch fp := req.Meta.GetClientFingerprint(); {
+	case fp == "":
+		return nil, status.Error(codes.PermissionDenied, "missing client fingerprint")
+	case fp != session.clientFingerprint:
+		return nil, status.Error(codes.PermissionDenied, "shell was opened by another client")
+	}
+	switch input := req.Input.(type) {
+	case *api.ShellInputRequest_Data:
+		if _, err := session.pty.Write(input.Data); err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	case *api.ShellInputRequest_Resize:
+		if err := pty.Setsize(session.pty, &pty.Winsize{
+			Rows: uint16(input.Resize.GetRows()),
+			Cols: uint16(input.Resize.GetCols()),
+		}); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	case *api.ShellInputRequest_Signal:
+		sig, ok := signals[input.Signal]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown signal %q", input.Signal)
+		}
+		if err := session.cmd.Process.Signal(sig); err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "empty input")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,144 @@
+package agent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"os/user"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/kralicky/post-init/pkg/util"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// outputRecorder is an agent API client which passes output events to a
+// channel.
+type outputRecorder struct {
+	api.AgentAPIClient
+	events chan *api.OutputEvent
+}
+
+func (r *outputRecorder) WriteOutput(_ context.Context, ev *api.OutputEvent, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+	r.events <- ev
+	return &emptypb.Empty{}, nil
+}
+
+// newTestAgent returns an agent which authorizes a new client key for the
+// current user, and the fingerprint of that key.
+func newTestAgent() (*Agent, *outputRecorder, string) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	key, err := ssh.NewPublicKey(pub)
+	Expect(err).NotTo(HaveOccurred())
+	current, err := user.Current()
+	Expect(err).NotTo(HaveOccurred())
+
+	a := New(WithExtraAuthorizedKeys(string(ssh.MarshalAuthorizedKey(key))))
+	a.username = current.Username
+	a.sharedTimer = util.NewSharedTimer(time.Hour)
+	recorder := &outputRecorder{
+		events: make(chan *api.OutputEvent, 100),
+	}
+	a.agentClient = recorder
+	return a, recorder, ssh.FingerprintSHA256(key)
+}
+
+var _ = Describe("Shell", func() {
+	var a *Agent
+	var recorder *outputRecorder
+	var meta *api.InstructionMeta
+	BeforeEach(func() {
+		var fp string
+		a, recorder, fp = newTestAgent()
+		meta = &api.InstructionMeta{
+			ClientFingerprint: fp,
+			StreamID:          "stream",
+			InstructionID:     "instruction",
+		}
+	})
+	start := func(ctx context.Context) <-chan error {
+		errs := make(chan error, 1)
+		go func() {
+			_, err := a.Shell(ctx, &api.ShellRequest{
+				Meta:  meta,
+				Shell: &api.Shell{},
+			})
+			errs <- err
+		}()
+		// The first event is sent once the shell has started
+		Eventually(recorder.events, 5*time.Second).Should(Receive())
+		return errs
+	}
+	exitStatus := func() *api.ExitStatus {
+		for {
+			var ev *api.OutputEvent
+			Eventually(recorder.events, 5*time.Second).Should(Receive(&ev))
+			if exit := ev.GetExit(); exit != nil {
+				return exit
+			}
+		}
+	}
+
+	It("should kill the shell when it is canceled", func() {
+		errs := start(context.Background())
+		_, err := a.ShellInput(context.Background(), &api.ShellInputRequest{
+			Meta:  meta,
+			Input: &api.ShellInputRequest_Data{Data: []byte("sleep 60\n")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = a.Cancel(context.Background(), &api.CancelRequest{Meta: meta})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(errs, 5*time.Second).Should(Receive(BeNil()))
+		exit := exitStatus()
+		Expect(exit.Terminated).To(BeTrue())
+		Expect(exit.TerminationReason).To(Equal("canceled"))
+
+		_, err = a.shells.get(meta.StreamID)
+		Expect(err).To(HaveOccurred())
+	})
+	It("should only accept input and cancellation from the client which opened the shell", func() {
+		errs := start(context.Background())
+		other := &api.InstructionMeta{
+			ClientFingerprint: "SHA256:other",
+			StreamID:          meta.StreamID,
+			InstructionID:     meta.InstructionID,
+		}
+		_, err := a.ShellInput(context.Background(), &api.ShellInputRequest{
+			Meta:  other,
+			Input: &api.ShellInputRequest_Signal{Signal: "KILL"},
+		})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		_, err = a.ShellInput(context.Background(), &api.ShellInputRequest{
+			Meta:  &api.InstructionMeta{StreamID: meta.StreamID},
+			Input: &api.ShellInputRequest_Signal{Signal: "KILL"},
+		})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		_, err = a.Cancel(context.Background(), &api.CancelRequest{Meta: other})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+		_, err = a.Cancel(context.Background(), &api.CancelRequest{
+			Meta: &api.InstructionMeta{InstructionID: meta.InstructionID},
+		})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		Consistently(errs, 100*time.Millisecond).ShouldNot(Receive())
+
+		_, err = a.Cancel(context.Background(), &api.CancelRequest{Meta: meta})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(errs, 5*time.Second).Should(Receive(BeNil()))
+	})
+	It("should kill the shell when the relay's stream is lost", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := start(ctx)
+		cancel()
+		Eventually(errs, 5*time.Second).Should(Receive())
+		_, err := a.shells.get(meta.StreamID)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -9,7 +9,7 @@ package api
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
-	_ "google.golang.org/protobuf/types/known/emptypb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -89,24 +89,59 @@ var file_pkg_api_agent_api_proto_rawDesc = []byte{
 	0x3d, 0x0a, 0x14, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52,
 	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x06, 0x41, 0x63, 0x63, 0x65, 0x70,
 	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x42, 0x00, 0x12, 0x11, 0x0a, 0x07, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x32, 0x4c,
-	0x0a, 0x08, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x12, 0x3e, 0x0a, 0x08, 0x41, 0x6e,
-	0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6e, 0x6e,
-	0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x2e,
-	0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x1a, 0x00, 0x32, 0x84, 0x01, 0x0a,
-	0x0b, 0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x07,
-	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f,
-	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61,
-	0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x37, 0x0a, 0x06, 0x53, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30,
-	0x00, 0x1a, 0x00, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x6b, 0x72, 0x61, 0x6c, 0x69, 0x63, 0x6b, 0x79, 0x2f, 0x70, 0x6f, 0x73, 0x74, 0x2d,
-	0x69, 0x6e, 0x69, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x32, 0xcc,
+	0x01, 0x0a, 0x08, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x12, 0x3e, 0x0a, 0x08, 0x41,
+	0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6e,
+	0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x19, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3d, 0x0a, 0x0b, 0x57,
+	0x72, 0x69, 0x74, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x1a, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3f, 0x0a, 0x0c, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x1a, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x1a, 0x00, 0x32, 0xbe, 0x04,
+	0x0a, 0x0b, 0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a,
+	0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x37, 0x0a, 0x06, 0x53, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00,
+	0x30, 0x00, 0x12, 0x42, 0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x12, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x40, 0x0a, 0x0c, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3a, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x63,
+	0x65, 0x6c, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00,
+	0x28, 0x00, 0x30, 0x00, 0x12, 0x38, 0x0a, 0x05, 0x53, 0x68, 0x65, 0x6c, 0x6c, 0x12, 0x11, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x42,
+	0x0a, 0x0a, 0x53, 0x68, 0x65, 0x6c, 0x6c, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x16, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x6c, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x00,
+	0x30, 0x00, 0x12, 0x3c, 0x0a, 0x07, 0x50, 0x75, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x13, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x50, 0x75, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00,
+	0x12, 0x3a, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x13, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x1a, 0x00, 0x42, 0x27,
+	0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x72, 0x61,
+	0x6c, 0x69, 0x63, 0x6b, 0x79, 0x2f, 0x70, 0x6f, 0x73, 0x74, 0x2d, 0x69, 0x6e, 0x69, 0x74, 0x2f,
+	0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -125,23 +160,50 @@ var file_pkg_api_agent_api_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_pkg_api_agent_api_proto_goTypes = []interface{}{
 	(*AnnouncementResponse)(nil), // 0: api.AnnouncementResponse
 	(*Announcement)(nil),         // 1: api.Announcement
-	(*CommandRequest)(nil),       // 2: api.CommandRequest
-	(*ScriptRequest)(nil),        // 3: api.ScriptRequest
-	(*CommandResponse)(nil),      // 4: api.CommandResponse
-	(*ScriptResponse)(nil),       // 5: api.ScriptResponse
+	(*OutputEvent)(nil),          // 2: api.OutputEvent
+	(*StatusUpdate)(nil),         // 3: api.StatusUpdate
+	(*CommandRequest)(nil),       // 4: api.CommandRequest
+	(*ScriptRequest)(nil),        // 5: api.ScriptRequest
+	(*CancelRequest)(nil),        // 6: api.CancelRequest
+	(*ShellRequest)(nil),         // 7: api.ShellRequest
+	(*ShellInputRequest)(nil),    // 8: api.ShellInputRequest
+	(*PutFileRequest)(nil),       // 9: api.PutFileRequest
+	(*GetFileRequest)(nil),       // 10: api.GetFileRequest
+	(*emptypb.Empty)(nil),        // 11: google.protobuf.Empty
+	(*CommandResponse)(nil),      // 12: api.CommandResponse
+	(*ScriptResponse)(nil),       // 13: api.ScriptResponse
+	(*GetFileResponse)(nil),      // 14: api.GetFileResponse
 }
 var file_pkg_api_agent_api_proto_depIdxs = []int32{
-	1, // 0: api.AgentAPI.Announce:input_type -> api.Announcement
-	2, // 1: api.Instruction.Command:input_type -> api.CommandRequest
-	3, // 2: api.Instruction.Script:input_type -> api.ScriptRequest
-	0, // 3: api.AgentAPI.Announce:output_type -> api.AnnouncementResponse
-	4, // 4: api.Instruction.Command:output_type -> api.CommandResponse
-	5, // 5: api.Instruction.Script:output_type -> api.ScriptResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1,  // 0: api.AgentAPI.Announce:input_type -> api.Announcement
+	2,  // 1: api.AgentAPI.WriteOutput:input_type -> api.OutputEvent
+	3,  // 2: api.AgentAPI.UpdateStatus:input_type -> api.StatusUpdate
+	4,  // 3: api.Instruction.Command:input_type -> api.CommandRequest
+	5,  // 4: api.Instruction.Script:input_type -> api.ScriptRequest
+	4,  // 5: api.Instruction.CommandStream:input_type -> api.CommandRequest
+	5,  // 6: api.Instruction.ScriptStream:input_type -> api.ScriptRequest
+	6,  // 7: api.Instruction.Cancel:input_type -> api.CancelRequest
+	7,  // 8: api.Instruction.Shell:input_type -> api.ShellRequest
+	8,  // 9: api.Instruction.ShellInput:input_type -> api.ShellInputRequest
+	9,  // 10: api.Instruction.PutFile:input_type -> api.PutFileRequest
+	10, // 11: api.Instruction.GetFile:input_type -> api.GetFileRequest
+	0,  // 12: api.AgentAPI.Announce:output_type -> api.AnnouncementResponse
+	11, // 13: api.AgentAPI.WriteOutput:output_type -> google.protobuf.Empty
+	11, // 14: api.AgentAPI.UpdateStatus:output_type -> google.protobuf.Empty
+	12, // 15: api.Instruction.Command:output_type -> api.CommandResponse
+	13, // 16: api.Instruction.Script:output_type -> api.ScriptResponse
+	11, // 17: api.Instruction.CommandStream:output_type -> google.protobuf.Empty
+	11, // 18: api.Instruction.ScriptStream:output_type -> google.protobuf.Empty
+	11, // 19: api.Instruction.Cancel:output_type -> google.protobuf.Empty
+	11, // 20: api.Instruction.Shell:output_type -> google.protobuf.Empty
+	11, // 21: api.Instruction.ShellInput:output_type -> google.protobuf.Empty
+	11, // 22: api.Instruction.PutFile:output_type -> google.protobuf.Empty
+	14, // 23: api.Instruction.GetFile:output_type -> api.GetFileResponse
+	12, // [12:24] is the sub-list for method output_type
+	0,  // [0:12] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_pkg_api_agent_api_proto_init() }
@@ -11,6 +11,7 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -23,6 +24,8 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AgentAPIClient interface {
 	Announce(ctx context.Context, in *Announcement, opts ...grpc.CallOption) (*AnnouncementResponse, error)
+	WriteOutput(ctx context.Context, in *OutputEvent, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateStatus(ctx context.Context, in *StatusUpdate, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type agentAPIClient struct {
@@ -42,11 +45,31 @@ func (c *agentAPIClient) Announce(ctx context.Context, in *Announcement, opts ..
 	return out, nil
 }
 
+func (c *agentAPIClient) WriteOutput(ctx context.Context, in *OutputEvent, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.AgentAPI/WriteOutput", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentAPIClient) UpdateStatus(ctx context.Context, in *StatusUpdate, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.AgentAPI/UpdateStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentAPIServer is the server API for AgentAPI service.
 // All implementations must embed UnimplementedAgentAPIServer
 // for forward compatibility
 type AgentAPIServer interface {
 	Announce(context.Context, *Announcement) (*AnnouncementResponse, error)
+	WriteOutput(context.Context, *OutputEvent) (*emptypb.Empty, error)
+	UpdateStatus(context.Context, *StatusUpdate) (*emptypb.Empty, error)
 	mustEmbedUnimplementedAgentAPIServer()
 }
 
@@ -57,6 +80,12 @@ type UnimplementedAgentAPIServer struct {
 func (UnimplementedAgentAPIServer) Announce(context.Context, *Announcement) (*AnnouncementResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Announce not implemented")
 }
+func (UnimplementedAgentAPIServer) WriteOutput(context.Context, *OutputEvent) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteOutput not implemented")
+}
+func (UnimplementedAgentAPIServer) UpdateStatus(context.Context, *StatusUpdate) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateStatus not implemented")
+}
 func (UnimplementedAgentAPIServer) mustEmbedUnimplementedAgentAPIServer() {}
 
 // UnsafeAgentAPIServer may be embedded to opt out of forward compatibility for this service.
@@ -88,6 +117,42 @@ func _AgentAPI_Announce_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentAPI_WriteOutput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OutputEvent)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentAPIServer).WriteOutput(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.AgentAPI/WriteOutput",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentAPIServer).WriteOutput(ctx, req.(*OutputEvent))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentAPI_UpdateStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusUpdate)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentAPIServer).UpdateStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.AgentAPI/UpdateStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentAPIServer).UpdateStatus(ctx, req.(*StatusUpdate))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AgentAPI_ServiceDesc is the grpc.ServiceDesc for AgentAPI service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -99,6 +164,14 @@ var AgentAPI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Announce",
 			Handler:    _AgentAPI_Announce_Handler,
 		},
+		{
+			MethodName: "WriteOutput",
+			Handler:    _AgentAPI_WriteOutput_Handler,
+		},
+		{
+			MethodName: "UpdateStatus",
+			Handler:    _AgentAPI_UpdateStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/api/agent_api.proto",
@@ -110,6 +183,13 @@ var AgentAPI_ServiceDesc = grpc.ServiceDesc{
 type InstructionClient interface {
 	Command(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error)
 	Script(ctx context.Context, in *ScriptRequest, opts ...grpc.CallOption) (*ScriptResponse, error)
+	CommandStream(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ScriptStream(ctx context.Context, in *ScriptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Shell(ctx context.Context, in *ShellRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ShellInput(ctx context.Context, in *ShellInputRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	PutFile(ctx context.Context, in *PutFileRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*GetFileResponse, error)
 }
 
 type instructionClient struct {
@@ -138,12 +218,82 @@ func (c *instructionClient) Script(ctx context.Context, in *ScriptRequest, opts
 	return out, nil
 }
 
+func (c *instructionClient) CommandStream(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.Instruction/CommandStream", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instructionClient) ScriptStream(ctx context.Context, in *ScriptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.Instruction/ScriptStream", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instructionClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.Instruction/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instructionClient) Shell(ctx context.Context, in *ShellRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.Instruction/Shell", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instructionClient) ShellInput(ctx context.Context, in *ShellInputRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.Instruction/ShellInput", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instructionClient) PutFile(ctx context.Context, in *PutFileRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.Instruction/PutFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instructionClient) GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*GetFileResponse, error) {
+	out := new(GetFileResponse)
+	err := c.cc.Invoke(ctx, "/api.Instruction/GetFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InstructionServer is the server API for Instruction service.
 // All implementations must embed UnimplementedInstructionServer
 // for forward compatibility
 type InstructionServer interface {
 	Command(context.Context, *CommandRequest) (*CommandResponse, error)
 	Script(context.Context, *ScriptRequest) (*ScriptResponse, error)
+	CommandStream(context.Context, *CommandRequest) (*emptypb.Empty, error)
+	ScriptStream(context.Context, *ScriptRequest) (*emptypb.Empty, error)
+	Cancel(context.Context, *CancelRequest) (*emptypb.Empty, error)
+	Shell(context.Context, *ShellRequest) (*emptypb.Empty, error)
+	ShellInput(context.Context, *ShellInputRequest) (*emptypb.Empty, error)
+	PutFile(context.Context, *PutFileRequest) (*emptypb.Empty, error)
+	GetFile(context.Context, *GetFileRequest) (*GetFileResponse, error)
 	mustEmbedUnimplementedInstructionServer()
 }
 
@@ -157,6 +307,27 @@ func (UnimplementedInstructionServer) Command(context.Context, *CommandRequest)
 func (UnimplementedInstructionServer) Script(context.Context, *ScriptRequest) (*ScriptResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Script not implemented")
 }
+func (UnimplementedInstructionServer) CommandStream(context.Context, *CommandRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommandStream not implemented")
+}
+func (UnimplementedInstructionServer) ScriptStream(context.Context, *ScriptRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScriptStream not implemented")
+}
+func (UnimplementedInstructionServer) Cancel(context.Context, *CancelRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+func (UnimplementedInstructionServer) Shell(context.Context, *ShellRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Shell not implemented")
+}
+func (UnimplementedInstructionServer) ShellInput(context.Context, *ShellInputRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShellInput not implemented")
+}
+func (UnimplementedInstructionServer) PutFile(context.Context, *PutFileRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutFile not implemented")
+}
+func (UnimplementedInstructionServer) GetFile(context.Context, *GetFileRequest) (*GetFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFile not implemented")
+}
 func (UnimplementedInstructionServer) mustEmbedUnimplementedInstructionServer() {}
 
 // UnsafeInstructionServer may be embedded to opt out of forward compatibility for this service.
@@ -206,6 +377,132 @@ func _Instruction_Script_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Instruction_CommandStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstructionServer).CommandStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Instruction/CommandStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstructionServer).CommandStream(ctx, req.(*CommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Instruction_ScriptStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstructionServer).ScriptStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Instruction/ScriptStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstructionServer).ScriptStream(ctx, req.(*ScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Instruction_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstructionServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Instruction/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstructionServer).Cancel(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Instruction_Shell_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShellRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstructionServer).Shell(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Instruction/Shell",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstructionServer).Shell(ctx, req.(*ShellRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Instruction_ShellInput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShellInputRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstructionServer).ShellInput(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Instruction/ShellInput",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstructionServer).ShellInput(ctx, req.(*ShellInputRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Instruction_PutFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstructionServer).PutFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Instruction/PutFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstructionServer).PutFile(ctx, req.(*PutFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Instruction_GetFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstructionServer).GetFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Instruction/GetFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstructionServer).GetFile(ctx, req.(*GetFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Instruction_ServiceDesc is the grpc.ServiceDesc for Instruction service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -221,6 +518,34 @@ var Instruction_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Script",
 			Handler:    _Instruction_Script_Handler,
 		},
+		{
+			MethodName: "CommandStream",
+			Handler:    _Instruction_CommandStream_Handler,
+		},
+		{
+			MethodName: "ScriptStream",
+			Handler:    _Instruction_ScriptStream_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _Instruction_Cancel_Handler,
+		},
+		{
+			MethodName: "Shell",
+			Handler:    _Instruction_Shell_Handler,
+		},
+		{
+			MethodName: "ShellInput",
+			Handler:    _Instruction_ShellInput_Handler,
+		},
+		{
+			MethodName: "PutFile",
+			Handler:    _Instruction_PutFile_Handler,
+		},
+		{
+			MethodName: "GetFile",
+			Handler:    _Instruction_GetFile_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/api/agent_api.proto",
@@ -9,6 +9,7 @@ package api
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	_ "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
@@ -21,15 +22,81 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CloudInitState int32
+
+const (
+	CloudInitState_Unknown      CloudInitState = 0
+	CloudInitState_NotInstalled CloudInitState = 1
+	CloudInitState_NotRun       CloudInitState = 2
+	CloudInitState_Running      CloudInitState = 3
+	CloudInitState_Done         CloudInitState = 4
+	CloudInitState_Error        CloudInitState = 5
+	CloudInitState_Disabled     CloudInitState = 6
+)
+
+// Enum value maps for CloudInitState.
+var (
+	CloudInitState_name = map[int32]string{
+		0: "Unknown",
+		1: "NotInstalled",
+		2: "NotRun",
+		3: "Running",
+		4: "Done",
+		5: "Error",
+		6: "Disabled",
+	}
+	CloudInitState_value = map[string]int32{
+		"Unknown":      0,
+		"NotInstalled": 1,
+		"NotRun":       2,
+		"Running":      3,
+		"Done":         4,
+		"Error":        5,
+		"Disabled":     6,
+	}
+)
+
+func (x CloudInitState) Enum() *CloudInitState {
+	p := new(CloudInitState)
+	*p = x
+	return p
+}
+
+func (x CloudInitState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CloudInitState) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_api_announce_proto_enumTypes[0].Descriptor()
+}
+
+func (CloudInitState) Type() protoreflect.EnumType {
+	return &file_pkg_api_announce_proto_enumTypes[0]
+}
+
+func (x CloudInitState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CloudInitState.Descriptor instead.
+func (CloudInitState) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_api_announce_proto_rawDescGZIP(), []int{0}
+}
+
 type Announcement struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uname                  *UnameInfo       `protobuf:"bytes,1,opt,name=Uname,proto3" json:"Uname,omitempty"`
-	Network                *NetworkInfo     `protobuf:"bytes,2,opt,name=Network,proto3" json:"Network,omitempty"`
-	PreferredHostPublicKey []byte           `protobuf:"bytes,3,opt,name=PreferredHostPublicKey,proto3" json:"PreferredHostPublicKey,omitempty"`
-	AuthorizedKeys         []*AuthorizedKey `protobuf:"bytes,4,rep,name=AuthorizedKeys,proto3" json:"AuthorizedKeys,omitempty"`
+	Uname                  *UnameInfo        `protobuf:"bytes,1,opt,name=Uname,proto3" json:"Uname,omitempty"`
+	Network                *NetworkInfo      `protobuf:"bytes,2,opt,name=Network,proto3" json:"Network,omitempty"`
+	PreferredHostPublicKey []byte            `protobuf:"bytes,3,opt,name=PreferredHostPublicKey,proto3" json:"PreferredHostPublicKey,omitempty"`
+	AuthorizedKeys         []*AuthorizedKey  `protobuf:"bytes,4,rep,name=AuthorizedKeys,proto3" json:"AuthorizedKeys,omitempty"`
+	Labels                 map[string]string `protobuf:"bytes,5,rep,name=Labels,proto3" json:"Labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Cloud                  *CloudMetadata    `protobuf:"bytes,6,opt,name=Cloud,proto3" json:"Cloud,omitempty"`
+	CloudInit              *CloudInitStatus  `protobuf:"bytes,7,opt,name=CloudInit,proto3" json:"CloudInit,omitempty"`
+	BootWait               *BootWait         `protobuf:"bytes,8,opt,name=BootWait,proto3" json:"BootWait,omitempty"`
+	AgentUser              string            `protobuf:"bytes,9,opt,name=AgentUser,proto3" json:"AgentUser,omitempty"`
 }
 
 func (x *Announcement) Reset() {
@@ -92,6 +159,41 @@ func (x *Announcement) GetAuthorizedKeys() []*AuthorizedKey {
 	return nil
 }
 
+func (x *Announcement) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Announcement) GetCloud() *CloudMetadata {
+	if x != nil {
+		return x.Cloud
+	}
+	return nil
+}
+
+func (x *Announcement) GetCloudInit() *CloudInitStatus {
+	if x != nil {
+		return x.CloudInit
+	}
+	return nil
+}
+
+func (x *Announcement) GetBootWait() *BootWait {
+	if x != nil {
+		return x.BootWait
+	}
+	return nil
+}
+
+func (x *Announcement) GetAgentUser() string {
+	if x != nil {
+		return x.AgentUser
+	}
+	return ""
+}
+
 type UnameInfo struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -423,13 +525,275 @@ func (x *AuthorizedKey) GetOptions() []string {
 	return nil
 }
 
+type CloudMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Provider     string            `protobuf:"bytes,1,opt,name=Provider,proto3" json:"Provider,omitempty"`
+	InstanceID   string            `protobuf:"bytes,2,opt,name=InstanceID,proto3" json:"InstanceID,omitempty"`
+	InstanceType string            `protobuf:"bytes,3,opt,name=InstanceType,proto3" json:"InstanceType,omitempty"`
+	Region       string            `protobuf:"bytes,4,opt,name=Region,proto3" json:"Region,omitempty"`
+	Zone         string            `protobuf:"bytes,5,opt,name=Zone,proto3" json:"Zone,omitempty"`
+	Tags         map[string]string `protobuf:"bytes,6,rep,name=Tags,proto3" json:"Tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *CloudMetadata) Reset() {
+	*x = CloudMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_announce_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloudMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloudMetadata) ProtoMessage() {}
+
+func (x *CloudMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_announce_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloudMetadata.ProtoReflect.Descriptor instead.
+func (*CloudMetadata) Descriptor() ([]byte, []int) {
+	return file_pkg_api_announce_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CloudMetadata) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *CloudMetadata) GetInstanceID() string {
+	if x != nil {
+		return x.InstanceID
+	}
+	return ""
+}
+
+func (x *CloudMetadata) GetInstanceType() string {
+	if x != nil {
+		return x.InstanceType
+	}
+	return ""
+}
+
+func (x *CloudMetadata) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *CloudMetadata) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *CloudMetadata) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type CloudInitStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State   CloudInitState `protobuf:"varint,1,opt,name=State,proto3,enum=api.CloudInitState" json:"State,omitempty"`
+	Errors  []string       `protobuf:"bytes,2,rep,name=Errors,proto3" json:"Errors,omitempty"`
+	LogTail []string       `protobuf:"bytes,3,rep,name=LogTail,proto3" json:"LogTail,omitempty"`
+}
+
+func (x *CloudInitStatus) Reset() {
+	*x = CloudInitStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_announce_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloudInitStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloudInitStatus) ProtoMessage() {}
+
+func (x *CloudInitStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_announce_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloudInitStatus.ProtoReflect.Descriptor instead.
+func (*CloudInitStatus) Descriptor() ([]byte, []int) {
+	return file_pkg_api_announce_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CloudInitStatus) GetState() CloudInitState {
+	if x != nil {
+		return x.State
+	}
+	return CloudInitState_Unknown
+}
+
+func (x *CloudInitStatus) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *CloudInitStatus) GetLogTail() []string {
+	if x != nil {
+		return x.LogTail
+	}
+	return nil
+}
+
+type StatusUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CloudInit *CloudInitStatus `protobuf:"bytes,1,opt,name=CloudInit,proto3" json:"CloudInit,omitempty"`
+}
+
+func (x *StatusUpdate) Reset() {
+	*x = StatusUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_announce_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusUpdate) ProtoMessage() {}
+
+func (x *StatusUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_announce_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusUpdate.ProtoReflect.Descriptor instead.
+func (*StatusUpdate) Descriptor() ([]byte, []int) {
+	return file_pkg_api_announce_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StatusUpdate) GetCloudInit() *CloudInitStatus {
+	if x != nil {
+		return x.CloudInit
+	}
+	return nil
+}
+
+type BootWait struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Duration  *durationpb.Duration `protobuf:"bytes,1,opt,name=Duration,proto3" json:"Duration,omitempty"`
+	Condition string               `protobuf:"bytes,2,opt,name=Condition,proto3" json:"Condition,omitempty"`
+	TimedOut  bool                 `protobuf:"varint,3,opt,name=TimedOut,proto3" json:"TimedOut,omitempty"`
+}
+
+func (x *BootWait) Reset() {
+	*x = BootWait{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_announce_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BootWait) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BootWait) ProtoMessage() {}
+
+func (x *BootWait) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_announce_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BootWait.ProtoReflect.Descriptor instead.
+func (*BootWait) Descriptor() ([]byte, []int) {
+	return file_pkg_api_announce_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BootWait) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *BootWait) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *BootWait) GetTimedOut() bool {
+	if x != nil {
+		return x.TimedOut
+	}
+	return false
+}
+
 var File_pkg_api_announce_proto protoreflect.FileDescriptor
 
 var file_pkg_api_announce_proto_rawDesc = []byte{
 	0x0a, 0x16, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e,
 	0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x61, 0x70, 0x69, 0x1a, 0x1b, 0x67,
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65,
-	0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa6, 0x01, 0x0a, 0x0c, 0x41,
+	0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x92, 0x03, 0x0a, 0x0c, 0x41,
 	0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x05, 0x55,
 	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69,
 	0x2e, 0x55, 0x6e, 0x61, 0x6d, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x42, 0x00, 0x12, 0x23, 0x0a, 0x07,
@@ -440,38 +804,89 @@ var file_pkg_api_announce_proto_rawDesc = []byte{
 	0x0c, 0x42, 0x00, 0x12, 0x2c, 0x0a, 0x0e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65,
 	0x64, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x70,
 	0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x42,
-	0x00, 0x3a, 0x00, 0x22, 0x7c, 0x0a, 0x09, 0x55, 0x6e, 0x61, 0x6d, 0x65, 0x49, 0x6e, 0x66, 0x6f,
-	0x12, 0x14, 0x0a, 0x0a, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x12, 0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x17, 0x0a, 0x0d, 0x4b, 0x65,
-	0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x42, 0x00, 0x12, 0x17, 0x0a, 0x0d, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x56, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x11, 0x0a, 0x07,
-	0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a,
-	0x00, 0x22, 0x43, 0x0a, 0x0b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x66, 0x6f,
-	0x12, 0x32, 0x0a, 0x11, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72,
-	0x66, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x61, 0x70,
-	0x69, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
-	0x63, 0x65, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x54, 0x0a, 0x10, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
-	0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x06, 0x44, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0c, 0x0a, 0x02,
-	0x55, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x42, 0x00, 0x12, 0x1e, 0x0a, 0x09, 0x41, 0x64,
-	0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e,
-	0x61, 0x70, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x3b, 0x0a, 0x04,
-	0x41, 0x64, 0x64, 0x72, 0x12, 0x0e, 0x0a, 0x04, 0x43, 0x69, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x42, 0x00, 0x12, 0x11, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x4d, 0x61, 0x73, 0x6b, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x6e, 0x0a, 0x0d, 0x41, 0x75, 0x74,
-	0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x0e, 0x0a, 0x04, 0x55, 0x73,
-	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x54, 0x79,
-	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x15, 0x0a, 0x0b, 0x46, 0x69,
-	0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42,
-	0x00, 0x12, 0x11, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x09, 0x42, 0x00, 0x12, 0x11, 0x0a, 0x07, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
-	0x04, 0x20, 0x03, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x72, 0x61, 0x6c, 0x69, 0x63, 0x6b, 0x79,
-	0x2f, 0x70, 0x6f, 0x73, 0x74, 0x2d, 0x69, 0x6e, 0x69, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61,
-	0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x00, 0x12, 0x2f, 0x0a, 0x06, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x42, 0x00, 0x12, 0x23, 0x0a, 0x05, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x42, 0x00, 0x12, 0x29, 0x0a, 0x09, 0x43, 0x6c, 0x6f, 0x75, 0x64,
+	0x49, 0x6e, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x6e, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x42, 0x00, 0x12, 0x21, 0x0a, 0x08, 0x42, 0x6f, 0x6f, 0x74, 0x57, 0x61, 0x69, 0x74, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x6f, 0x6f, 0x74, 0x57,
+	0x61, 0x69, 0x74, 0x42, 0x00, 0x12, 0x13, 0x0a, 0x09, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x55, 0x73,
+	0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x1a, 0x31, 0x0a, 0x0b, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x0d, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0f, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x02, 0x38, 0x01, 0x3a, 0x00, 0x22,
+	0x7c, 0x0a, 0x09, 0x55, 0x6e, 0x61, 0x6d, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x14, 0x0a, 0x0a,
+	0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x00, 0x12, 0x12, 0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x17, 0x0a, 0x0d, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
+	0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12,
+	0x17, 0x0a, 0x0d, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x11, 0x0a, 0x07, 0x4d, 0x61, 0x63, 0x68,
+	0x69, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x43, 0x0a,
+	0x0b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x32, 0x0a, 0x11,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x42, 0x00,
+	0x3a, 0x00, 0x22, 0x54, 0x0a, 0x10, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x06, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0c, 0x0a, 0x02, 0x55, 0x70, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x42, 0x00, 0x12, 0x1e, 0x0a, 0x09, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x41, 0x64, 0x64, 0x72, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x3b, 0x0a, 0x04, 0x41, 0x64, 0x64, 0x72,
+	0x12, 0x0e, 0x0a, 0x04, 0x43, 0x69, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00,
+	0x12, 0x11, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x4d, 0x61, 0x73, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x6e, 0x0a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69,
+	0x7a, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x0e, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x15, 0x0a, 0x0b, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72,
+	0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x11, 0x0a,
+	0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00,
+	0x12, 0x11, 0x0a, 0x07, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0xd4, 0x01, 0x0a, 0x0d, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x76, 0x69,
+	0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x14, 0x0a, 0x0a, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x00, 0x12, 0x16, 0x0a, 0x0c, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x52, 0x65, 0x67,
+	0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x5a,
+	0x6f, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x2c, 0x0a, 0x04, 0x54,
+	0x61, 0x67, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x43, 0x6c, 0x6f, 0x75, 0x64, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x54, 0x61,
+	0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x42, 0x00, 0x1a, 0x2f, 0x0a, 0x09, 0x54, 0x61, 0x67,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x0d, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0f, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x02, 0x38, 0x01, 0x3a, 0x00, 0x22, 0x5e, 0x0a, 0x0f,
+	0x43, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x6e, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x24, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x6e, 0x69, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x42, 0x00, 0x12, 0x11, 0x0a, 0x07, 0x4c, 0x6f, 0x67, 0x54, 0x61,
+	0x69, 0x6c, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x3b, 0x0a, 0x0c,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x09,
+	0x43, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x6e, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x6e, 0x69, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x64, 0x0a, 0x08, 0x42, 0x6f, 0x6f,
+	0x74, 0x57, 0x61, 0x69, 0x74, 0x12, 0x2d, 0x0a, 0x08, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x42, 0x00, 0x12, 0x13, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x12, 0x0a, 0x08, 0x54, 0x69, 0x6d,
+	0x65, 0x64, 0x4f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x42, 0x00, 0x3a, 0x00, 0x2a,
+	0x6d, 0x0a, 0x0e, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x6e, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x10,
+	0x0a, 0x0c, 0x4e, 0x6f, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x01,
+	0x12, 0x0a, 0x0a, 0x06, 0x4e, 0x6f, 0x74, 0x52, 0x75, 0x6e, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07,
+	0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x6f, 0x6e,
+	0x65, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x05, 0x12, 0x0c,
+	0x0a, 0x08, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x10, 0x06, 0x1a, 0x00, 0x42, 0x27,
+	0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x72, 0x61,
+	0x6c, 0x69, 0x63, 0x6b, 0x79, 0x2f, 0x70, 0x6f, 0x73, 0x74, 0x2d, 0x69, 0x6e, 0x69, 0x74, 0x2f,
+	0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -486,26 +901,43 @@ func file_pkg_api_announce_proto_rawDescGZIP() []byte {
 	return file_pkg_api_announce_proto_rawDescData
 }
 
-var file_pkg_api_announce_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_pkg_api_announce_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_api_announce_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_pkg_api_announce_proto_goTypes = []interface{}{
-	(*Announcement)(nil),     // 0: api.Announcement
-	(*UnameInfo)(nil),        // 1: api.UnameInfo
-	(*NetworkInfo)(nil),      // 2: api.NetworkInfo
-	(*NetworkInterface)(nil), // 3: api.NetworkInterface
-	(*Addr)(nil),             // 4: api.Addr
-	(*AuthorizedKey)(nil),    // 5: api.AuthorizedKey
+	(CloudInitState)(0),         // 0: api.CloudInitState
+	(*Announcement)(nil),        // 1: api.Announcement
+	(*UnameInfo)(nil),           // 2: api.UnameInfo
+	(*NetworkInfo)(nil),         // 3: api.NetworkInfo
+	(*NetworkInterface)(nil),    // 4: api.NetworkInterface
+	(*Addr)(nil),                // 5: api.Addr
+	(*AuthorizedKey)(nil),       // 6: api.AuthorizedKey
+	(*CloudMetadata)(nil),       // 7: api.CloudMetadata
+	(*CloudInitStatus)(nil),     // 8: api.CloudInitStatus
+	(*StatusUpdate)(nil),        // 9: api.StatusUpdate
+	(*BootWait)(nil),            // 10: api.BootWait
+	nil,                         // 11: api.Announcement.LabelsEntry
+	nil,                         // 12: api.CloudMetadata.TagsEntry
+	(*durationpb.Duration)(nil), // 13: google.protobuf.Duration
 }
 var file_pkg_api_announce_proto_depIdxs = []int32{
-	1, // 0: api.Announcement.Uname:type_name -> api.UnameInfo
-	2, // 1: api.Announcement.Network:type_name -> api.NetworkInfo
-	5, // 2: api.Announcement.AuthorizedKeys:type_name -> api.AuthorizedKey
-	3, // 3: api.NetworkInfo.NetworkInterfaces:type_name -> api.NetworkInterface
-	4, // 4: api.NetworkInterface.Addresses:type_name -> api.Addr
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	2,  // 0: api.Announcement.Uname:type_name -> api.UnameInfo
+	3,  // 1: api.Announcement.Network:type_name -> api.NetworkInfo
+	6,  // 2: api.Announcement.AuthorizedKeys:type_name -> api.AuthorizedKey
+	11, // 3: api.Announcement.Labels:type_name -> api.Announcement.LabelsEntry
+	7,  // 4: api.Announcement.Cloud:type_name -> api.CloudMetadata
+	8,  // 5: api.Announcement.CloudInit:type_name -> api.CloudInitStatus
+	10, // 6: api.Announcement.BootWait:type_name -> api.BootWait
+	4,  // 7: api.NetworkInfo.NetworkInterfaces:type_name -> api.NetworkInterface
+	5,  // 8: api.NetworkInterface.Addresses:type_name -> api.Addr
+	12, // 9: api.CloudMetadata.Tags:type_name -> api.CloudMetadata.TagsEntry
+	0,  // 10: api.CloudInitStatus.State:type_name -> api.CloudInitState
+	8,  // 11: api.StatusUpdate.CloudInit:type_name -> api.CloudInitStatus
+	13, // 12: api.BootWait.Duration:type_name -> google.protobuf.Duration
+	13, // [13:13] is the sub-list for method output_type
+	13, // [13:13] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_pkg_api_announce_proto_init() }
@@ -586,19 +1018,68 @@ func file_pkg_api_announce_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_api_announce_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloudMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_announce_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloudInitStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_announce_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_announce_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BootWait); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_api_announce_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      1,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_pkg_api_announce_proto_goTypes,
 		DependencyIndexes: file_pkg_api_announce_proto_depIdxs,
+		EnumInfos:         file_pkg_api_announce_proto_enumTypes,
 		MessageInfos:      file_pkg_api_announce_proto_msgTypes,
 	}.Build()
 	File_pkg_api_announce_proto = out.File
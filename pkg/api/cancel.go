@@ -0,0 +1,73 @@
+package api
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CancelGracePeriod is how long a canceled instruction is given to report
+// that it was terminated before the call is abandoned.
+const CancelGracePeriod = 10 * time.Second
+
+// InstructionCanceler is implemented by both the client API and instruction
+// clients, which cancel instructions by their ID.
+type InstructionCanceler interface {
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+// RunCancelable calls an instruction with a context which is not canceled
+// along with ctx. Totem does not propagate cancellation, so if ctx is done
+// before the call returns, the instruction identified in meta is explicitly
+// canceled, and the call is given CancelGracePeriod to return the terminated
+// result.
+func RunCancelable(
+	ctx context.Context,
+	canceler InstructionCanceler,
+	meta *InstructionMeta,
+	call func(context.Context) error,
+) error {
+	return RunCanceling(ctx, func(ctx context.Context) error {
+		if _, err := canceler.Cancel(ctx, &CancelRequest{
+			Meta: meta,
+		}); err != nil {
+			return fmt.Errorf("instruction %s: %w", meta.GetInstructionID(), err)
+		}
+		return nil
+	}, call)
+}
+
+// RunCanceling is like RunCancelable, for calls which are canceled some other
+// way. If ctx is done before the call returns, cancel is called, and the call
+// is given CancelGracePeriod to return.
+func RunCanceling(
+	ctx context.Context,
+	cancel func(context.Context) error,
+	call func(context.Context) error,
+) error {
+	callCtx, ca := context.WithCancel(context.Background())
+	defer ca()
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-callCtx.Done():
+			return
+		}
+		if callCtx.Err() != nil {
+			// The call returned at the same time
+			return
+		}
+		cancelCtx, cancelCa := context.WithTimeout(callCtx, CancelGracePeriod)
+		defer cancelCa()
+		if err := cancel(cancelCtx); err != nil {
+			logrus.Debugf("Failed to cancel: %v", err)
+		}
+		<-cancelCtx.Done()
+		ca()
+	}()
+	return call(callCtx)
+}
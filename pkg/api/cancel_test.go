@@ -0,0 +1,70 @@
+package api
+
+import (
+	context "context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// cancelFunc cancels a running call when Cancel is called.
+type cancelFunc func(*CancelRequest)
+
+func (f cancelFunc) Cancel(_ context.Context, in *CancelRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+	f(in)
+	return &emptypb.Empty{}, nil
+}
+
+var _ = Describe("Cancelable instructions", func() {
+	meta := &InstructionMeta{InstructionID: "instruction"}
+
+	It("should not cancel instructions which return first", func() {
+		canceled := make(chan *CancelRequest, 1)
+		err := RunCancelable(context.Background(), cancelFunc(func(req *CancelRequest) {
+			canceled <- req
+		}), meta, func(ctx context.Context) error {
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Consistently(canceled, 100*time.Millisecond).ShouldNot(Receive())
+	})
+	It("should cancel the instruction when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		terminated := make(chan struct{})
+		err := RunCancelable(ctx, cancelFunc(func(req *CancelRequest) {
+			if req.Meta.GetInstructionID() == meta.InstructionID {
+				close(terminated)
+			}
+		}), meta, func(callCtx context.Context) error {
+			cancel()
+			// The call's context is not canceled along with ctx
+			select {
+			case <-terminated:
+				return callCtx.Err()
+			case <-time.After(5 * time.Second):
+				return context.DeadlineExceeded
+			}
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+	It("should call the cancel function when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan struct{})
+		err := RunCanceling(ctx, func(context.Context) error {
+			close(stopped)
+			return nil
+		}, func(callCtx context.Context) error {
+			cancel()
+			select {
+			case <-stopped:
+				return callCtx.Err()
+			case <-time.After(5 * time.Second):
+				return context.DeadlineExceeded
+			}
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ClientKey identifies a client by the key it authenticated with, for
+// authorization against authorized keys.
+type ClientKey struct {
+	// SHA256 fingerprint of the client's key. For certificates, this is the
+	// fingerprint of the certified key, so that it does not change when the
+	// certificate is renewed.
+	Fingerprint string
+	// Set if the client authenticated with an OpenSSH user certificate
+	Certificate *ssh.Certificate
+}
+
+// NewClientKey returns the client key for a verified public key, which may be
+// a certificate.
+func NewClientKey(key ssh.PublicKey) *ClientKey {
+	if cert, ok := key.(*ssh.Certificate); ok {
+		return &ClientKey{
+			Fingerprint: ssh.FingerprintSHA256(cert.Key),
+			Certificate: cert,
+		}
+	}
+	return &ClientKey{
+		Fingerprint: ssh.FingerprintSHA256(key),
+	}
+}
+
+// ParseClientKey returns the client key with the given fingerprint, and the
+// given certificate in SSH wire format, if any. The certificate must certify
+// the key with the fingerprint.
+func ParseClientKey(fingerprint string, certificate []byte) (*ClientKey, error) {
+	client := &ClientKey{
+		Fingerprint: fingerprint,
+	}
+	if len(certificate) == 0 {
+		return client, nil
+	}
+	key, err := ssh.ParsePublicKey(certificate)
+	if err != nil {
+		return nil, err
+	}
+	cert, ok := key.(*ssh.Certificate)
+	if !ok {
+		return nil, errors.New("not a certificate")
+	}
+	if ssh.FingerprintSHA256(cert.Key) != fingerprint {
+		return nil, errors.New("certificate does not match the client key")
+	}
+	client.Certificate = cert
+	return client, nil
+}
+
+// MarshalCertificate returns the client's certificate in SSH wire format, or
+// nil if the client did not authenticate with a certificate.
+func (c *ClientKey) MarshalCertificate() []byte {
+	if c.Certificate == nil {
+		return nil
+	}
+	return c.Certificate.Marshal()
+}
+
+// ClientKey returns the key of the client which sent the instruction.
+func (m *InstructionMeta) ClientKey() (*ClientKey, error) {
+	return ParseClientKey(m.GetClientFingerprint(), m.GetClientCertificate())
+}
+
+// OwnerKey returns the key of the client which created the job.
+func (j *Job) OwnerKey() (*ClientKey, error) {
+	return ParseClientKey(j.GetOwner(), j.GetOwnerCertificate())
+}
+
+// Authorizes returns true if the entry authorizes the client. Entries with
+// the cert-authority option authorize certificates signed by the entry's key
+// which are valid for one of the entry's principals, and other entries
+// authorize their own key. Only the certificate's signer and principals are
+// checked, see KeyOptions.Check for the rest.
+func (k *AuthorizedKey) Authorizes(client *ClientKey) bool {
+	if !k.isCertAuthority() {
+		return k.Fingerprint == client.Fingerprint
+	}
+	_, ok := k.certificatePrincipal(client.Certificate)
+	return ok
+}
+
+// ClientOptions returns the options which apply when the entry authorizes
+// the client. For certificates, these are combined with the certificate's
+// force-command critical option and permit-* extensions like sshd does, and
+// KeyOptions.Check also checks the certificate.
+func (k *AuthorizedKey) ClientOptions(client *ClientKey) (*KeyOptions, error) {
+	opts, err := k.ParseOptions()
+	if err != nil {
+		return nil, err
+	}
+	if !opts.CertAuthority {
+		return opts, nil
+	}
+	principal, ok := k.certificatePrincipal(client.Certificate)
+	if !ok {
+		return nil, errors.New("certificate is not authorized by this key")
+	}
+	cert := client.Certificate
+	opts.certificate = cert
+	opts.principal = principal
+	if command, ok := cert.CriticalOptions["force-command"]; ok {
+		if opts.Command != "" && opts.Command != command {
+			return nil, errors.New("forced commands of the key and certificate do not match")
+		}
+		opts.Command = command
+	}
+	if _, ok := cert.Extensions["permit-pty"]; !ok {
+		opts.NoPty = true
+	}
+	if _, ok := cert.Extensions["permit-port-forwarding"]; !ok {
+		opts.NoPortForwarding = true
+	}
+	return opts, nil
+}
+
+func (k *AuthorizedKey) isCertAuthority() bool {
+	opts, err := k.ParseOptions()
+	return err == nil && opts.CertAuthority
+}
+
+// certificatePrincipal returns the principal for which the cert-authority
+// entry authorizes the certificate, which is the entry's user unless the
+// entry lists its principals. The certificate must be signed by the entry's
+// key.
+func (k *AuthorizedKey) certificatePrincipal(cert *ssh.Certificate) (string, bool) {
+	if cert == nil || ssh.FingerprintSHA256(cert.SignatureKey) != k.Fingerprint {
+		return "", false
+	}
+	opts, err := k.ParseOptions()
+	if err != nil || !opts.CertAuthority {
+		return "", false
+	}
+	allowed := opts.Principals
+	if len(allowed) == 0 {
+		allowed = []string{k.User}
+	}
+	for _, p := range cert.ValidPrincipals {
+		for _, a := range allowed {
+			if p != "" && p == a {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+// CheckCertificate checks that the certificate is a user certificate with a
+// valid signature, that it is valid at the given time and for the given
+// principal, if any, and that its critical options are supported and allow a
+// client connecting from the given address. It does not check whether the
+// certificate's signer is trusted.
+func CheckCertificate(cert *ssh.Certificate, principal, address string, now time.Time) error {
+	if cert.CertType != ssh.UserCert {
+		return errors.New("not a user certificate")
+	}
+	if len(cert.ValidPrincipals) == 0 {
+		return errors.New("certificate has no principals")
+	}
+	if principal == "" {
+		// CertChecker always checks the principal
+		principal = cert.ValidPrincipals[0]
+	}
+	checker := &ssh.CertChecker{
+		SupportedCriticalOptions: []string{"force-command", "source-address"},
+		Clock: func() time.Time {
+			return now
+		},
+	}
+	if err := checker.CheckCert(principal, cert); err != nil {
+		return err
+	}
+	if sources, ok := cert.CriticalOptions["source-address"]; ok {
+		m, err := parseIPMatcher(sources)
+		if err != nil {
+			return fmt.Errorf("invalid source-address in certificate: %w", err)
+		}
+		ip := net.ParseIP(address)
+		if ip == nil || !m.matches(ip) {
+			return errors.New("certificate is not valid from the client's address")
+		}
+	}
+	return nil
+}
+
+// IsSignedBy returns true if the certificate was signed by one of the given
+// CA keys.
+func IsSignedBy(cert *ssh.Certificate, cas []ssh.PublicKey) bool {
+	signer := cert.SignatureKey.Marshal()
+	for _, ca := range cas {
+		if bytes.Equal(ca.Marshal(), signer) {
+			return true
+		}
+	}
+	return false
+}
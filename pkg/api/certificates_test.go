@@ -0,0 +1,147 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+)
+
+var _ = Describe("Certificates", func() {
+	newSigner := func() ssh.Signer {
+		_, priv, _ := ed25519.GenerateKey(rand.Reader)
+		signer, _ := ssh.NewSignerFromKey(priv)
+		return signer
+	}
+	ca, otherCA, user := newSigner(), newSigner(), newSigner()
+	now := time.Now()
+	newCert := func(ca ssh.Signer, modify func(*ssh.Certificate)) *ssh.Certificate {
+		cert := &ssh.Certificate{
+			Key:             user.PublicKey(),
+			CertType:        ssh.UserCert,
+			KeyId:           "test",
+			ValidPrincipals: []string{"deploy"},
+			ValidAfter:      uint64(now.Add(-time.Minute).Unix()),
+			ValidBefore:     uint64(now.Add(time.Hour).Unix()),
+			Permissions: ssh.Permissions{
+				CriticalOptions: map[string]string{},
+				Extensions:      map[string]string{"permit-pty": ""},
+			},
+		}
+		if modify != nil {
+			modify(cert)
+		}
+		Expect(cert.SignCert(rand.Reader, ca)).To(Succeed())
+		return cert
+	}
+	caEntry := func(options ...string) *AuthorizedKey {
+		return &AuthorizedKey{
+			User:        "deploy",
+			Fingerprint: ssh.FingerprintSHA256(ca.PublicKey()),
+			Options:     append([]string{"cert-authority"}, options...),
+		}
+	}
+
+	It("should identify certificates by the certified key", func() {
+		cert := newCert(ca, nil)
+		client := NewClientKey(cert)
+		Expect(client.Fingerprint).To(Equal(ssh.FingerprintSHA256(user.PublicKey())))
+		Expect(client.Certificate).To(Equal(cert))
+
+		parsed, err := ParseClientKey(client.Fingerprint, client.MarshalCertificate())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.Certificate.Marshal()).To(Equal(cert.Marshal()))
+
+		_, err = ParseClientKey(ssh.FingerprintSHA256(ca.PublicKey()), client.MarshalCertificate())
+		Expect(err).To(HaveOccurred())
+		_, err = ParseClientKey(client.Fingerprint, user.PublicKey().Marshal())
+		Expect(err).To(HaveOccurred())
+	})
+	It("should authorize certificates signed by cert-authority keys", func() {
+		Expect(caEntry().Authorizes(NewClientKey(newCert(ca, nil)))).To(BeTrue())
+		Expect(caEntry().Authorizes(NewClientKey(newCert(otherCA, nil)))).To(BeFalse())
+		Expect(caEntry().Authorizes(NewClientKey(newCert(ca, func(c *ssh.Certificate) {
+			c.ValidPrincipals = []string{"admin"}
+		})))).To(BeFalse())
+		Expect(caEntry(`principals="admin,ops"`).Authorizes(NewClientKey(newCert(ca, func(c *ssh.Certificate) {
+			c.ValidPrincipals = []string{"ops"}
+		})))).To(BeTrue())
+		Expect(caEntry().Authorizes(NewClientKey(ca.PublicKey()))).To(BeFalse())
+	})
+	It("should authorize the certified key with plain entries", func() {
+		entry := &AuthorizedKey{
+			User:        "root",
+			Fingerprint: ssh.FingerprintSHA256(user.PublicKey()),
+		}
+		Expect(entry.Authorizes(NewClientKey(newCert(otherCA, nil)))).To(BeTrue())
+	})
+	It("should combine options with the certificate", func() {
+		opts, err := caEntry().ClientOptions(NewClientKey(newCert(ca, func(c *ssh.Certificate) {
+			c.CriticalOptions["force-command"] = "uptime"
+		})))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts.Command).To(Equal("uptime"))
+		Expect(opts.NoPty).To(BeFalse())
+		Expect(opts.NoPortForwarding).To(BeTrue())
+		Expect(opts.Check("127.0.0.1", now)).To(Succeed())
+		Expect(opts.Check("127.0.0.1", now.Add(2*time.Hour))).NotTo(Succeed())
+
+		_, err = caEntry(`command="id"`).ClientOptions(NewClientKey(newCert(ca, func(c *ssh.Certificate) {
+			c.CriticalOptions["force-command"] = "uptime"
+		})))
+		Expect(err).To(HaveOccurred())
+
+		_, err = caEntry().ClientOptions(NewClientKey(newCert(otherCA, nil)))
+		Expect(err).To(HaveOccurred())
+	})
+	DescribeTable("invalid certificates",
+		func(modify func(*ssh.Certificate)) {
+			Expect(CheckCertificate(newCert(ca, modify), "", "10.0.0.1", now)).NotTo(Succeed())
+		},
+		Entry("expired", func(c *ssh.Certificate) {
+			c.ValidBefore = uint64(now.Add(-time.Second).Unix())
+		}),
+		Entry("not yet valid", func(c *ssh.Certificate) {
+			c.ValidAfter = uint64(now.Add(time.Minute).Unix())
+		}),
+		Entry("host certificate", func(c *ssh.Certificate) {
+			c.CertType = ssh.HostCert
+		}),
+		Entry("no principals", func(c *ssh.Certificate) {
+			c.ValidPrincipals = nil
+		}),
+		Entry("unsupported critical option", func(c *ssh.Certificate) {
+			c.CriticalOptions["verify-required"] = ""
+		}),
+		Entry("source address", func(c *ssh.Certificate) {
+			c.CriticalOptions["source-address"] = "192.168.0.0/16"
+		}),
+	)
+	It("should check the principal and source address", func() {
+		cert := newCert(ca, func(c *ssh.Certificate) {
+			c.CriticalOptions["source-address"] = "10.0.0.0/8"
+		})
+		Expect(CheckCertificate(cert, "deploy", "10.0.0.1", now)).To(Succeed())
+		Expect(CheckCertificate(cert, "admin", "10.0.0.1", now)).NotTo(Succeed())
+	})
+	It("should run as the user which authorizes the certificate", func() {
+		an := &Announcement{
+			AgentUser:      "root",
+			AuthorizedKeys: []*AuthorizedKey{caEntry()},
+		}
+		user, err := an.RunAsUser(NewClientKey(newCert(ca, nil)), "", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user).To(Equal("deploy"))
+		_, err = an.RunAsUser(NewClientKey(newCert(otherCA, nil)), "", "")
+		Expect(err).To(HaveOccurred())
+	})
+	It("should check certificate signers", func() {
+		cert := newCert(ca, nil)
+		Expect(IsSignedBy(cert, []ssh.PublicKey{otherCA.PublicKey(), ca.PublicKey()})).To(BeTrue())
+		Expect(IsSignedBy(cert, []ssh.PublicKey{otherCA.PublicKey()})).To(BeFalse())
+		Expect(IsSignedBy(cert, nil)).To(BeFalse())
+	})
+})
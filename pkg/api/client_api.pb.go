@@ -9,7 +9,9 @@ package api
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -67,6 +69,61 @@ func (Operator) EnumDescriptor() ([]byte, []int) {
 	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{0}
 }
 
+type WatchEventType int32
+
+const (
+	WatchEventType_Connected     WatchEventType = 0
+	WatchEventType_Disconnected  WatchEventType = 1
+	WatchEventType_Reannounced   WatchEventType = 2
+	WatchEventType_StatusChanged WatchEventType = 3
+	WatchEventType_Closed        WatchEventType = 4
+)
+
+// Enum value maps for WatchEventType.
+var (
+	WatchEventType_name = map[int32]string{
+		0: "Connected",
+		1: "Disconnected",
+		2: "Reannounced",
+		3: "StatusChanged",
+		4: "Closed",
+	}
+	WatchEventType_value = map[string]int32{
+		"Connected":     0,
+		"Disconnected":  1,
+		"Reannounced":   2,
+		"StatusChanged": 3,
+		"Closed":        4,
+	}
+)
+
+func (x WatchEventType) Enum() *WatchEventType {
+	p := new(WatchEventType)
+	*p = x
+	return p
+}
+
+func (x WatchEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_api_client_api_proto_enumTypes[1].Descriptor()
+}
+
+func (WatchEventType) Type() protoreflect.EnumType {
+	return &file_pkg_api_client_api_proto_enumTypes[1]
+}
+
+func (x WatchEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchEventType.Descriptor instead.
+func (WatchEventType) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{1}
+}
+
 type ConnectionRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -152,16 +209,22 @@ func (*ConnectionResponse) Descriptor() ([]byte, []int) {
 	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{1}
 }
 
-type WatchRequest struct {
+type WatchEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Filter *BasicFilter `protobuf:"bytes,1,opt,name=Filter,proto3" json:"Filter,omitempty"`
+	Type              WatchEventType         `protobuf:"varint,1,opt,name=Type,proto3,enum=api.WatchEventType" json:"Type,omitempty"`
+	Fingerprint       string                 `protobuf:"bytes,2,opt,name=Fingerprint,proto3" json:"Fingerprint,omitempty"`
+	Announcement      *Announcement          `protobuf:"bytes,3,opt,name=Announcement,proto3" json:"Announcement,omitempty"`
+	Time              *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=Time,proto3" json:"Time,omitempty"`
+	Reason            string                 `protobuf:"bytes,5,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	PreviouslyMatched bool                   `protobuf:"varint,6,opt,name=PreviouslyMatched,proto3" json:"PreviouslyMatched,omitempty"`
+	WatchID           string                 `protobuf:"bytes,7,opt,name=WatchID,proto3" json:"WatchID,omitempty"`
 }
 
-func (x *WatchRequest) Reset() {
-	*x = WatchRequest{}
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_api_client_api_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -169,13 +232,13 @@ func (x *WatchRequest) Reset() {
 	}
 }
 
-func (x *WatchRequest) String() string {
+func (x *WatchEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchRequest) ProtoMessage() {}
+func (*WatchEvent) ProtoMessage() {}
 
-func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_api_client_api_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -187,31 +250,72 @@ func (x *WatchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
-func (*WatchRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
 	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *WatchRequest) GetFilter() *BasicFilter {
+func (x *WatchEvent) GetType() WatchEventType {
 	if x != nil {
-		return x.Filter
+		return x.Type
+	}
+	return WatchEventType_Connected
+}
+
+func (x *WatchEvent) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetAnnouncement() *Announcement {
+	if x != nil {
+		return x.Announcement
 	}
 	return nil
 }
 
-type BasicFilter struct {
+func (x *WatchEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetPreviouslyMatched() bool {
+	if x != nil {
+		return x.PreviouslyMatched
+	}
+	return false
+}
+
+func (x *WatchEvent) GetWatchID() string {
+	if x != nil {
+		return x.WatchID
+	}
+	return ""
+}
+
+type WatchRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Operator         Operator `protobuf:"varint,1,opt,name=Operator,proto3,enum=api.Operator" json:"Operator,omitempty"`
-	HasAuthorizedKey string   `protobuf:"bytes,2,opt,name=HasAuthorizedKey,proto3" json:"HasAuthorizedKey,omitempty"`
-	HasIPAddress     string   `protobuf:"bytes,3,opt,name=HasIPAddress,proto3" json:"HasIPAddress,omitempty"`
-	HasHostname      string   `protobuf:"bytes,4,opt,name=HasHostname,proto3" json:"HasHostname,omitempty"`
+	Filter     *BasicFilter `protobuf:"bytes,1,opt,name=Filter,proto3" json:"Filter,omitempty"`
+	Expression *Expression  `protobuf:"bytes,2,opt,name=Expression,proto3" json:"Expression,omitempty"`
+	WatchID    string       `protobuf:"bytes,3,opt,name=WatchID,proto3" json:"WatchID,omitempty"`
 }
 
-func (x *BasicFilter) Reset() {
-	*x = BasicFilter{}
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_api_client_api_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -219,13 +323,13 @@ func (x *BasicFilter) Reset() {
 	}
 }
 
-func (x *BasicFilter) String() string {
+func (x *WatchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BasicFilter) ProtoMessage() {}
+func (*WatchRequest) ProtoMessage() {}
 
-func (x *BasicFilter) ProtoReflect() protoreflect.Message {
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_api_client_api_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -237,49 +341,42 @@ func (x *BasicFilter) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BasicFilter.ProtoReflect.Descriptor instead.
-func (*BasicFilter) Descriptor() ([]byte, []int) {
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
 	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *BasicFilter) GetOperator() Operator {
-	if x != nil {
-		return x.Operator
-	}
-	return Operator_And
-}
-
-func (x *BasicFilter) GetHasAuthorizedKey() string {
+func (x *WatchRequest) GetFilter() *BasicFilter {
 	if x != nil {
-		return x.HasAuthorizedKey
+		return x.Filter
 	}
-	return ""
+	return nil
 }
 
-func (x *BasicFilter) GetHasIPAddress() string {
+func (x *WatchRequest) GetExpression() *Expression {
 	if x != nil {
-		return x.HasIPAddress
+		return x.Expression
 	}
-	return ""
+	return nil
 }
 
-func (x *BasicFilter) GetHasHostname() string {
+func (x *WatchRequest) GetWatchID() string {
 	if x != nil {
-		return x.HasHostname
+		return x.WatchID
 	}
 	return ""
 }
 
-type KexRequest struct {
+type WatchReference struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ServerEphemeralPublicKey []byte `protobuf:"bytes,1,opt,name=ServerEphemeralPublicKey,proto3" json:"ServerEphemeralPublicKey,omitempty"`
+	WatchID string `protobuf:"bytes,1,opt,name=WatchID,proto3" json:"WatchID,omitempty"`
 }
 
-func (x *KexRequest) Reset() {
-	*x = KexRequest{}
+func (x *WatchReference) Reset() {
+	*x = WatchReference{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_api_client_api_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -287,13 +384,13 @@ func (x *KexRequest) Reset() {
 	}
 }
 
-func (x *KexRequest) String() string {
+func (x *WatchReference) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KexRequest) ProtoMessage() {}
+func (*WatchReference) ProtoMessage() {}
 
-func (x *KexRequest) ProtoReflect() protoreflect.Message {
+func (x *WatchReference) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_api_client_api_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -305,28 +402,31 @@ func (x *KexRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KexRequest.ProtoReflect.Descriptor instead.
-func (*KexRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use WatchReference.ProtoReflect.Descriptor instead.
+func (*WatchReference) Descriptor() ([]byte, []int) {
 	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *KexRequest) GetServerEphemeralPublicKey() []byte {
+func (x *WatchReference) GetWatchID() string {
 	if x != nil {
-		return x.ServerEphemeralPublicKey
+		return x.WatchID
 	}
-	return nil
+	return ""
 }
 
-type KexResponse struct {
+type BasicFilter struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ClientEphemeralPublicKey []byte `protobuf:"bytes,1,opt,name=ClientEphemeralPublicKey,proto3" json:"ClientEphemeralPublicKey,omitempty"`
+	Operator         Operator `protobuf:"varint,1,opt,name=Operator,proto3,enum=api.Operator" json:"Operator,omitempty"`
+	HasAuthorizedKey string   `protobuf:"bytes,2,opt,name=HasAuthorizedKey,proto3" json:"HasAuthorizedKey,omitempty"`
+	HasIPAddress     string   `protobuf:"bytes,3,opt,name=HasIPAddress,proto3" json:"HasIPAddress,omitempty"`
+	HasHostname      string   `protobuf:"bytes,4,opt,name=HasHostname,proto3" json:"HasHostname,omitempty"`
 }
 
-func (x *KexResponse) Reset() {
-	*x = KexResponse{}
+func (x *BasicFilter) Reset() {
+	*x = BasicFilter{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_api_client_api_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -334,13 +434,13 @@ func (x *KexResponse) Reset() {
 	}
 }
 
-func (x *KexResponse) String() string {
+func (x *BasicFilter) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KexResponse) ProtoMessage() {}
+func (*BasicFilter) ProtoMessage() {}
 
-func (x *KexResponse) ProtoReflect() protoreflect.Message {
+func (x *BasicFilter) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_api_client_api_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -352,28 +452,62 @@ func (x *KexResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KexResponse.ProtoReflect.Descriptor instead.
-func (*KexResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BasicFilter.ProtoReflect.Descriptor instead.
+func (*BasicFilter) Descriptor() ([]byte, []int) {
 	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *KexResponse) GetClientEphemeralPublicKey() []byte {
+func (x *BasicFilter) GetOperator() Operator {
 	if x != nil {
-		return x.ClientEphemeralPublicKey
+		return x.Operator
 	}
-	return nil
+	return Operator_And
 }
 
-type SignRequest struct {
+func (x *BasicFilter) GetHasAuthorizedKey() string {
+	if x != nil {
+		return x.HasAuthorizedKey
+	}
+	return ""
+}
+
+func (x *BasicFilter) GetHasIPAddress() string {
+	if x != nil {
+		return x.HasIPAddress
+	}
+	return ""
+}
+
+func (x *BasicFilter) GetHasHostname() string {
+	if x != nil {
+		return x.HasHostname
+	}
+	return ""
+}
+
+type Expression struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Nonce []byte `protobuf:"bytes,1,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	// Types that are assignable to Expr:
+	//	*Expression_And
+	//	*Expression_Or
+	//	*Expression_Not
+	//	*Expression_Hostname
+	//	*Expression_IPAddress
+	//	*Expression_AuthorizedKey
+	//	*Expression_KernelName
+	//	*Expression_KernelRelease
+	//	*Expression_Machine
+	//	*Expression_Label
+	//	*Expression_Cloud
+	//	*Expression_CloudInit
+	Expr isExpression_Expr `protobuf_oneof:"Expr"`
 }
 
-func (x *SignRequest) Reset() {
-	*x = SignRequest{}
+func (x *Expression) Reset() {
+	*x = Expression{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_api_client_api_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -381,13 +515,13 @@ func (x *SignRequest) Reset() {
 	}
 }
 
-func (x *SignRequest) String() string {
+func (x *Expression) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignRequest) ProtoMessage() {}
+func (*Expression) ProtoMessage() {}
 
-func (x *SignRequest) ProtoReflect() protoreflect.Message {
+func (x *Expression) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_api_client_api_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -399,202 +533,2759 @@ func (x *SignRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignRequest.ProtoReflect.Descriptor instead.
-func (*SignRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Expression.ProtoReflect.Descriptor instead.
+func (*Expression) Descriptor() ([]byte, []int) {
 	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *SignRequest) GetNonce() []byte {
-	if x != nil {
-		return x.Nonce
+func (m *Expression) GetExpr() isExpression_Expr {
+	if m != nil {
+		return m.Expr
 	}
 	return nil
 }
 
-type SignResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *Expression) GetAnd() *ExpressionList {
+	if x, ok := x.GetExpr().(*Expression_And); ok {
+		return x.And
+	}
+	return nil
+}
 
-	Signature []byte `protobuf:"bytes,1,opt,name=Signature,proto3" json:"Signature,omitempty"`
+func (x *Expression) GetOr() *ExpressionList {
+	if x, ok := x.GetExpr().(*Expression_Or); ok {
+		return x.Or
+	}
+	return nil
 }
 
-func (x *SignResponse) Reset() {
-	*x = SignResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_api_client_api_proto_msgTypes[7]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *Expression) GetNot() *Expression {
+	if x, ok := x.GetExpr().(*Expression_Not); ok {
+		return x.Not
 	}
+	return nil
 }
 
-func (x *SignResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Expression) GetHostname() *StringMatch {
+	if x, ok := x.GetExpr().(*Expression_Hostname); ok {
+		return x.Hostname
+	}
+	return nil
 }
 
-func (*SignResponse) ProtoMessage() {}
+func (x *Expression) GetIPAddress() string {
+	if x, ok := x.GetExpr().(*Expression_IPAddress); ok {
+		return x.IPAddress
+	}
+	return ""
+}
 
-func (x *SignResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_client_api_proto_msgTypes[7]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *Expression) GetAuthorizedKey() string {
+	if x, ok := x.GetExpr().(*Expression_AuthorizedKey); ok {
+		return x.AuthorizedKey
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use SignResponse.ProtoReflect.Descriptor instead.
-func (*SignResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{7}
+func (x *Expression) GetKernelName() *StringMatch {
+	if x, ok := x.GetExpr().(*Expression_KernelName); ok {
+		return x.KernelName
+	}
+	return nil
 }
 
-func (x *SignResponse) GetSignature() []byte {
-	if x != nil {
-		return x.Signature
+func (x *Expression) GetKernelRelease() *StringMatch {
+	if x, ok := x.GetExpr().(*Expression_KernelRelease); ok {
+		return x.KernelRelease
 	}
 	return nil
 }
 
-var File_pkg_api_client_api_proto protoreflect.FileDescriptor
+func (x *Expression) GetMachine() *StringMatch {
+	if x, ok := x.GetExpr().(*Expression_Machine); ok {
+		return x.Machine
+	}
+	return nil
+}
 
-var file_pkg_api_client_api_proto_rawDesc = []byte{
-	0x0a, 0x18, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
-	0x5f, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x61, 0x70, 0x69, 0x1a,
-	0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1a, 0x70, 0x6b,
-	0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x69, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70,
-	0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x22, 0x30, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x0f, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x43,
-	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00,
-	0x3a, 0x00, 0x22, 0x16, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x3a, 0x00, 0x22, 0x34, 0x0a, 0x0c, 0x57, 0x61,
-	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x06, 0x46, 0x69,
-	0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69,
-	0x2e, 0x42, 0x61, 0x73, 0x69, 0x63, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x42, 0x00, 0x3a, 0x00,
-	0x22, 0x7d, 0x0a, 0x0b, 0x42, 0x61, 0x73, 0x69, 0x63, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12,
-	0x21, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
-	0x42, 0x00, 0x12, 0x1a, 0x0a, 0x10, 0x48, 0x61, 0x73, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69,
-	0x7a, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x16,
-	0x0a, 0x0c, 0x48, 0x61, 0x73, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x15, 0x0a, 0x0b, 0x48, 0x61, 0x73, 0x48, 0x6f, 0x73,
-	0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22,
-	0x32, 0x0a, 0x0a, 0x4b, 0x65, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a,
-	0x18, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x70, 0x68, 0x65, 0x6d, 0x65, 0x72, 0x61, 0x6c,
-	0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x42,
-	0x00, 0x3a, 0x00, 0x22, 0x33, 0x0a, 0x0b, 0x4b, 0x65, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x22, 0x0a, 0x18, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x45, 0x70, 0x68, 0x65,
-	0x6d, 0x65, 0x72, 0x61, 0x6c, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x20, 0x0a, 0x0b, 0x53, 0x69, 0x67, 0x6e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0f, 0x0a, 0x05, 0x4e, 0x6f, 0x6e, 0x63, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x25, 0x0a, 0x0c, 0x53, 0x69,
-	0x67, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x13, 0x0a, 0x09, 0x53, 0x69,
-	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x3a,
-	0x00, 0x2a, 0x1d, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x07, 0x0a,
-	0x03, 0x41, 0x6e, 0x64, 0x10, 0x00, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x72, 0x10, 0x01, 0x1a, 0x00,
-	0x32, 0x84, 0x02, 0x0a, 0x09, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x12, 0x40,
-	0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x2e,
-	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00,
-	0x12, 0x38, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e,
-	0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
-	0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3d, 0x0a, 0x0a, 0x52, 0x75,
-	0x6e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43,
-	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
-	0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3a, 0x0a, 0x09, 0x52, 0x75, 0x6e,
-	0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69,
-	0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x28, 0x00, 0x30, 0x00, 0x1a, 0x00, 0x32, 0x7b, 0x0a, 0x0b, 0x4b, 0x65, 0x79, 0x45, 0x78,
-	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x37, 0x0a, 0x0c, 0x45, 0x78, 0x63, 0x68, 0x61, 0x6e,
-	0x67, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4b, 0x65, 0x78,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4b, 0x65,
-	0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12,
-	0x31, 0x0a, 0x04, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x69,
-	0x67, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e,
-	0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00,
-	0x30, 0x00, 0x1a, 0x00, 0x32, 0x44, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x39, 0x0a,
-	0x06, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6e,
-	0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x1a, 0x00, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x72, 0x61, 0x6c, 0x69, 0x63, 0x6b,
-	0x79, 0x2f, 0x70, 0x6f, 0x73, 0x74, 0x2d, 0x69, 0x6e, 0x69, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
-	0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *Expression) GetLabel() *LabelMatch {
+	if x, ok := x.GetExpr().(*Expression_Label); ok {
+		return x.Label
+	}
+	return nil
 }
 
-var (
-	file_pkg_api_client_api_proto_rawDescOnce sync.Once
-	file_pkg_api_client_api_proto_rawDescData = file_pkg_api_client_api_proto_rawDesc
-)
+func (x *Expression) GetCloud() *CloudMatch {
+	if x, ok := x.GetExpr().(*Expression_Cloud); ok {
+		return x.Cloud
+	}
+	return nil
+}
 
-func file_pkg_api_client_api_proto_rawDescGZIP() []byte {
-	file_pkg_api_client_api_proto_rawDescOnce.Do(func() {
-		file_pkg_api_client_api_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_api_client_api_proto_rawDescData)
-	})
-	return file_pkg_api_client_api_proto_rawDescData
+func (x *Expression) GetCloudInit() CloudInitState {
+	if x, ok := x.GetExpr().(*Expression_CloudInit); ok {
+		return x.CloudInit
+	}
+	return CloudInitState_Unknown
 }
 
-var file_pkg_api_client_api_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pkg_api_client_api_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
-var file_pkg_api_client_api_proto_goTypes = []interface{}{
-	(Operator)(0),              // 0: api.Operator
-	(*ConnectionRequest)(nil),  // 1: api.ConnectionRequest
-	(*ConnectionResponse)(nil), // 2: api.ConnectionResponse
-	(*WatchRequest)(nil),       // 3: api.WatchRequest
-	(*BasicFilter)(nil),        // 4: api.BasicFilter
-	(*KexRequest)(nil),         // 5: api.KexRequest
-	(*KexResponse)(nil),        // 6: api.KexResponse
-	(*SignRequest)(nil),        // 7: api.SignRequest
-	(*SignResponse)(nil),       // 8: api.SignResponse
-	(*CommandRequest)(nil),     // 9: api.CommandRequest
-	(*ScriptRequest)(nil),      // 10: api.ScriptRequest
-	(*Announcement)(nil),       // 11: api.Announcement
-	(*emptypb.Empty)(nil),      // 12: google.protobuf.Empty
-	(*CommandResponse)(nil),    // 13: api.CommandResponse
-	(*ScriptResponse)(nil),     // 14: api.ScriptResponse
+type isExpression_Expr interface {
+	isExpression_Expr()
 }
-var file_pkg_api_client_api_proto_depIdxs = []int32{
-	4,  // 0: api.WatchRequest.Filter:type_name -> api.BasicFilter
-	0,  // 1: api.BasicFilter.Operator:type_name -> api.Operator
-	1,  // 2: api.ClientAPI.Connect:input_type -> api.ConnectionRequest
-	3,  // 3: api.ClientAPI.Watch:input_type -> api.WatchRequest
-	9,  // 4: api.ClientAPI.RunCommand:input_type -> api.CommandRequest
-	10, // 5: api.ClientAPI.RunScript:input_type -> api.ScriptRequest
-	5,  // 6: api.KeyExchange.ExchangeKeys:input_type -> api.KexRequest
-	7,  // 7: api.KeyExchange.Sign:input_type -> api.SignRequest
-	11, // 8: api.Watch.Notify:input_type -> api.Announcement
-	2,  // 9: api.ClientAPI.Connect:output_type -> api.ConnectionResponse
-	12, // 10: api.ClientAPI.Watch:output_type -> google.protobuf.Empty
-	13, // 11: api.ClientAPI.RunCommand:output_type -> api.CommandResponse
-	14, // 12: api.ClientAPI.RunScript:output_type -> api.ScriptResponse
-	6,  // 13: api.KeyExchange.ExchangeKeys:output_type -> api.KexResponse
-	8,  // 14: api.KeyExchange.Sign:output_type -> api.SignResponse
-	12, // 15: api.Watch.Notify:output_type -> google.protobuf.Empty
-	9,  // [9:16] is the sub-list for method output_type
-	2,  // [2:9] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+
+type Expression_And struct {
+	And *ExpressionList `protobuf:"bytes,1,opt,name=And,proto3,oneof"`
 }
 
-func init() { file_pkg_api_client_api_proto_init() }
-func file_pkg_api_client_api_proto_init() {
-	if File_pkg_api_client_api_proto != nil {
-		return
-	}
-	file_pkg_api_instructions_proto_init()
-	file_pkg_api_announce_proto_init()
-	if !protoimpl.UnsafeEnabled {
-		file_pkg_api_client_api_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+type Expression_Or struct {
+	Or *ExpressionList `protobuf:"bytes,2,opt,name=Or,proto3,oneof"`
+}
+
+type Expression_Not struct {
+	Not *Expression `protobuf:"bytes,3,opt,name=Not,proto3,oneof"`
+}
+
+type Expression_Hostname struct {
+	Hostname *StringMatch `protobuf:"bytes,4,opt,name=Hostname,proto3,oneof"`
+}
+
+type Expression_IPAddress struct {
+	IPAddress string `protobuf:"bytes,5,opt,name=IPAddress,proto3,oneof"`
+}
+
+type Expression_AuthorizedKey struct {
+	AuthorizedKey string `protobuf:"bytes,6,opt,name=AuthorizedKey,proto3,oneof"`
+}
+
+type Expression_KernelName struct {
+	KernelName *StringMatch `protobuf:"bytes,7,opt,name=KernelName,proto3,oneof"`
+}
+
+type Expression_KernelRelease struct {
+	KernelRelease *StringMatch `protobuf:"bytes,8,opt,name=KernelRelease,proto3,oneof"`
+}
+
+type Expression_Machine struct {
+	Machine *StringMatch `protobuf:"bytes,9,opt,name=Machine,proto3,oneof"`
+}
+
+type Expression_Label struct {
+	Label *LabelMatch `protobuf:"bytes,10,opt,name=Label,proto3,oneof"`
+}
+
+type Expression_Cloud struct {
+	Cloud *CloudMatch `protobuf:"bytes,11,opt,name=Cloud,proto3,oneof"`
+}
+
+type Expression_CloudInit struct {
+	CloudInit CloudInitState `protobuf:"varint,12,opt,name=CloudInit,proto3,enum=api.CloudInitState,oneof"`
+}
+
+func (*Expression_And) isExpression_Expr() {}
+
+func (*Expression_Or) isExpression_Expr() {}
+
+func (*Expression_Not) isExpression_Expr() {}
+
+func (*Expression_Hostname) isExpression_Expr() {}
+
+func (*Expression_IPAddress) isExpression_Expr() {}
+
+func (*Expression_AuthorizedKey) isExpression_Expr() {}
+
+func (*Expression_KernelName) isExpression_Expr() {}
+
+func (*Expression_KernelRelease) isExpression_Expr() {}
+
+func (*Expression_Machine) isExpression_Expr() {}
+
+func (*Expression_Label) isExpression_Expr() {}
+
+func (*Expression_Cloud) isExpression_Expr() {}
+
+func (*Expression_CloudInit) isExpression_Expr() {}
+
+type CloudMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Field:
+	//	*CloudMatch_Provider
+	//	*CloudMatch_InstanceID
+	//	*CloudMatch_InstanceType
+	//	*CloudMatch_Region
+	//	*CloudMatch_Zone
+	//	*CloudMatch_Tag
+	Field isCloudMatch_Field `protobuf_oneof:"Field"`
+}
+
+func (x *CloudMatch) Reset() {
+	*x = CloudMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloudMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloudMatch) ProtoMessage() {}
+
+func (x *CloudMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloudMatch.ProtoReflect.Descriptor instead.
+func (*CloudMatch) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{7}
+}
+
+func (m *CloudMatch) GetField() isCloudMatch_Field {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (x *CloudMatch) GetProvider() *StringMatch {
+	if x, ok := x.GetField().(*CloudMatch_Provider); ok {
+		return x.Provider
+	}
+	return nil
+}
+
+func (x *CloudMatch) GetInstanceID() *StringMatch {
+	if x, ok := x.GetField().(*CloudMatch_InstanceID); ok {
+		return x.InstanceID
+	}
+	return nil
+}
+
+func (x *CloudMatch) GetInstanceType() *StringMatch {
+	if x, ok := x.GetField().(*CloudMatch_InstanceType); ok {
+		return x.InstanceType
+	}
+	return nil
+}
+
+func (x *CloudMatch) GetRegion() *StringMatch {
+	if x, ok := x.GetField().(*CloudMatch_Region); ok {
+		return x.Region
+	}
+	return nil
+}
+
+func (x *CloudMatch) GetZone() *StringMatch {
+	if x, ok := x.GetField().(*CloudMatch_Zone); ok {
+		return x.Zone
+	}
+	return nil
+}
+
+func (x *CloudMatch) GetTag() *LabelMatch {
+	if x, ok := x.GetField().(*CloudMatch_Tag); ok {
+		return x.Tag
+	}
+	return nil
+}
+
+type isCloudMatch_Field interface {
+	isCloudMatch_Field()
+}
+
+type CloudMatch_Provider struct {
+	Provider *StringMatch `protobuf:"bytes,1,opt,name=Provider,proto3,oneof"`
+}
+
+type CloudMatch_InstanceID struct {
+	InstanceID *StringMatch `protobuf:"bytes,2,opt,name=InstanceID,proto3,oneof"`
+}
+
+type CloudMatch_InstanceType struct {
+	InstanceType *StringMatch `protobuf:"bytes,3,opt,name=InstanceType,proto3,oneof"`
+}
+
+type CloudMatch_Region struct {
+	Region *StringMatch `protobuf:"bytes,4,opt,name=Region,proto3,oneof"`
+}
+
+type CloudMatch_Zone struct {
+	Zone *StringMatch `protobuf:"bytes,5,opt,name=Zone,proto3,oneof"`
+}
+
+type CloudMatch_Tag struct {
+	Tag *LabelMatch `protobuf:"bytes,6,opt,name=Tag,proto3,oneof"`
+}
+
+func (*CloudMatch_Provider) isCloudMatch_Field() {}
+
+func (*CloudMatch_InstanceID) isCloudMatch_Field() {}
+
+func (*CloudMatch_InstanceType) isCloudMatch_Field() {}
+
+func (*CloudMatch_Region) isCloudMatch_Field() {}
+
+func (*CloudMatch_Zone) isCloudMatch_Field() {}
+
+func (*CloudMatch_Tag) isCloudMatch_Field() {}
+
+type ExpressionList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*Expression `protobuf:"bytes,1,rep,name=Items,proto3" json:"Items,omitempty"`
+}
+
+func (x *ExpressionList) Reset() {
+	*x = ExpressionList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExpressionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpressionList) ProtoMessage() {}
+
+func (x *ExpressionList) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpressionList.ProtoReflect.Descriptor instead.
+func (*ExpressionList) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ExpressionList) GetItems() []*Expression {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type StringMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Match:
+	//	*StringMatch_Exact
+	//	*StringMatch_Glob
+	//	*StringMatch_Regex
+	Match isStringMatch_Match `protobuf_oneof:"Match"`
+}
+
+func (x *StringMatch) Reset() {
+	*x = StringMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StringMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringMatch) ProtoMessage() {}
+
+func (x *StringMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringMatch.ProtoReflect.Descriptor instead.
+func (*StringMatch) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{9}
+}
+
+func (m *StringMatch) GetMatch() isStringMatch_Match {
+	if m != nil {
+		return m.Match
+	}
+	return nil
+}
+
+func (x *StringMatch) GetExact() string {
+	if x, ok := x.GetMatch().(*StringMatch_Exact); ok {
+		return x.Exact
+	}
+	return ""
+}
+
+func (x *StringMatch) GetGlob() string {
+	if x, ok := x.GetMatch().(*StringMatch_Glob); ok {
+		return x.Glob
+	}
+	return ""
+}
+
+func (x *StringMatch) GetRegex() string {
+	if x, ok := x.GetMatch().(*StringMatch_Regex); ok {
+		return x.Regex
+	}
+	return ""
+}
+
+type isStringMatch_Match interface {
+	isStringMatch_Match()
+}
+
+type StringMatch_Exact struct {
+	Exact string `protobuf:"bytes,1,opt,name=Exact,proto3,oneof"`
+}
+
+type StringMatch_Glob struct {
+	Glob string `protobuf:"bytes,2,opt,name=Glob,proto3,oneof"`
+}
+
+type StringMatch_Regex struct {
+	Regex string `protobuf:"bytes,3,opt,name=Regex,proto3,oneof"`
+}
+
+func (*StringMatch_Exact) isStringMatch_Match() {}
+
+func (*StringMatch_Glob) isStringMatch_Match() {}
+
+func (*StringMatch_Regex) isStringMatch_Match() {}
+
+type LabelMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   string       `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
+	Value *StringMatch `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+}
+
+func (x *LabelMatch) Reset() {
+	*x = LabelMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelMatch) ProtoMessage() {}
+
+func (x *LabelMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelMatch.ProtoReflect.Descriptor instead.
+func (*LabelMatch) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LabelMatch) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LabelMatch) GetValue() *StringMatch {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type KexRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ServerEphemeralPublicKey []byte `protobuf:"bytes,1,opt,name=ServerEphemeralPublicKey,proto3" json:"ServerEphemeralPublicKey,omitempty"`
+}
+
+func (x *KexRequest) Reset() {
+	*x = KexRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KexRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KexRequest) ProtoMessage() {}
+
+func (x *KexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KexRequest.ProtoReflect.Descriptor instead.
+func (*KexRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *KexRequest) GetServerEphemeralPublicKey() []byte {
+	if x != nil {
+		return x.ServerEphemeralPublicKey
+	}
+	return nil
+}
+
+type KexResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClientEphemeralPublicKey []byte `protobuf:"bytes,1,opt,name=ClientEphemeralPublicKey,proto3" json:"ClientEphemeralPublicKey,omitempty"`
+}
+
+func (x *KexResponse) Reset() {
+	*x = KexResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KexResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KexResponse) ProtoMessage() {}
+
+func (x *KexResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KexResponse.ProtoReflect.Descriptor instead.
+func (*KexResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *KexResponse) GetClientEphemeralPublicKey() []byte {
+	if x != nil {
+		return x.ClientEphemeralPublicKey
+	}
+	return nil
+}
+
+type SignRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce []byte `protobuf:"bytes,1,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+}
+
+func (x *SignRequest) Reset() {
+	*x = SignRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignRequest) ProtoMessage() {}
+
+func (x *SignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignRequest.ProtoReflect.Descriptor instead.
+func (*SignRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SignRequest) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+type SignResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Signature []byte `protobuf:"bytes,1,opt,name=Signature,proto3" json:"Signature,omitempty"`
+}
+
+func (x *SignResponse) Reset() {
+	*x = SignResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignResponse) ProtoMessage() {}
+
+func (x *SignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignResponse.ProtoReflect.Descriptor instead.
+func (*SignResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SignResponse) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type Job struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID               string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Filter           *BasicFilter           `protobuf:"bytes,2,opt,name=Filter,proto3" json:"Filter,omitempty"`
+	Steps            []*JobStep             `protobuf:"bytes,3,rep,name=Steps,proto3" json:"Steps,omitempty"`
+	Owner            string                 `protobuf:"bytes,4,opt,name=Owner,proto3" json:"Owner,omitempty"`
+	CreationTime     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=CreationTime,proto3" json:"CreationTime,omitempty"`
+	Expression       *Expression            `protobuf:"bytes,6,opt,name=Expression,proto3" json:"Expression,omitempty"`
+	OwnerCertificate []byte                 `protobuf:"bytes,7,opt,name=OwnerCertificate,proto3" json:"OwnerCertificate,omitempty"`
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Job) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Job) GetFilter() *BasicFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *Job) GetSteps() []*JobStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *Job) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Job) GetCreationTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreationTime
+	}
+	return nil
+}
+
+func (x *Job) GetExpression() *Expression {
+	if x != nil {
+		return x.Expression
+	}
+	return nil
+}
+
+func (x *Job) GetOwnerCertificate() []byte {
+	if x != nil {
+		return x.OwnerCertificate
+	}
+	return nil
+}
+
+type JobStep struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Step:
+	//	*JobStep_Command
+	//	*JobStep_Script
+	Step isJobStep_Step `protobuf_oneof:"Step"`
+}
+
+func (x *JobStep) Reset() {
+	*x = JobStep{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStep) ProtoMessage() {}
+
+func (x *JobStep) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStep.ProtoReflect.Descriptor instead.
+func (*JobStep) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{16}
+}
+
+func (m *JobStep) GetStep() isJobStep_Step {
+	if m != nil {
+		return m.Step
+	}
+	return nil
+}
+
+func (x *JobStep) GetCommand() *Command {
+	if x, ok := x.GetStep().(*JobStep_Command); ok {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *JobStep) GetScript() *Script {
+	if x, ok := x.GetStep().(*JobStep_Script); ok {
+		return x.Script
+	}
+	return nil
+}
+
+type isJobStep_Step interface {
+	isJobStep_Step()
+}
+
+type JobStep_Command struct {
+	Command *Command `protobuf:"bytes,1,opt,name=Command,proto3,oneof"`
+}
+
+type JobStep_Script struct {
+	Script *Script `protobuf:"bytes,2,opt,name=Script,proto3,oneof"`
+}
+
+func (*JobStep_Command) isJobStep_Step() {}
+
+func (*JobStep_Script) isJobStep_Step() {}
+
+type JobList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*Job `protobuf:"bytes,1,rep,name=Items,proto3" json:"Items,omitempty"`
+}
+
+func (x *JobList) Reset() {
+	*x = JobList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobList) ProtoMessage() {}
+
+func (x *JobList) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobList.ProtoReflect.Descriptor instead.
+func (*JobList) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *JobList) GetItems() []*Job {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type JobReference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+}
+
+func (x *JobReference) Reset() {
+	*x = JobReference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobReference) ProtoMessage() {}
+
+func (x *JobReference) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobReference.ProtoReflect.Descriptor instead.
+func (*JobReference) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *JobReference) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+type JobResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobID            string                 `protobuf:"bytes,1,opt,name=JobID,proto3" json:"JobID,omitempty"`
+	AgentFingerprint string                 `protobuf:"bytes,2,opt,name=AgentFingerprint,proto3" json:"AgentFingerprint,omitempty"`
+	Announcement     *Announcement          `protobuf:"bytes,3,opt,name=Announcement,proto3" json:"Announcement,omitempty"`
+	StartTime        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=StartTime,proto3" json:"StartTime,omitempty"`
+	EndTime          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=EndTime,proto3" json:"EndTime,omitempty"`
+	Steps            []*JobStepResult       `protobuf:"bytes,6,rep,name=Steps,proto3" json:"Steps,omitempty"`
+}
+
+func (x *JobResult) Reset() {
+	*x = JobResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobResult) ProtoMessage() {}
+
+func (x *JobResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobResult.ProtoReflect.Descriptor instead.
+func (*JobResult) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *JobResult) GetJobID() string {
+	if x != nil {
+		return x.JobID
+	}
+	return ""
+}
+
+func (x *JobResult) GetAgentFingerprint() string {
+	if x != nil {
+		return x.AgentFingerprint
+	}
+	return ""
+}
+
+func (x *JobResult) GetAnnouncement() *Announcement {
+	if x != nil {
+		return x.Announcement
+	}
+	return nil
+}
+
+func (x *JobResult) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *JobResult) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *JobResult) GetSteps() []*JobStepResult {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+type JobStepResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Result:
+	//	*JobStepResult_Command
+	//	*JobStepResult_Script
+	Result isJobStepResult_Result `protobuf_oneof:"Result"`
+	Error  string                 `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`
+}
+
+func (x *JobStepResult) Reset() {
+	*x = JobStepResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobStepResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStepResult) ProtoMessage() {}
+
+func (x *JobStepResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStepResult.ProtoReflect.Descriptor instead.
+func (*JobStepResult) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{20}
+}
+
+func (m *JobStepResult) GetResult() isJobStepResult_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *JobStepResult) GetCommand() *CommandResponse {
+	if x, ok := x.GetResult().(*JobStepResult_Command); ok {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *JobStepResult) GetScript() *ScriptResponse {
+	if x, ok := x.GetResult().(*JobStepResult_Script); ok {
+		return x.Script
+	}
+	return nil
+}
+
+func (x *JobStepResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type isJobStepResult_Result interface {
+	isJobStepResult_Result()
+}
+
+type JobStepResult_Command struct {
+	Command *CommandResponse `protobuf:"bytes,1,opt,name=Command,proto3,oneof"`
+}
+
+type JobStepResult_Script struct {
+	Script *ScriptResponse `protobuf:"bytes,2,opt,name=Script,proto3,oneof"`
+}
+
+func (*JobStepResult_Command) isJobStepResult_Result() {}
+
+func (*JobStepResult_Script) isJobStepResult_Result() {}
+
+type JobResultList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*JobResult `protobuf:"bytes,1,rep,name=Items,proto3" json:"Items,omitempty"`
+}
+
+func (x *JobResultList) Reset() {
+	*x = JobResultList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobResultList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobResultList) ProtoMessage() {}
+
+func (x *JobResultList) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobResultList.ProtoReflect.Descriptor instead.
+func (*JobResultList) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *JobResultList) GetItems() []*JobResult {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type TimeRange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Since *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=Since,proto3" json:"Since,omitempty"`
+	Until *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=Until,proto3" json:"Until,omitempty"`
+}
+
+func (x *TimeRange) Reset() {
+	*x = TimeRange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimeRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeRange) ProtoMessage() {}
+
+func (x *TimeRange) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeRange.ProtoReflect.Descriptor instead.
+func (*TimeRange) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TimeRange) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *TimeRange) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+type AnnouncementRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fingerprint    string                 `protobuf:"bytes,1,opt,name=Fingerprint,proto3" json:"Fingerprint,omitempty"`
+	Announcement   *Announcement          `protobuf:"bytes,2,opt,name=Announcement,proto3" json:"Announcement,omitempty"`
+	ConnectTime    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=ConnectTime,proto3" json:"ConnectTime,omitempty"`
+	DisconnectTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=DisconnectTime,proto3" json:"DisconnectTime,omitempty"`
+}
+
+func (x *AnnouncementRecord) Reset() {
+	*x = AnnouncementRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnnouncementRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnouncementRecord) ProtoMessage() {}
+
+func (x *AnnouncementRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnouncementRecord.ProtoReflect.Descriptor instead.
+func (*AnnouncementRecord) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AnnouncementRecord) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *AnnouncementRecord) GetAnnouncement() *Announcement {
+	if x != nil {
+		return x.Announcement
+	}
+	return nil
+}
+
+func (x *AnnouncementRecord) GetConnectTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ConnectTime
+	}
+	return nil
+}
+
+func (x *AnnouncementRecord) GetDisconnectTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisconnectTime
+	}
+	return nil
+}
+
+type AnnouncementHistory struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*AnnouncementRecord `protobuf:"bytes,1,rep,name=Items,proto3" json:"Items,omitempty"`
+}
+
+func (x *AnnouncementHistory) Reset() {
+	*x = AnnouncementHistory{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnnouncementHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnouncementHistory) ProtoMessage() {}
+
+func (x *AnnouncementHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnouncementHistory.ProtoReflect.Descriptor instead.
+func (*AnnouncementHistory) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AnnouncementHistory) GetItems() []*AnnouncementRecord {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type ListAgentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter     *BasicFilter `protobuf:"bytes,1,opt,name=Filter,proto3" json:"Filter,omitempty"`
+	Expression *Expression  `protobuf:"bytes,2,opt,name=Expression,proto3" json:"Expression,omitempty"`
+}
+
+func (x *ListAgentsRequest) Reset() {
+	*x = ListAgentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsRequest) ProtoMessage() {}
+
+func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListAgentsRequest) GetFilter() *BasicFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListAgentsRequest) GetExpression() *Expression {
+	if x != nil {
+		return x.Expression
+	}
+	return nil
+}
+
+type AgentInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fingerprint  string                 `protobuf:"bytes,1,opt,name=Fingerprint,proto3" json:"Fingerprint,omitempty"`
+	Announcement *Announcement          `protobuf:"bytes,2,opt,name=Announcement,proto3" json:"Announcement,omitempty"`
+	ConnectTime  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=ConnectTime,proto3" json:"ConnectTime,omitempty"`
+	LastActivity *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=LastActivity,proto3" json:"LastActivity,omitempty"`
+}
+
+func (x *AgentInfo) Reset() {
+	*x = AgentInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AgentInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentInfo) ProtoMessage() {}
+
+func (x *AgentInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentInfo.ProtoReflect.Descriptor instead.
+func (*AgentInfo) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AgentInfo) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *AgentInfo) GetAnnouncement() *Announcement {
+	if x != nil {
+		return x.Announcement
+	}
+	return nil
+}
+
+func (x *AgentInfo) GetConnectTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ConnectTime
+	}
+	return nil
+}
+
+func (x *AgentInfo) GetLastActivity() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastActivity
+	}
+	return nil
+}
+
+type AgentList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*AgentInfo `protobuf:"bytes,1,rep,name=Items,proto3" json:"Items,omitempty"`
+}
+
+func (x *AgentList) Reset() {
+	*x = AgentList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AgentList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentList) ProtoMessage() {}
+
+func (x *AgentList) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentList.ProtoReflect.Descriptor instead.
+func (*AgentList) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *AgentList) GetItems() []*AgentInfo {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type BroadcastRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter      *BasicFilter         `protobuf:"bytes,1,opt,name=Filter,proto3" json:"Filter,omitempty"`
+	Expression  *Expression          `protobuf:"bytes,2,opt,name=Expression,proto3" json:"Expression,omitempty"`
+	Instruction *JobStep             `protobuf:"bytes,3,opt,name=Instruction,proto3" json:"Instruction,omitempty"`
+	Parallelism int32                `protobuf:"varint,4,opt,name=Parallelism,proto3" json:"Parallelism,omitempty"`
+	Timeout     *durationpb.Duration `protobuf:"bytes,5,opt,name=Timeout,proto3" json:"Timeout,omitempty"`
+	BroadcastID string               `protobuf:"bytes,6,opt,name=BroadcastID,proto3" json:"BroadcastID,omitempty"`
+	Rollout     *Rollout             `protobuf:"bytes,7,opt,name=Rollout,proto3" json:"Rollout,omitempty"`
+}
+
+func (x *BroadcastRequest) Reset() {
+	*x = BroadcastRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BroadcastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastRequest) ProtoMessage() {}
+
+func (x *BroadcastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastRequest.ProtoReflect.Descriptor instead.
+func (*BroadcastRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *BroadcastRequest) GetFilter() *BasicFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *BroadcastRequest) GetExpression() *Expression {
+	if x != nil {
+		return x.Expression
+	}
+	return nil
+}
+
+func (x *BroadcastRequest) GetInstruction() *JobStep {
+	if x != nil {
+		return x.Instruction
+	}
+	return nil
+}
+
+func (x *BroadcastRequest) GetParallelism() int32 {
+	if x != nil {
+		return x.Parallelism
+	}
+	return 0
+}
+
+func (x *BroadcastRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *BroadcastRequest) GetBroadcastID() string {
+	if x != nil {
+		return x.BroadcastID
+	}
+	return ""
+}
+
+func (x *BroadcastRequest) GetRollout() *Rollout {
+	if x != nil {
+		return x.Rollout
+	}
+	return nil
+}
+
+type Rollout struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchSize    int32                `protobuf:"varint,1,opt,name=BatchSize,proto3" json:"BatchSize,omitempty"`
+	BatchPercent int32                `protobuf:"varint,2,opt,name=BatchPercent,proto3" json:"BatchPercent,omitempty"`
+	Pause        *durationpb.Duration `protobuf:"bytes,3,opt,name=Pause,proto3" json:"Pause,omitempty"`
+	MaxFailures  int32                `protobuf:"varint,4,opt,name=MaxFailures,proto3" json:"MaxFailures,omitempty"`
+}
+
+func (x *Rollout) Reset() {
+	*x = Rollout{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Rollout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rollout) ProtoMessage() {}
+
+func (x *Rollout) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rollout.ProtoReflect.Descriptor instead.
+func (*Rollout) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Rollout) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *Rollout) GetBatchPercent() int32 {
+	if x != nil {
+		return x.BatchPercent
+	}
+	return 0
+}
+
+func (x *Rollout) GetPause() *durationpb.Duration {
+	if x != nil {
+		return x.Pause
+	}
+	return nil
+}
+
+func (x *Rollout) GetMaxFailures() int32 {
+	if x != nil {
+		return x.MaxFailures
+	}
+	return 0
+}
+
+type BroadcastReference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BroadcastID string `protobuf:"bytes,1,opt,name=BroadcastID,proto3" json:"BroadcastID,omitempty"`
+}
+
+func (x *BroadcastReference) Reset() {
+	*x = BroadcastReference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BroadcastReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastReference) ProtoMessage() {}
+
+func (x *BroadcastReference) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastReference.ProtoReflect.Descriptor instead.
+func (*BroadcastReference) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *BroadcastReference) GetBroadcastID() string {
+	if x != nil {
+		return x.BroadcastID
+	}
+	return ""
+}
+
+type BroadcastResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BroadcastID      string                 `protobuf:"bytes,1,opt,name=BroadcastID,proto3" json:"BroadcastID,omitempty"`
+	AgentFingerprint string                 `protobuf:"bytes,2,opt,name=AgentFingerprint,proto3" json:"AgentFingerprint,omitempty"`
+	Announcement     *Announcement          `protobuf:"bytes,3,opt,name=Announcement,proto3" json:"Announcement,omitempty"`
+	StartTime        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=StartTime,proto3" json:"StartTime,omitempty"`
+	EndTime          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=EndTime,proto3" json:"EndTime,omitempty"`
+	Result           *JobStepResult         `protobuf:"bytes,6,opt,name=Result,proto3" json:"Result,omitempty"`
+	Batch            int32                  `protobuf:"varint,7,opt,name=Batch,proto3" json:"Batch,omitempty"`
+}
+
+func (x *BroadcastResult) Reset() {
+	*x = BroadcastResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BroadcastResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastResult) ProtoMessage() {}
+
+func (x *BroadcastResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastResult.ProtoReflect.Descriptor instead.
+func (*BroadcastResult) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *BroadcastResult) GetBroadcastID() string {
+	if x != nil {
+		return x.BroadcastID
+	}
+	return ""
+}
+
+func (x *BroadcastResult) GetAgentFingerprint() string {
+	if x != nil {
+		return x.AgentFingerprint
+	}
+	return ""
+}
+
+func (x *BroadcastResult) GetAnnouncement() *Announcement {
+	if x != nil {
+		return x.Announcement
+	}
+	return nil
+}
+
+func (x *BroadcastResult) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *BroadcastResult) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *BroadcastResult) GetResult() *JobStepResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BroadcastResult) GetBatch() int32 {
+	if x != nil {
+		return x.Batch
+	}
+	return 0
+}
+
+type BroadcastSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Total     int32           `protobuf:"varint,1,opt,name=Total,proto3" json:"Total,omitempty"`
+	Succeeded int32           `protobuf:"varint,2,opt,name=Succeeded,proto3" json:"Succeeded,omitempty"`
+	Failed    int32           `protobuf:"varint,3,opt,name=Failed,proto3" json:"Failed,omitempty"`
+	Skipped   int32           `protobuf:"varint,4,opt,name=Skipped,proto3" json:"Skipped,omitempty"`
+	Aborted   bool            `protobuf:"varint,5,opt,name=Aborted,proto3" json:"Aborted,omitempty"`
+	Batches   []*BatchSummary `protobuf:"bytes,6,rep,name=Batches,proto3" json:"Batches,omitempty"`
+}
+
+func (x *BroadcastSummary) Reset() {
+	*x = BroadcastSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BroadcastSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastSummary) ProtoMessage() {}
+
+func (x *BroadcastSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastSummary.ProtoReflect.Descriptor instead.
+func (*BroadcastSummary) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BroadcastSummary) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *BroadcastSummary) GetSucceeded() int32 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *BroadcastSummary) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *BroadcastSummary) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *BroadcastSummary) GetAborted() bool {
+	if x != nil {
+		return x.Aborted
+	}
+	return false
+}
+
+func (x *BroadcastSummary) GetBatches() []*BatchSummary {
+	if x != nil {
+		return x.Batches
+	}
+	return nil
+}
+
+type BatchSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Size      int32                  `protobuf:"varint,1,opt,name=Size,proto3" json:"Size,omitempty"`
+	Succeeded int32                  `protobuf:"varint,2,opt,name=Succeeded,proto3" json:"Succeeded,omitempty"`
+	Failed    int32                  `protobuf:"varint,3,opt,name=Failed,proto3" json:"Failed,omitempty"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=StartTime,proto3" json:"StartTime,omitempty"`
+	EndTime   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=EndTime,proto3" json:"EndTime,omitempty"`
+}
+
+func (x *BatchSummary) Reset() {
+	*x = BatchSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSummary) ProtoMessage() {}
+
+func (x *BatchSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSummary.ProtoReflect.Descriptor instead.
+func (*BatchSummary) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BatchSummary) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BatchSummary) GetSucceeded() int32 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *BatchSummary) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *BatchSummary) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *BatchSummary) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+type AuditRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time              *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=Time,proto3" json:"Time,omitempty"`
+	ClientFingerprint string                 `protobuf:"bytes,2,opt,name=ClientFingerprint,proto3" json:"ClientFingerprint,omitempty"`
+	AgentFingerprint  string                 `protobuf:"bytes,3,opt,name=AgentFingerprint,proto3" json:"AgentFingerprint,omitempty"`
+	Action            string                 `protobuf:"bytes,4,opt,name=Action,proto3" json:"Action,omitempty"`
+	Detail            string                 `protobuf:"bytes,5,opt,name=Detail,proto3" json:"Detail,omitempty"`
+	Error             string                 `protobuf:"bytes,6,opt,name=Error,proto3" json:"Error,omitempty"`
+}
+
+func (x *AuditRecord) Reset() {
+	*x = AuditRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditRecord) ProtoMessage() {}
+
+func (x *AuditRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditRecord.ProtoReflect.Descriptor instead.
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AuditRecord) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *AuditRecord) GetClientFingerprint() string {
+	if x != nil {
+		return x.ClientFingerprint
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetAgentFingerprint() string {
+	if x != nil {
+		return x.AgentFingerprint
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type AuditLog struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*AuditRecord `protobuf:"bytes,1,rep,name=Items,proto3" json:"Items,omitempty"`
+}
+
+func (x *AuditLog) Reset() {
+	*x = AuditLog{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_client_api_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditLog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditLog) ProtoMessage() {}
+
+func (x *AuditLog) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_client_api_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditLog.ProtoReflect.Descriptor instead.
+func (*AuditLog) Descriptor() ([]byte, []int) {
+	return file_pkg_api_client_api_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *AuditLog) GetItems() []*AuditRecord {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+var File_pkg_api_client_api_proto protoreflect.FileDescriptor
+
+var file_pkg_api_client_api_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x5f, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x61, 0x70, 0x69, 0x1a,
+	0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1a, 0x70,
+	0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x69, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x70, 0x6b, 0x67, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x30, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x0f, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x42,
+	0x00, 0x3a, 0x00, 0x22, 0x16, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x3a, 0x00, 0x22, 0xe3, 0x01, 0x0a, 0x0a,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x23, 0x0a, 0x04, 0x54, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x42, 0x00, 0x12,
+	0x15, 0x0a, 0x0b, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x29, 0x0a, 0x0c, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e,
+	0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x42,
+	0x00, 0x12, 0x2a, 0x0a, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x12, 0x10, 0x0a,
+	0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12,
+	0x1b, 0x0a, 0x11, 0x50, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x6c, 0x79, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x42, 0x00, 0x12, 0x11, 0x0a, 0x07,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x44, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a,
+	0x00, 0x22, 0x6e, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x22, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x61, 0x73, 0x69, 0x63, 0x46, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x42, 0x00, 0x12, 0x25, 0x0a, 0x0a, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x00, 0x12, 0x11, 0x0a, 0x07,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a,
+	0x00, 0x22, 0x25, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x11, 0x0a, 0x07, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x7d, 0x0a, 0x0b, 0x42, 0x61, 0x73, 0x69,
+	0x63, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x42, 0x00, 0x12, 0x1a, 0x0a, 0x10, 0x48, 0x61,
+	0x73, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x16, 0x0a, 0x0c, 0x48, 0x61, 0x73, 0x49, 0x50, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x15,
+	0x0a, 0x0b, 0x48, 0x61, 0x73, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0xcf, 0x03, 0x0a, 0x0a, 0x45, 0x78, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x03, 0x41, 0x6e, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x00, 0x48, 0x00, 0x12, 0x23, 0x0a, 0x02,
+	0x4f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45,
+	0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x00, 0x48,
+	0x00, 0x12, 0x20, 0x0a, 0x03, 0x4e, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42,
+	0x00, 0x48, 0x00, 0x12, 0x26, 0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x72, 0x69,
+	0x6e, 0x67, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x42, 0x00, 0x48, 0x00, 0x12, 0x15, 0x0a, 0x09, 0x49,
+	0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00,
+	0x48, 0x00, 0x12, 0x19, 0x0a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x64,
+	0x4b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x48, 0x00, 0x12, 0x28, 0x0a,
+	0x0a, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x42, 0x00, 0x48, 0x00, 0x12, 0x2b, 0x0a, 0x0d, 0x4b, 0x65, 0x72, 0x6e, 0x65,
+	0x6c, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x74, 0x63, 0x68,
+	0x42, 0x00, 0x48, 0x00, 0x12, 0x25, 0x0a, 0x07, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x72, 0x69,
+	0x6e, 0x67, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x42, 0x00, 0x48, 0x00, 0x12, 0x22, 0x0a, 0x05, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x42, 0x00, 0x48, 0x00, 0x12,
+	0x22, 0x0a, 0x05, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x42,
+	0x00, 0x48, 0x00, 0x12, 0x2a, 0x0a, 0x09, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x6e, 0x69, 0x74,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6c, 0x6f,
+	0x75, 0x64, 0x49, 0x6e, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x42, 0x00, 0x48, 0x00, 0x3a,
+	0x00, 0x42, 0x06, 0x0a, 0x04, 0x45, 0x78, 0x70, 0x72, 0x22, 0x81, 0x02, 0x0a, 0x0a, 0x43, 0x6c,
+	0x6f, 0x75, 0x64, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x26, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x42, 0x00, 0x48, 0x00,
+	0x12, 0x28, 0x0a, 0x0a, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x42, 0x00, 0x48, 0x00, 0x12, 0x2a, 0x0a, 0x0c, 0x49, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x42, 0x00, 0x48, 0x00, 0x12, 0x24, 0x0a, 0x06, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x42, 0x00, 0x48, 0x00, 0x12, 0x22, 0x0a, 0x04,
+	0x5a, 0x6f, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x42, 0x00, 0x48, 0x00,
+	0x12, 0x20, 0x0a, 0x03, 0x54, 0x61, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x42, 0x00,
+	0x48, 0x00, 0x3a, 0x00, 0x42, 0x07, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x22, 0x34, 0x0a,
+	0x0e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x20, 0x0a, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42,
+	0x00, 0x3a, 0x00, 0x22, 0x50, 0x0a, 0x0b, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x12, 0x11, 0x0a, 0x05, 0x45, 0x78, 0x61, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x00, 0x48, 0x00, 0x12, 0x10, 0x0a, 0x04, 0x47, 0x6c, 0x6f, 0x62, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x00, 0x48, 0x00, 0x12, 0x11, 0x0a, 0x05, 0x52, 0x65, 0x67, 0x65, 0x78,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x48, 0x00, 0x3a, 0x00, 0x42, 0x07, 0x0a, 0x05,
+	0x4d, 0x61, 0x74, 0x63, 0x68, 0x22, 0x40, 0x0a, 0x0a, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x12, 0x0d, 0x0a, 0x03, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x00, 0x12, 0x21, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x32, 0x0a, 0x0a, 0x4b, 0x65, 0x78, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x18, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45,
+	0x70, 0x68, 0x65, 0x6d, 0x65, 0x72, 0x61, 0x6c, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x33, 0x0a, 0x0b, 0x4b,
+	0x65, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x18, 0x43, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x45, 0x70, 0x68, 0x65, 0x6d, 0x65, 0x72, 0x61, 0x6c, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x3a, 0x00,
+	0x22, 0x20, 0x0a, 0x0b, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0f, 0x0a, 0x05, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00,
+	0x3a, 0x00, 0x22, 0x25, 0x0a, 0x0c, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x13, 0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x3a, 0x00, 0x22, 0xe0, 0x01, 0x0a, 0x03, 0x4a, 0x6f,
+	0x62, 0x12, 0x0c, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12,
+	0x22, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x61, 0x73, 0x69, 0x63, 0x46, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x42, 0x00, 0x12, 0x1d, 0x0a, 0x05, 0x53, 0x74, 0x65, 0x70, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x65, 0x70,
+	0x42, 0x00, 0x12, 0x0f, 0x0a, 0x05, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x00, 0x12, 0x32, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54,
+	0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x12, 0x25, 0x0a, 0x0a, 0x45, 0x78, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x00, 0x12, 0x1a,
+	0x0a, 0x10, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x57, 0x0a, 0x07,
+	0x4a, 0x6f, 0x62, 0x53, 0x74, 0x65, 0x70, 0x12, 0x21, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x42, 0x00, 0x48, 0x00, 0x12, 0x1f, 0x0a, 0x06, 0x53, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x42, 0x00, 0x48, 0x00, 0x3a, 0x00, 0x42, 0x06, 0x0a,
+	0x04, 0x53, 0x74, 0x65, 0x70, 0x22, 0x26, 0x0a, 0x07, 0x4a, 0x6f, 0x62, 0x4c, 0x69, 0x73, 0x74,
+	0x12, 0x19, 0x0a, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x08, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x1e, 0x0a,
+	0x0c, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x0c, 0x0a,
+	0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0xea, 0x01,
+	0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x0f, 0x0a, 0x05, 0x4a,
+	0x6f, 0x62, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x1a, 0x0a, 0x10,
+	0x41, 0x67, 0x65, 0x6e, 0x74, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x29, 0x0a, 0x0c, 0x41, 0x6e, 0x6e, 0x6f,
+	0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x42, 0x00, 0x12, 0x2f, 0x0a, 0x09, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x42, 0x00, 0x12, 0x2d, 0x0a, 0x07, 0x45, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x42, 0x00, 0x12, 0x23, 0x0a, 0x05, 0x53, 0x74, 0x65, 0x70, 0x73, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x65, 0x70,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x80, 0x01, 0x0a, 0x0d, 0x4a,
+	0x6f, 0x62, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x29, 0x0a, 0x07,
+	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x00, 0x48, 0x00, 0x12, 0x27, 0x0a, 0x06, 0x53, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x00, 0x48, 0x00,
+	0x12, 0x0f, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x00, 0x3a, 0x00, 0x42, 0x08, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x32, 0x0a,
+	0x0d, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1f,
+	0x0a, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x42, 0x00, 0x3a,
+	0x00, 0x22, 0x67, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x2b,
+	0x0a, 0x05, 0x53, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x12, 0x2b, 0x0a, 0x05, 0x55,
+	0x6e, 0x74, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x3a, 0x00, 0x22, 0xc1, 0x01, 0x0a, 0x12, 0x41,
+	0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x12, 0x15, 0x0a, 0x0b, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x29, 0x0a, 0x0c, 0x41, 0x6e, 0x6e, 0x6f,
+	0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x42, 0x00, 0x12, 0x31, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x12, 0x34, 0x0a, 0x0e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x41,
+	0x0a, 0x13, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x28, 0x0a, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x75,
+	0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x42, 0x00, 0x3a,
+	0x00, 0x22, 0x60, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x61, 0x73,
+	0x69, 0x63, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x42, 0x00, 0x12, 0x25, 0x0a, 0x0a, 0x45, 0x78,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42,
+	0x00, 0x3a, 0x00, 0x22, 0xb6, 0x01, 0x0a, 0x09, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x15, 0x0a, 0x0b, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x29, 0x0a, 0x0c, 0x41, 0x6e, 0x6e, 0x6f,
+	0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x42, 0x00, 0x12, 0x31, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x12, 0x32, 0x0a, 0x0c, 0x4c, 0x61, 0x73, 0x74, 0x41, 0x63,
+	0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x2e, 0x0a, 0x09,
+	0x41, 0x67, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x05, 0x49, 0x74, 0x65,
+	0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41,
+	0x67, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x81, 0x02, 0x0a,
+	0x10, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x22, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x61, 0x73, 0x69, 0x63, 0x46, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x42, 0x00, 0x12, 0x25, 0x0a, 0x0a, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x00, 0x12, 0x23, 0x0a, 0x0b,
+	0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x65, 0x70, 0x42,
+	0x00, 0x12, 0x15, 0x0a, 0x0b, 0x50, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65, 0x6c, 0x69, 0x73, 0x6d,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x42, 0x00, 0x12, 0x2c, 0x0a, 0x07, 0x54, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x42, 0x00, 0x12, 0x15, 0x0a, 0x0b, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63,
+	0x61, 0x73, 0x74, 0x49, 0x44, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x1f, 0x0a,
+	0x07, 0x52, 0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x42, 0x00, 0x3a, 0x00,
+	0x22, 0x7b, 0x0a, 0x07, 0x52, 0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x12, 0x13, 0x0a, 0x09, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x42, 0x00,
+	0x12, 0x16, 0x0a, 0x0c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x42, 0x00, 0x12, 0x2a, 0x0a, 0x05, 0x50, 0x61, 0x75, 0x73,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x42, 0x00, 0x12, 0x15, 0x0a, 0x0b, 0x4d, 0x61, 0x78, 0x46, 0x61, 0x69, 0x6c, 0x75,
+	0x72, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x2d, 0x0a,
+	0x12, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x15, 0x0a, 0x0b, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74,
+	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x88, 0x02, 0x0a,
+	0x0f, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x15, 0x0a, 0x0b, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x1a, 0x0a, 0x10, 0x41, 0x67, 0x65, 0x6e, 0x74,
+	0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x00, 0x12, 0x29, 0x0a, 0x0c, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x42, 0x00, 0x12, 0x2f,
+	0x0a, 0x09, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x12,
+	0x2d, 0x0a, 0x07, 0x45, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x12, 0x24,
+	0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x42, 0x00, 0x12, 0x0f, 0x0a, 0x05, 0x42, 0x61, 0x74, 0x63, 0x68, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x05, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x98, 0x01, 0x0a, 0x10, 0x42, 0x72, 0x6f, 0x61,
+	0x64, 0x63, 0x61, 0x73, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x0f, 0x0a, 0x05,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x42, 0x00, 0x12, 0x13, 0x0a,
+	0x09, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x42, 0x00, 0x12, 0x11, 0x0a, 0x07, 0x53, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x42, 0x00, 0x12, 0x11, 0x0a, 0x07, 0x41, 0x62, 0x6f, 0x72, 0x74,
+	0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x42, 0x00, 0x12, 0x24, 0x0a, 0x07, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x42, 0x00,
+	0x3a, 0x00, 0x22, 0xa7, 0x01, 0x0a, 0x0c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x12, 0x0e, 0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x42, 0x00, 0x12, 0x13, 0x0a, 0x09, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x46, 0x61, 0x69, 0x6c,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x42, 0x00, 0x12, 0x2f, 0x0a, 0x09, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x12, 0x2d, 0x0a, 0x07, 0x45,
+	0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x3a, 0x00, 0x22, 0xa9, 0x01, 0x0a,
+	0x0b, 0x41, 0x75, 0x64, 0x69, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x2a, 0x0a, 0x04,
+	0x54, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x12, 0x1b, 0x0a, 0x11, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x1a, 0x0a, 0x10, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x46, 0x69,
+	0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x00, 0x12, 0x10, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0f, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x2f, 0x0a, 0x08, 0x41, 0x75, 0x64, 0x69,
+	0x74, 0x4c, 0x6f, 0x67, 0x12, 0x21, 0x0a, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x42, 0x00, 0x3a, 0x00, 0x2a, 0x1d, 0x0a, 0x08, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x6e, 0x64, 0x10, 0x00, 0x12, 0x06,
+	0x0a, 0x02, 0x4f, 0x72, 0x10, 0x01, 0x1a, 0x00, 0x2a, 0x63, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x44, 0x69, 0x73,
+	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x52,
+	0x65, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x64, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x10, 0x03, 0x12,
+	0x0a, 0x0a, 0x06, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x10, 0x04, 0x1a, 0x00, 0x32, 0xa6, 0x0a,
+	0x0a, 0x09, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x12, 0x40, 0x0a, 0x07, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x38, 0x0a,
+	0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3c, 0x0a, 0x07, 0x55, 0x6e, 0x77, 0x61, 0x74,
+	0x63, 0x68, 0x12, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3d, 0x0a, 0x0a, 0x52, 0x75, 0x6e, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x12, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x28, 0x00, 0x30, 0x00, 0x12, 0x3a, 0x0a, 0x09, 0x52, 0x75, 0x6e, 0x53, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00,
+	0x12, 0x45, 0x0a, 0x10, 0x52, 0x75, 0x6e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x12, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x43, 0x0a, 0x0f, 0x52, 0x75, 0x6e, 0x53, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3a, 0x0a, 0x06,
+	0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3b, 0x0a, 0x08, 0x52, 0x75, 0x6e, 0x53,
+	0x68, 0x65, 0x6c, 0x6c, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x42, 0x0a, 0x0a, 0x53, 0x68, 0x65, 0x6c, 0x6c, 0x49, 0x6e,
+	0x70, 0x75, 0x74, 0x12, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x6c, 0x49,
+	0x6e, 0x70, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3c, 0x0a, 0x07, 0x50, 0x75, 0x74,
+	0x46, 0x69, 0x6c, 0x65, 0x12, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x75, 0x74, 0x46, 0x69,
+	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3a, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x46, 0x69,
+	0x6c, 0x65, 0x12, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65,
+	0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28,
+	0x00, 0x30, 0x00, 0x12, 0x25, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4a, 0x6f, 0x62,
+	0x12, 0x08, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x1a, 0x08, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x4a, 0x6f, 0x62, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x36, 0x0a, 0x08, 0x4c, 0x69,
+	0x73, 0x74, 0x4a, 0x6f, 0x62, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0c,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x28, 0x00,
+	0x30, 0x00, 0x12, 0x3c, 0x0a, 0x09, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x12,
+	0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00,
+	0x12, 0x3c, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x63, 0x65, 0x1a, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x49,
+	0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x1a, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x32, 0x0a, 0x0b, 0x47, 0x65, 0x74,
+	0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f, 0x67, 0x12, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41,
+	0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f, 0x67, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3a, 0x0a,
+	0x0a, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x16, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x4c,
+	0x69, 0x73, 0x74, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x3f, 0x0a, 0x09, 0x42, 0x72, 0x6f,
+	0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x12, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x72, 0x6f,
+	0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x53, 0x75, 0x6d,
+	0x6d, 0x61, 0x72, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x48, 0x0a, 0x0f, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x12, 0x17, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00,
+	0x28, 0x00, 0x30, 0x00, 0x1a, 0x00, 0x32, 0x7b, 0x0a, 0x0b, 0x4b, 0x65, 0x79, 0x45, 0x78, 0x63,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x37, 0x0a, 0x0c, 0x45, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4b, 0x65, 0x78, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4b, 0x65, 0x78,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00, 0x12, 0x31,
+	0x0a, 0x04, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x69, 0x67,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53,
+	0x69, 0x67, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x00, 0x30,
+	0x00, 0x1a, 0x00, 0x32, 0x42, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x37, 0x0a, 0x06,
+	0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x61, 0x74,
+	0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x00, 0x28, 0x00, 0x30, 0x00, 0x1a, 0x00, 0x32, 0x49, 0x0a, 0x0c, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x37, 0x0a, 0x05, 0x57, 0x72, 0x69, 0x74, 0x65,
+	0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x00, 0x30, 0x00,
+	0x1a, 0x00, 0x32, 0x51, 0x0a, 0x0f, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x3c, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12,
+	0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28,
+	0x00, 0x30, 0x00, 0x1a, 0x00, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x72, 0x61, 0x6c, 0x69, 0x63, 0x6b, 0x79, 0x2f, 0x70, 0x6f, 0x73,
+	0x74, 0x2d, 0x69, 0x6e, 0x69, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_api_client_api_proto_rawDescOnce sync.Once
+	file_pkg_api_client_api_proto_rawDescData = file_pkg_api_client_api_proto_rawDesc
+)
+
+func file_pkg_api_client_api_proto_rawDescGZIP() []byte {
+	file_pkg_api_client_api_proto_rawDescOnce.Do(func() {
+		file_pkg_api_client_api_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_api_client_api_proto_rawDescData)
+	})
+	return file_pkg_api_client_api_proto_rawDescData
+}
+
+var file_pkg_api_client_api_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_pkg_api_client_api_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
+var file_pkg_api_client_api_proto_goTypes = []interface{}{
+	(Operator)(0),                 // 0: api.Operator
+	(WatchEventType)(0),           // 1: api.WatchEventType
+	(*ConnectionRequest)(nil),     // 2: api.ConnectionRequest
+	(*ConnectionResponse)(nil),    // 3: api.ConnectionResponse
+	(*WatchEvent)(nil),            // 4: api.WatchEvent
+	(*WatchRequest)(nil),          // 5: api.WatchRequest
+	(*WatchReference)(nil),        // 6: api.WatchReference
+	(*BasicFilter)(nil),           // 7: api.BasicFilter
+	(*Expression)(nil),            // 8: api.Expression
+	(*CloudMatch)(nil),            // 9: api.CloudMatch
+	(*ExpressionList)(nil),        // 10: api.ExpressionList
+	(*StringMatch)(nil),           // 11: api.StringMatch
+	(*LabelMatch)(nil),            // 12: api.LabelMatch
+	(*KexRequest)(nil),            // 13: api.KexRequest
+	(*KexResponse)(nil),           // 14: api.KexResponse
+	(*SignRequest)(nil),           // 15: api.SignRequest
+	(*SignResponse)(nil),          // 16: api.SignResponse
+	(*Job)(nil),                   // 17: api.Job
+	(*JobStep)(nil),               // 18: api.JobStep
+	(*JobList)(nil),               // 19: api.JobList
+	(*JobReference)(nil),          // 20: api.JobReference
+	(*JobResult)(nil),             // 21: api.JobResult
+	(*JobStepResult)(nil),         // 22: api.JobStepResult
+	(*JobResultList)(nil),         // 23: api.JobResultList
+	(*TimeRange)(nil),             // 24: api.TimeRange
+	(*AnnouncementRecord)(nil),    // 25: api.AnnouncementRecord
+	(*AnnouncementHistory)(nil),   // 26: api.AnnouncementHistory
+	(*ListAgentsRequest)(nil),     // 27: api.ListAgentsRequest
+	(*AgentInfo)(nil),             // 28: api.AgentInfo
+	(*AgentList)(nil),             // 29: api.AgentList
+	(*BroadcastRequest)(nil),      // 30: api.BroadcastRequest
+	(*Rollout)(nil),               // 31: api.Rollout
+	(*BroadcastReference)(nil),    // 32: api.BroadcastReference
+	(*BroadcastResult)(nil),       // 33: api.BroadcastResult
+	(*BroadcastSummary)(nil),      // 34: api.BroadcastSummary
+	(*BatchSummary)(nil),          // 35: api.BatchSummary
+	(*AuditRecord)(nil),           // 36: api.AuditRecord
+	(*AuditLog)(nil),              // 37: api.AuditLog
+	(*Announcement)(nil),          // 38: api.Announcement
+	(*timestamppb.Timestamp)(nil), // 39: google.protobuf.Timestamp
+	(CloudInitState)(0),           // 40: api.CloudInitState
+	(*Command)(nil),               // 41: api.Command
+	(*Script)(nil),                // 42: api.Script
+	(*CommandResponse)(nil),       // 43: api.CommandResponse
+	(*ScriptResponse)(nil),        // 44: api.ScriptResponse
+	(*durationpb.Duration)(nil),   // 45: google.protobuf.Duration
+	(*CommandRequest)(nil),        // 46: api.CommandRequest
+	(*ScriptRequest)(nil),         // 47: api.ScriptRequest
+	(*CancelRequest)(nil),         // 48: api.CancelRequest
+	(*ShellRequest)(nil),          // 49: api.ShellRequest
+	(*ShellInputRequest)(nil),     // 50: api.ShellInputRequest
+	(*PutFileRequest)(nil),        // 51: api.PutFileRequest
+	(*GetFileRequest)(nil),        // 52: api.GetFileRequest
+	(*emptypb.Empty)(nil),         // 53: google.protobuf.Empty
+	(*OutputEvent)(nil),           // 54: api.OutputEvent
+	(*GetFileResponse)(nil),       // 55: api.GetFileResponse
+}
+var file_pkg_api_client_api_proto_depIdxs = []int32{
+	1,  // 0: api.WatchEvent.Type:type_name -> api.WatchEventType
+	38, // 1: api.WatchEvent.Announcement:type_name -> api.Announcement
+	39, // 2: api.WatchEvent.Time:type_name -> google.protobuf.Timestamp
+	7,  // 3: api.WatchRequest.Filter:type_name -> api.BasicFilter
+	8,  // 4: api.WatchRequest.Expression:type_name -> api.Expression
+	0,  // 5: api.BasicFilter.Operator:type_name -> api.Operator
+	10, // 6: api.Expression.And:type_name -> api.ExpressionList
+	10, // 7: api.Expression.Or:type_name -> api.ExpressionList
+	8,  // 8: api.Expression.Not:type_name -> api.Expression
+	11, // 9: api.Expression.Hostname:type_name -> api.StringMatch
+	11, // 10: api.Expression.KernelName:type_name -> api.StringMatch
+	11, // 11: api.Expression.KernelRelease:type_name -> api.StringMatch
+	11, // 12: api.Expression.Machine:type_name -> api.StringMatch
+	12, // 13: api.Expression.Label:type_name -> api.LabelMatch
+	9,  // 14: api.Expression.Cloud:type_name -> api.CloudMatch
+	40, // 15: api.Expression.CloudInit:type_name -> api.CloudInitState
+	11, // 16: api.CloudMatch.Provider:type_name -> api.StringMatch
+	11, // 17: api.CloudMatch.InstanceID:type_name -> api.StringMatch
+	11, // 18: api.CloudMatch.InstanceType:type_name -> api.StringMatch
+	11, // 19: api.CloudMatch.Region:type_name -> api.StringMatch
+	11, // 20: api.CloudMatch.Zone:type_name -> api.StringMatch
+	12, // 21: api.CloudMatch.Tag:type_name -> api.LabelMatch
+	8,  // 22: api.ExpressionList.Items:type_name -> api.Expression
+	11, // 23: api.LabelMatch.Value:type_name -> api.StringMatch
+	7,  // 24: api.Job.Filter:type_name -> api.BasicFilter
+	18, // 25: api.Job.Steps:type_name -> api.JobStep
+	39, // 26: api.Job.CreationTime:type_name -> google.protobuf.Timestamp
+	8,  // 27: api.Job.Expression:type_name -> api.Expression
+	41, // 28: api.JobStep.Command:type_name -> api.Command
+	42, // 29: api.JobStep.Script:type_name -> api.Script
+	17, // 30: api.JobList.Items:type_name -> api.Job
+	38, // 31: api.JobResult.Announcement:type_name -> api.Announcement
+	39, // 32: api.JobResult.StartTime:type_name -> google.protobuf.Timestamp
+	39, // 33: api.JobResult.EndTime:type_name -> google.protobuf.Timestamp
+	22, // 34: api.JobResult.Steps:type_name -> api.JobStepResult
+	43, // 35: api.JobStepResult.Command:type_name -> api.CommandResponse
+	44, // 36: api.JobStepResult.Script:type_name -> api.ScriptResponse
+	21, // 37: api.JobResultList.Items:type_name -> api.JobResult
+	39, // 38: api.TimeRange.Since:type_name -> google.protobuf.Timestamp
+	39, // 39: api.TimeRange.Until:type_name -> google.protobuf.Timestamp
+	38, // 40: api.AnnouncementRecord.Announcement:type_name -> api.Announcement
+	39, // 41: api.AnnouncementRecord.ConnectTime:type_name -> google.protobuf.Timestamp
+	39, // 42: api.AnnouncementRecord.DisconnectTime:type_name -> google.protobuf.Timestamp
+	25, // 43: api.AnnouncementHistory.Items:type_name -> api.AnnouncementRecord
+	7,  // 44: api.ListAgentsRequest.Filter:type_name -> api.BasicFilter
+	8,  // 45: api.ListAgentsRequest.Expression:type_name -> api.Expression
+	38, // 46: api.AgentInfo.Announcement:type_name -> api.Announcement
+	39, // 47: api.AgentInfo.ConnectTime:type_name -> google.protobuf.Timestamp
+	39, // 48: api.AgentInfo.LastActivity:type_name -> google.protobuf.Timestamp
+	28, // 49: api.AgentList.Items:type_name -> api.AgentInfo
+	7,  // 50: api.BroadcastRequest.Filter:type_name -> api.BasicFilter
+	8,  // 51: api.BroadcastRequest.Expression:type_name -> api.Expression
+	18, // 52: api.BroadcastRequest.Instruction:type_name -> api.JobStep
+	45, // 53: api.BroadcastRequest.Timeout:type_name -> google.protobuf.Duration
+	31, // 54: api.BroadcastRequest.Rollout:type_name -> api.Rollout
+	45, // 55: api.Rollout.Pause:type_name -> google.protobuf.Duration
+	38, // 56: api.BroadcastResult.Announcement:type_name -> api.Announcement
+	39, // 57: api.BroadcastResult.StartTime:type_name -> google.protobuf.Timestamp
+	39, // 58: api.BroadcastResult.EndTime:type_name -> google.protobuf.Timestamp
+	22, // 59: api.BroadcastResult.Result:type_name -> api.JobStepResult
+	35, // 60: api.BroadcastSummary.Batches:type_name -> api.BatchSummary
+	39, // 61: api.BatchSummary.StartTime:type_name -> google.protobuf.Timestamp
+	39, // 62: api.BatchSummary.EndTime:type_name -> google.protobuf.Timestamp
+	39, // 63: api.AuditRecord.Time:type_name -> google.protobuf.Timestamp
+	36, // 64: api.AuditLog.Items:type_name -> api.AuditRecord
+	2,  // 65: api.ClientAPI.Connect:input_type -> api.ConnectionRequest
+	5,  // 66: api.ClientAPI.Watch:input_type -> api.WatchRequest
+	6,  // 67: api.ClientAPI.Unwatch:input_type -> api.WatchReference
+	46, // 68: api.ClientAPI.RunCommand:input_type -> api.CommandRequest
+	47, // 69: api.ClientAPI.RunScript:input_type -> api.ScriptRequest
+	46, // 70: api.ClientAPI.RunCommandStream:input_type -> api.CommandRequest
+	47, // 71: api.ClientAPI.RunScriptStream:input_type -> api.ScriptRequest
+	48, // 72: api.ClientAPI.Cancel:input_type -> api.CancelRequest
+	49, // 73: api.ClientAPI.RunShell:input_type -> api.ShellRequest
+	50, // 74: api.ClientAPI.ShellInput:input_type -> api.ShellInputRequest
+	51, // 75: api.ClientAPI.PutFile:input_type -> api.PutFileRequest
+	52, // 76: api.ClientAPI.GetFile:input_type -> api.GetFileRequest
+	17, // 77: api.ClientAPI.CreateJob:input_type -> api.Job
+	53, // 78: api.ClientAPI.ListJobs:input_type -> google.protobuf.Empty
+	20, // 79: api.ClientAPI.DeleteJob:input_type -> api.JobReference
+	20, // 80: api.ClientAPI.GetJobResults:input_type -> api.JobReference
+	24, // 81: api.ClientAPI.ListAnnouncementHistory:input_type -> api.TimeRange
+	24, // 82: api.ClientAPI.GetAuditLog:input_type -> api.TimeRange
+	27, // 83: api.ClientAPI.ListAgents:input_type -> api.ListAgentsRequest
+	30, // 84: api.ClientAPI.Broadcast:input_type -> api.BroadcastRequest
+	32, // 85: api.ClientAPI.CancelBroadcast:input_type -> api.BroadcastReference
+	13, // 86: api.KeyExchange.ExchangeKeys:input_type -> api.KexRequest
+	15, // 87: api.KeyExchange.Sign:input_type -> api.SignRequest
+	4,  // 88: api.Watch.Notify:input_type -> api.WatchEvent
+	54, // 89: api.OutputStream.Write:input_type -> api.OutputEvent
+	33, // 90: api.BroadcastStream.Result:input_type -> api.BroadcastResult
+	3,  // 91: api.ClientAPI.Connect:output_type -> api.ConnectionResponse
+	53, // 92: api.ClientAPI.Watch:output_type -> google.protobuf.Empty
+	53, // 93: api.ClientAPI.Unwatch:output_type -> google.protobuf.Empty
+	43, // 94: api.ClientAPI.RunCommand:output_type -> api.CommandResponse
+	44, // 95: api.ClientAPI.RunScript:output_type -> api.ScriptResponse
+	53, // 96: api.ClientAPI.RunCommandStream:output_type -> google.protobuf.Empty
+	53, // 97: api.ClientAPI.RunScriptStream:output_type -> google.protobuf.Empty
+	53, // 98: api.ClientAPI.Cancel:output_type -> google.protobuf.Empty
+	53, // 99: api.ClientAPI.RunShell:output_type -> google.protobuf.Empty
+	53, // 100: api.ClientAPI.ShellInput:output_type -> google.protobuf.Empty
+	53, // 101: api.ClientAPI.PutFile:output_type -> google.protobuf.Empty
+	55, // 102: api.ClientAPI.GetFile:output_type -> api.GetFileResponse
+	17, // 103: api.ClientAPI.CreateJob:output_type -> api.Job
+	19, // 104: api.ClientAPI.ListJobs:output_type -> api.JobList
+	53, // 105: api.ClientAPI.DeleteJob:output_type -> google.protobuf.Empty
+	23, // 106: api.ClientAPI.GetJobResults:output_type -> api.JobResultList
+	26, // 107: api.ClientAPI.ListAnnouncementHistory:output_type -> api.AnnouncementHistory
+	37, // 108: api.ClientAPI.GetAuditLog:output_type -> api.AuditLog
+	29, // 109: api.ClientAPI.ListAgents:output_type -> api.AgentList
+	34, // 110: api.ClientAPI.Broadcast:output_type -> api.BroadcastSummary
+	53, // 111: api.ClientAPI.CancelBroadcast:output_type -> google.protobuf.Empty
+	14, // 112: api.KeyExchange.ExchangeKeys:output_type -> api.KexResponse
+	16, // 113: api.KeyExchange.Sign:output_type -> api.SignResponse
+	53, // 114: api.Watch.Notify:output_type -> google.protobuf.Empty
+	53, // 115: api.OutputStream.Write:output_type -> google.protobuf.Empty
+	53, // 116: api.BroadcastStream.Result:output_type -> google.protobuf.Empty
+	91, // [91:117] is the sub-list for method output_type
+	65, // [65:91] is the sub-list for method input_type
+	65, // [65:65] is the sub-list for extension type_name
+	65, // [65:65] is the sub-list for extension extendee
+	0,  // [0:65] is the sub-list for field type_name
+}
+
+func init() { file_pkg_api_client_api_proto_init() }
+func file_pkg_api_client_api_proto_init() {
+	if File_pkg_api_client_api_proto != nil {
+		return
+	}
+	file_pkg_api_instructions_proto_init()
+	file_pkg_api_announce_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_api_client_api_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ConnectionRequest); i {
 			case 0:
 				return &v.state
@@ -606,8 +3297,68 @@ func file_pkg_api_client_api_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_api_client_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConnectionResponse); i {
+		file_pkg_api_client_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConnectionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchReference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BasicFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Expression); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -618,8 +3369,8 @@ func file_pkg_api_client_api_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_api_client_api_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WatchRequest); i {
+		file_pkg_api_client_api_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloudMatch); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -630,8 +3381,8 @@ func file_pkg_api_client_api_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_api_client_api_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BasicFilter); i {
+		file_pkg_api_client_api_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExpressionList); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -642,7 +3393,31 @@ func file_pkg_api_client_api_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_api_client_api_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_api_client_api_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StringMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LabelMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*KexRequest); i {
 			case 0:
 				return &v.state
@@ -654,7 +3429,7 @@ func file_pkg_api_client_api_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_api_client_api_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_api_client_api_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*KexResponse); i {
 			case 0:
 				return &v.state
@@ -666,7 +3441,7 @@ func file_pkg_api_client_api_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_api_client_api_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_api_client_api_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SignRequest); i {
 			case 0:
 				return &v.state
@@ -678,7 +3453,7 @@ func file_pkg_api_client_api_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_api_client_api_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_api_client_api_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SignResponse); i {
 			case 0:
 				return &v.state
@@ -690,16 +3465,303 @@ func file_pkg_api_client_api_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_api_client_api_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Job); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobStep); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobReference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobStepResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobResultList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TimeRange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnnouncementRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnnouncementHistory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListAgentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AgentInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AgentList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BroadcastRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Rollout); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BroadcastReference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BroadcastResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BroadcastSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_client_api_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditLog); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pkg_api_client_api_proto_msgTypes[6].OneofWrappers = []interface{}{
+		(*Expression_And)(nil),
+		(*Expression_Or)(nil),
+		(*Expression_Not)(nil),
+		(*Expression_Hostname)(nil),
+		(*Expression_IPAddress)(nil),
+		(*Expression_AuthorizedKey)(nil),
+		(*Expression_KernelName)(nil),
+		(*Expression_KernelRelease)(nil),
+		(*Expression_Machine)(nil),
+		(*Expression_Label)(nil),
+		(*Expression_Cloud)(nil),
+		(*Expression_CloudInit)(nil),
+	}
+	file_pkg_api_client_api_proto_msgTypes[7].OneofWrappers = []interface{}{
+		(*CloudMatch_Provider)(nil),
+		(*CloudMatch_InstanceID)(nil),
+		(*CloudMatch_InstanceType)(nil),
+		(*CloudMatch_Region)(nil),
+		(*CloudMatch_Zone)(nil),
+		(*CloudMatch_Tag)(nil),
+	}
+	file_pkg_api_client_api_proto_msgTypes[9].OneofWrappers = []interface{}{
+		(*StringMatch_Exact)(nil),
+		(*StringMatch_Glob)(nil),
+		(*StringMatch_Regex)(nil),
+	}
+	file_pkg_api_client_api_proto_msgTypes[16].OneofWrappers = []interface{}{
+		(*JobStep_Command)(nil),
+		(*JobStep_Script)(nil),
+	}
+	file_pkg_api_client_api_proto_msgTypes[20].OneofWrappers = []interface{}{
+		(*JobStepResult_Command)(nil),
+		(*JobStepResult_Script)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_api_client_api_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   8,
+			NumEnums:      2,
+			NumMessages:   36,
 			NumExtensions: 0,
-			NumServices:   3,
+			NumServices:   5,
 		},
 		GoTypes:           file_pkg_api_client_api_proto_goTypes,
 		DependencyIndexes: file_pkg_api_client_api_proto_depIdxs,
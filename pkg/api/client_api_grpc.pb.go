@@ -25,8 +25,25 @@ const _ = grpc.SupportPackageIsVersion7
 type ClientAPIClient interface {
 	Connect(ctx context.Context, in *ConnectionRequest, opts ...grpc.CallOption) (*ConnectionResponse, error)
 	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Unwatch(ctx context.Context, in *WatchReference, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	RunCommand(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error)
 	RunScript(ctx context.Context, in *ScriptRequest, opts ...grpc.CallOption) (*ScriptResponse, error)
+	RunCommandStream(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RunScriptStream(ctx context.Context, in *ScriptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RunShell(ctx context.Context, in *ShellRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ShellInput(ctx context.Context, in *ShellInputRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	PutFile(ctx context.Context, in *PutFileRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*GetFileResponse, error)
+	CreateJob(ctx context.Context, in *Job, opts ...grpc.CallOption) (*Job, error)
+	ListJobs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*JobList, error)
+	DeleteJob(ctx context.Context, in *JobReference, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetJobResults(ctx context.Context, in *JobReference, opts ...grpc.CallOption) (*JobResultList, error)
+	ListAnnouncementHistory(ctx context.Context, in *TimeRange, opts ...grpc.CallOption) (*AnnouncementHistory, error)
+	GetAuditLog(ctx context.Context, in *TimeRange, opts ...grpc.CallOption) (*AuditLog, error)
+	ListAgents(ctx context.Context, in *ListAgentsRequest, opts ...grpc.CallOption) (*AgentList, error)
+	Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*BroadcastSummary, error)
+	CancelBroadcast(ctx context.Context, in *BroadcastReference, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type clientAPIClient struct {
@@ -55,6 +72,15 @@ func (c *clientAPIClient) Watch(ctx context.Context, in *WatchRequest, opts ...g
 	return out, nil
 }
 
+func (c *clientAPIClient) Unwatch(ctx context.Context, in *WatchReference, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/Unwatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *clientAPIClient) RunCommand(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
 	out := new(CommandResponse)
 	err := c.cc.Invoke(ctx, "/api.ClientAPI/RunCommand", in, out, opts...)
@@ -73,14 +99,175 @@ func (c *clientAPIClient) RunScript(ctx context.Context, in *ScriptRequest, opts
 	return out, nil
 }
 
+func (c *clientAPIClient) RunCommandStream(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/RunCommandStream", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) RunScriptStream(ctx context.Context, in *ScriptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/RunScriptStream", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) RunShell(ctx context.Context, in *ShellRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/RunShell", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) ShellInput(ctx context.Context, in *ShellInputRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/ShellInput", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) PutFile(ctx context.Context, in *PutFileRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/PutFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*GetFileResponse, error) {
+	out := new(GetFileResponse)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/GetFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) CreateJob(ctx context.Context, in *Job, opts ...grpc.CallOption) (*Job, error) {
+	out := new(Job)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/CreateJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) ListJobs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*JobList, error) {
+	out := new(JobList)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/ListJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) DeleteJob(ctx context.Context, in *JobReference, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/DeleteJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) GetJobResults(ctx context.Context, in *JobReference, opts ...grpc.CallOption) (*JobResultList, error) {
+	out := new(JobResultList)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/GetJobResults", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) ListAnnouncementHistory(ctx context.Context, in *TimeRange, opts ...grpc.CallOption) (*AnnouncementHistory, error) {
+	out := new(AnnouncementHistory)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/ListAnnouncementHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) GetAuditLog(ctx context.Context, in *TimeRange, opts ...grpc.CallOption) (*AuditLog, error) {
+	out := new(AuditLog)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/GetAuditLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) ListAgents(ctx context.Context, in *ListAgentsRequest, opts ...grpc.CallOption) (*AgentList, error) {
+	out := new(AgentList)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/ListAgents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*BroadcastSummary, error) {
+	out := new(BroadcastSummary)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/Broadcast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) CancelBroadcast(ctx context.Context, in *BroadcastReference, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.ClientAPI/CancelBroadcast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ClientAPIServer is the server API for ClientAPI service.
 // All implementations must embed UnimplementedClientAPIServer
 // for forward compatibility
 type ClientAPIServer interface {
 	Connect(context.Context, *ConnectionRequest) (*ConnectionResponse, error)
 	Watch(context.Context, *WatchRequest) (*emptypb.Empty, error)
+	Unwatch(context.Context, *WatchReference) (*emptypb.Empty, error)
 	RunCommand(context.Context, *CommandRequest) (*CommandResponse, error)
 	RunScript(context.Context, *ScriptRequest) (*ScriptResponse, error)
+	RunCommandStream(context.Context, *CommandRequest) (*emptypb.Empty, error)
+	RunScriptStream(context.Context, *ScriptRequest) (*emptypb.Empty, error)
+	Cancel(context.Context, *CancelRequest) (*emptypb.Empty, error)
+	RunShell(context.Context, *ShellRequest) (*emptypb.Empty, error)
+	ShellInput(context.Context, *ShellInputRequest) (*emptypb.Empty, error)
+	PutFile(context.Context, *PutFileRequest) (*emptypb.Empty, error)
+	GetFile(context.Context, *GetFileRequest) (*GetFileResponse, error)
+	CreateJob(context.Context, *Job) (*Job, error)
+	ListJobs(context.Context, *emptypb.Empty) (*JobList, error)
+	DeleteJob(context.Context, *JobReference) (*emptypb.Empty, error)
+	GetJobResults(context.Context, *JobReference) (*JobResultList, error)
+	ListAnnouncementHistory(context.Context, *TimeRange) (*AnnouncementHistory, error)
+	GetAuditLog(context.Context, *TimeRange) (*AuditLog, error)
+	ListAgents(context.Context, *ListAgentsRequest) (*AgentList, error)
+	Broadcast(context.Context, *BroadcastRequest) (*BroadcastSummary, error)
+	CancelBroadcast(context.Context, *BroadcastReference) (*emptypb.Empty, error)
 	mustEmbedUnimplementedClientAPIServer()
 }
 
@@ -94,12 +281,63 @@ func (UnimplementedClientAPIServer) Connect(context.Context, *ConnectionRequest)
 func (UnimplementedClientAPIServer) Watch(context.Context, *WatchRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
+func (UnimplementedClientAPIServer) Unwatch(context.Context, *WatchReference) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unwatch not implemented")
+}
 func (UnimplementedClientAPIServer) RunCommand(context.Context, *CommandRequest) (*CommandResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RunCommand not implemented")
 }
 func (UnimplementedClientAPIServer) RunScript(context.Context, *ScriptRequest) (*ScriptResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RunScript not implemented")
 }
+func (UnimplementedClientAPIServer) RunCommandStream(context.Context, *CommandRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunCommandStream not implemented")
+}
+func (UnimplementedClientAPIServer) RunScriptStream(context.Context, *ScriptRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunScriptStream not implemented")
+}
+func (UnimplementedClientAPIServer) Cancel(context.Context, *CancelRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+func (UnimplementedClientAPIServer) RunShell(context.Context, *ShellRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunShell not implemented")
+}
+func (UnimplementedClientAPIServer) ShellInput(context.Context, *ShellInputRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShellInput not implemented")
+}
+func (UnimplementedClientAPIServer) PutFile(context.Context, *PutFileRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutFile not implemented")
+}
+func (UnimplementedClientAPIServer) GetFile(context.Context, *GetFileRequest) (*GetFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFile not implemented")
+}
+func (UnimplementedClientAPIServer) CreateJob(context.Context, *Job) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateJob not implemented")
+}
+func (UnimplementedClientAPIServer) ListJobs(context.Context, *emptypb.Empty) (*JobList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedClientAPIServer) DeleteJob(context.Context, *JobReference) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteJob not implemented")
+}
+func (UnimplementedClientAPIServer) GetJobResults(context.Context, *JobReference) (*JobResultList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobResults not implemented")
+}
+func (UnimplementedClientAPIServer) ListAnnouncementHistory(context.Context, *TimeRange) (*AnnouncementHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAnnouncementHistory not implemented")
+}
+func (UnimplementedClientAPIServer) GetAuditLog(context.Context, *TimeRange) (*AuditLog, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuditLog not implemented")
+}
+func (UnimplementedClientAPIServer) ListAgents(context.Context, *ListAgentsRequest) (*AgentList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAgents not implemented")
+}
+func (UnimplementedClientAPIServer) Broadcast(context.Context, *BroadcastRequest) (*BroadcastSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Broadcast not implemented")
+}
+func (UnimplementedClientAPIServer) CancelBroadcast(context.Context, *BroadcastReference) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelBroadcast not implemented")
+}
 func (UnimplementedClientAPIServer) mustEmbedUnimplementedClientAPIServer() {}
 
 // UnsafeClientAPIServer may be embedded to opt out of forward compatibility for this service.
@@ -149,6 +387,24 @@ func _ClientAPI_Watch_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClientAPI_Unwatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WatchReference)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).Unwatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/Unwatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).Unwatch(ctx, req.(*WatchReference))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ClientAPI_RunCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CommandRequest)
 	if err := dec(in); err != nil {
@@ -185,6 +441,294 @@ func _ClientAPI_RunScript_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClientAPI_RunCommandStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).RunCommandStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/RunCommandStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).RunCommandStream(ctx, req.(*CommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_RunScriptStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).RunScriptStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/RunScriptStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).RunScriptStream(ctx, req.(*ScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).Cancel(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_RunShell_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShellRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).RunShell(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/RunShell",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).RunShell(ctx, req.(*ShellRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_ShellInput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShellInputRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).ShellInput(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/ShellInput",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).ShellInput(ctx, req.(*ShellInputRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_PutFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).PutFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/PutFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).PutFile(ctx, req.(*PutFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_GetFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).GetFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/GetFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).GetFile(ctx, req.(*GetFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_CreateJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Job)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).CreateJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/CreateJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).CreateJob(ctx, req.(*Job))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/ListJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).ListJobs(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_DeleteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobReference)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).DeleteJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/DeleteJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).DeleteJob(ctx, req.(*JobReference))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_GetJobResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobReference)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).GetJobResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/GetJobResults",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).GetJobResults(ctx, req.(*JobReference))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_ListAnnouncementHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeRange)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).ListAnnouncementHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/ListAnnouncementHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).ListAnnouncementHistory(ctx, req.(*TimeRange))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_GetAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeRange)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).GetAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/GetAuditLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).GetAuditLog(ctx, req.(*TimeRange))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_ListAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).ListAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/ListAgents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).ListAgents(ctx, req.(*ListAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).Broadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/Broadcast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).Broadcast(ctx, req.(*BroadcastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_CancelBroadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastReference)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).CancelBroadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.ClientAPI/CancelBroadcast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).CancelBroadcast(ctx, req.(*BroadcastReference))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ClientAPI_ServiceDesc is the grpc.ServiceDesc for ClientAPI service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -200,6 +744,10 @@ var ClientAPI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Watch",
 			Handler:    _ClientAPI_Watch_Handler,
 		},
+		{
+			MethodName: "Unwatch",
+			Handler:    _ClientAPI_Unwatch_Handler,
+		},
 		{
 			MethodName: "RunCommand",
 			Handler:    _ClientAPI_RunCommand_Handler,
@@ -208,6 +756,70 @@ var ClientAPI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RunScript",
 			Handler:    _ClientAPI_RunScript_Handler,
 		},
+		{
+			MethodName: "RunCommandStream",
+			Handler:    _ClientAPI_RunCommandStream_Handler,
+		},
+		{
+			MethodName: "RunScriptStream",
+			Handler:    _ClientAPI_RunScriptStream_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _ClientAPI_Cancel_Handler,
+		},
+		{
+			MethodName: "RunShell",
+			Handler:    _ClientAPI_RunShell_Handler,
+		},
+		{
+			MethodName: "ShellInput",
+			Handler:    _ClientAPI_ShellInput_Handler,
+		},
+		{
+			MethodName: "PutFile",
+			Handler:    _ClientAPI_PutFile_Handler,
+		},
+		{
+			MethodName: "GetFile",
+			Handler:    _ClientAPI_GetFile_Handler,
+		},
+		{
+			MethodName: "CreateJob",
+			Handler:    _ClientAPI_CreateJob_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _ClientAPI_ListJobs_Handler,
+		},
+		{
+			MethodName: "DeleteJob",
+			Handler:    _ClientAPI_DeleteJob_Handler,
+		},
+		{
+			MethodName: "GetJobResults",
+			Handler:    _ClientAPI_GetJobResults_Handler,
+		},
+		{
+			MethodName: "ListAnnouncementHistory",
+			Handler:    _ClientAPI_ListAnnouncementHistory_Handler,
+		},
+		{
+			MethodName: "GetAuditLog",
+			Handler:    _ClientAPI_GetAuditLog_Handler,
+		},
+		{
+			MethodName: "ListAgents",
+			Handler:    _ClientAPI_ListAgents_Handler,
+		},
+		{
+			MethodName: "Broadcast",
+			Handler:    _ClientAPI_Broadcast_Handler,
+		},
+		{
+			MethodName: "CancelBroadcast",
+			Handler:    _ClientAPI_CancelBroadcast_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/api/client_api.proto",
@@ -339,7 +951,7 @@ var KeyExchange_ServiceDesc = grpc.ServiceDesc{
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type WatchClient interface {
-	Notify(ctx context.Context, in *Announcement, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Notify(ctx context.Context, in *WatchEvent, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type watchClient struct {
@@ -350,7 +962,7 @@ func NewWatchClient(cc grpc.ClientConnInterface) WatchClient {
 	return &watchClient{cc}
 }
 
-func (c *watchClient) Notify(ctx context.Context, in *Announcement, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+func (c *watchClient) Notify(ctx context.Context, in *WatchEvent, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/api.Watch/Notify", in, out, opts...)
 	if err != nil {
@@ -363,7 +975,7 @@ func (c *watchClient) Notify(ctx context.Context, in *Announcement, opts ...grpc
 // All implementations must embed UnimplementedWatchServer
 // for forward compatibility
 type WatchServer interface {
-	Notify(context.Context, *Announcement) (*emptypb.Empty, error)
+	Notify(context.Context, *WatchEvent) (*emptypb.Empty, error)
 	mustEmbedUnimplementedWatchServer()
 }
 
@@ -371,7 +983,7 @@ type WatchServer interface {
 type UnimplementedWatchServer struct {
 }
 
-func (UnimplementedWatchServer) Notify(context.Context, *Announcement) (*emptypb.Empty, error) {
+func (UnimplementedWatchServer) Notify(context.Context, *WatchEvent) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Notify not implemented")
 }
 func (UnimplementedWatchServer) mustEmbedUnimplementedWatchServer() {}
@@ -388,7 +1000,7 @@ func RegisterWatchServer(s grpc.ServiceRegistrar, srv WatchServer) {
 }
 
 func _Watch_Notify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Announcement)
+	in := new(WatchEvent)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -400,7 +1012,7 @@ func _Watch_Notify_Handler(srv interface{}, ctx context.Context, dec func(interf
 		FullMethod: "/api.Watch/Notify",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WatchServer).Notify(ctx, req.(*Announcement))
+		return srv.(WatchServer).Notify(ctx, req.(*WatchEvent))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -420,3 +1032,175 @@ var Watch_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/api/client_api.proto",
 }
+
+// OutputStreamClient is the client API for OutputStream service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OutputStreamClient interface {
+	Write(ctx context.Context, in *OutputEvent, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type outputStreamClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOutputStreamClient(cc grpc.ClientConnInterface) OutputStreamClient {
+	return &outputStreamClient{cc}
+}
+
+func (c *outputStreamClient) Write(ctx context.Context, in *OutputEvent, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.OutputStream/Write", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OutputStreamServer is the server API for OutputStream service.
+// All implementations must embed UnimplementedOutputStreamServer
+// for forward compatibility
+type OutputStreamServer interface {
+	Write(context.Context, *OutputEvent) (*emptypb.Empty, error)
+	mustEmbedUnimplementedOutputStreamServer()
+}
+
+// UnimplementedOutputStreamServer must be embedded to have forward compatible implementations.
+type UnimplementedOutputStreamServer struct {
+}
+
+func (UnimplementedOutputStreamServer) Write(context.Context, *OutputEvent) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Write not implemented")
+}
+func (UnimplementedOutputStreamServer) mustEmbedUnimplementedOutputStreamServer() {}
+
+// UnsafeOutputStreamServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OutputStreamServer will
+// result in compilation errors.
+type UnsafeOutputStreamServer interface {
+	mustEmbedUnimplementedOutputStreamServer()
+}
+
+func RegisterOutputStreamServer(s grpc.ServiceRegistrar, srv OutputStreamServer) {
+	s.RegisterService(&OutputStream_ServiceDesc, srv)
+}
+
+func _OutputStream_Write_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OutputEvent)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputStreamServer).Write(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.OutputStream/Write",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputStreamServer).Write(ctx, req.(*OutputEvent))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OutputStream_ServiceDesc is the grpc.ServiceDesc for OutputStream service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OutputStream_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.OutputStream",
+	HandlerType: (*OutputStreamServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Write",
+			Handler:    _OutputStream_Write_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/api/client_api.proto",
+}
+
+// BroadcastStreamClient is the client API for BroadcastStream service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BroadcastStreamClient interface {
+	Result(ctx context.Context, in *BroadcastResult, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type broadcastStreamClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBroadcastStreamClient(cc grpc.ClientConnInterface) BroadcastStreamClient {
+	return &broadcastStreamClient{cc}
+}
+
+func (c *broadcastStreamClient) Result(ctx context.Context, in *BroadcastResult, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.BroadcastStream/Result", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BroadcastStreamServer is the server API for BroadcastStream service.
+// All implementations must embed UnimplementedBroadcastStreamServer
+// for forward compatibility
+type BroadcastStreamServer interface {
+	Result(context.Context, *BroadcastResult) (*emptypb.Empty, error)
+	mustEmbedUnimplementedBroadcastStreamServer()
+}
+
+// UnimplementedBroadcastStreamServer must be embedded to have forward compatible implementations.
+type UnimplementedBroadcastStreamServer struct {
+}
+
+func (UnimplementedBroadcastStreamServer) Result(context.Context, *BroadcastResult) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Result not implemented")
+}
+func (UnimplementedBroadcastStreamServer) mustEmbedUnimplementedBroadcastStreamServer() {}
+
+// UnsafeBroadcastStreamServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BroadcastStreamServer will
+// result in compilation errors.
+type UnsafeBroadcastStreamServer interface {
+	mustEmbedUnimplementedBroadcastStreamServer()
+}
+
+func RegisterBroadcastStreamServer(s grpc.ServiceRegistrar, srv BroadcastStreamServer) {
+	s.RegisterService(&BroadcastStream_ServiceDesc, srv)
+}
+
+func _BroadcastStream_Result_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastResult)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BroadcastStreamServer).Result(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BroadcastStream/Result",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BroadcastStreamServer).Result(ctx, req.(*BroadcastResult))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BroadcastStream_ServiceDesc is the grpc.ServiceDesc for BroadcastStream service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BroadcastStream_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.BroadcastStream",
+	HandlerType: (*BroadcastStreamServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Result",
+			Handler:    _BroadcastStream_Result_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/api/client_api.proto",
+}
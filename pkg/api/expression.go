@@ -0,0 +1,313 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sync"
+)
+
+// A Selector decides whether an announcement should be delivered to a watch.
+// It is implemented by *BasicFilter and *Expression.
+type Selector interface {
+	Accepts(an *Announcement) bool
+}
+
+var _ Selector = (*BasicFilter)(nil)
+var _ Selector = (*Expression)(nil)
+
+func (f *BasicFilter) Accepts(an *Announcement) bool {
+	return an.FilterAccepts(f)
+}
+
+// Selector returns the filter or expression set in the request.
+func (r *WatchRequest) Selector() Selector {
+	if r.Expression != nil {
+		return r.Expression
+	}
+	return r.Filter
+}
+
+func (r *WatchRequest) Validate() error {
+	switch {
+	case r.Filter != nil && r.Expression != nil:
+		return errors.New("only one of filter or expression can be set")
+	case r.Expression != nil:
+		return r.Expression.Validate()
+	case r.Filter != nil:
+		return nil
+	default:
+		return errors.New("missing filter or expression")
+	}
+}
+
+// Selector returns the filter or expression set in the request, or nil if
+// neither is set.
+func (r *ListAgentsRequest) Selector() Selector {
+	switch {
+	case r.Expression != nil:
+		return r.Expression
+	case r.Filter != nil:
+		return r.Filter
+	}
+	return nil
+}
+
+func (r *ListAgentsRequest) Validate() error {
+	switch {
+	case r.Filter != nil && r.Expression != nil:
+		return errors.New("only one of filter or expression can be set")
+	case r.Expression != nil:
+		return r.Expression.Validate()
+	}
+	return nil
+}
+
+// Selector returns the filter or expression set in the job.
+func (j *Job) Selector() Selector {
+	if j.Expression != nil {
+		return j.Expression
+	}
+	return j.Filter
+}
+
+// Selector returns the filter or expression set in the request.
+func (r *BroadcastRequest) Selector() Selector {
+	if r.Expression != nil {
+		return r.Expression
+	}
+	return r.Filter
+}
+
+// Accepts evaluates the expression against the announcement.
+func (e *Expression) Accepts(an *Announcement) bool {
+	switch expr := e.GetExpr().(type) {
+	case *Expression_And:
+		for _, item := range expr.And.GetItems() {
+			if !item.Accepts(an) {
+				return false
+			}
+		}
+		return true
+	case *Expression_Or:
+		for _, item := range expr.Or.GetItems() {
+			if item.Accepts(an) {
+				return true
+			}
+		}
+		return false
+	case *Expression_Not:
+		return !expr.Not.Accepts(an)
+	case *Expression_Hostname:
+		return expr.Hostname.Matches(an.GetUname().GetHostname())
+	case *Expression_IPAddress:
+		return matchIPAddress(an.GetNetwork().GetNetworkInterfaces(), expr.IPAddress)
+	case *Expression_AuthorizedKey:
+		return matchAuthorizedKey(an.GetAuthorizedKeys(), expr.AuthorizedKey)
+	case *Expression_KernelName:
+		return expr.KernelName.Matches(an.GetUname().GetKernelName())
+	case *Expression_KernelRelease:
+		return expr.KernelRelease.Matches(an.GetUname().GetKernelRelease())
+	case *Expression_Machine:
+		return expr.Machine.Matches(an.GetUname().GetMachine())
+	case *Expression_Label:
+		return expr.Label.Matches(an.GetLabels())
+	case *Expression_Cloud:
+		return expr.Cloud.Matches(an.GetCloud())
+	case *Expression_CloudInit:
+		// Agents which did not report a status are treated as Unknown
+		return an.GetCloudInit().GetState() == expr.CloudInit
+	}
+	return false
+}
+
+// Validate checks that every node in the expression is set and that all
+// patterns compile.
+func (e *Expression) Validate() error {
+	switch expr := e.GetExpr().(type) {
+	case *Expression_And:
+		return validateList(expr.And)
+	case *Expression_Or:
+		return validateList(expr.Or)
+	case *Expression_Not:
+		if expr.Not == nil {
+			return errors.New("not: missing expression")
+		}
+		return expr.Not.Validate()
+	case *Expression_Hostname:
+		return expr.Hostname.Validate()
+	case *Expression_IPAddress:
+		_, err := parseIPMatcher(expr.IPAddress)
+		return err
+	case *Expression_AuthorizedKey:
+		if expr.AuthorizedKey == "" {
+			return errors.New("empty authorized key fingerprint")
+		}
+		return nil
+	case *Expression_KernelName:
+		return expr.KernelName.Validate()
+	case *Expression_KernelRelease:
+		return expr.KernelRelease.Validate()
+	case *Expression_Machine:
+		return expr.Machine.Validate()
+	case *Expression_Label:
+		return expr.Label.Validate()
+	case *Expression_Cloud:
+		return expr.Cloud.Validate()
+	case *Expression_CloudInit:
+		if _, ok := CloudInitState_name[int32(expr.CloudInit)]; !ok {
+			return fmt.Errorf("unknown cloud-init state %d", expr.CloudInit)
+		}
+		return nil
+	}
+	return errors.New("empty expression")
+}
+
+func validateList(list *ExpressionList) error {
+	for _, item := range list.GetItems() {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the map contains the key, and its value matches.
+func (m *LabelMatch) Matches(labels map[string]string) bool {
+	value, ok := labels[m.GetKey()]
+	if !ok {
+		return false
+	}
+	if m.Value == nil {
+		return true
+	}
+	return m.Value.Matches(value)
+}
+
+func (m *LabelMatch) Validate() error {
+	if m.GetKey() == "" {
+		return errors.New("empty label key")
+	}
+	if m.Value != nil {
+		return m.Value.Validate()
+	}
+	return nil
+}
+
+func (m *CloudMatch) Matches(md *CloudMetadata) bool {
+	if md == nil {
+		return false
+	}
+	switch field := m.GetField().(type) {
+	case *CloudMatch_Provider:
+		return field.Provider.Matches(md.Provider)
+	case *CloudMatch_InstanceID:
+		return field.InstanceID.Matches(md.InstanceID)
+	case *CloudMatch_InstanceType:
+		return field.InstanceType.Matches(md.InstanceType)
+	case *CloudMatch_Region:
+		return field.Region.Matches(md.Region)
+	case *CloudMatch_Zone:
+		return field.Zone.Matches(md.Zone)
+	case *CloudMatch_Tag:
+		return field.Tag.Matches(md.Tags)
+	}
+	return false
+}
+
+func (m *CloudMatch) Validate() error {
+	switch field := m.GetField().(type) {
+	case *CloudMatch_Provider:
+		return field.Provider.Validate()
+	case *CloudMatch_InstanceID:
+		return field.InstanceID.Validate()
+	case *CloudMatch_InstanceType:
+		return field.InstanceType.Validate()
+	case *CloudMatch_Region:
+		return field.Region.Validate()
+	case *CloudMatch_Zone:
+		return field.Zone.Validate()
+	case *CloudMatch_Tag:
+		return field.Tag.Validate()
+	}
+	return errors.New("empty cloud match")
+}
+
+// Matches returns true if the string matches the exact value, glob, or
+// regular expression.
+func (m *StringMatch) Matches(s string) bool {
+	switch match := m.GetMatch().(type) {
+	case *StringMatch_Exact:
+		return s == match.Exact
+	case *StringMatch_Glob:
+		ok, _ := path.Match(match.Glob, s)
+		return ok
+	case *StringMatch_Regex:
+		re, err := regexps.compile(match.Regex)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(s)
+	}
+	return false
+}
+
+func (m *StringMatch) Validate() error {
+	switch match := m.GetMatch().(type) {
+	case *StringMatch_Exact:
+		return nil
+	case *StringMatch_Glob:
+		if _, err := path.Match(match.Glob, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", match.Glob, err)
+		}
+		return nil
+	case *StringMatch_Regex:
+		if _, err := regexps.compile(match.Regex); err != nil {
+			return fmt.Errorf("invalid regex %q: %w", match.Regex, err)
+		}
+		return nil
+	}
+	return errors.New("empty string match")
+}
+
+func anchored(re string) string {
+	return "^(?:" + re + ")$"
+}
+
+// Expressions are validated when they are received and then evaluated against
+// every announcement, so regular expressions compiled during validation are
+// kept for matching instead of being compiled again each time.
+var regexps = &regexpCache{
+	limit:   1024,
+	entries: map[string]*regexp.Regexp{},
+}
+
+type regexpCache struct {
+	mu      sync.Mutex
+	limit   int
+	entries map[string]*regexp.Regexp
+}
+
+// compile returns the anchored, compiled form of the pattern. Patterns which
+// fail to compile are not cached. When the cache is full, an arbitrary entry
+// is evicted to make room.
+func (c *regexpCache) compile(pattern string) (*regexp.Regexp, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if re, ok := c.entries[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(anchored(pattern))
+	if err != nil {
+		return nil, err
+	}
+	if len(c.entries) >= c.limit {
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[pattern] = re
+	return re, nil
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Expressions", func() {
+	an := &Announcement{
+		Uname: &UnameInfo{
+			Hostname:   "web-1",
+			KernelName: "Linux",
+			Machine:    "x86_64",
+		},
+		Labels: map[string]string{
+			"role": "web",
+			"env":  "staging",
+		},
+		Cloud: &CloudMetadata{
+			Provider: "aws",
+			Region:   "us-east-1",
+			Tags:     map[string]string{"team": "infra"},
+		},
+		CloudInit: &CloudInitStatus{
+			State: CloudInitState_Error,
+		},
+	}
+	label := func(key string, value *StringMatch) *Expression {
+		return &Expression{Expr: &Expression_Label{Label: &LabelMatch{Key: key, Value: value}}}
+	}
+	hostname := func(m *StringMatch) *Expression {
+		return &Expression{Expr: &Expression_Hostname{Hostname: m}}
+	}
+	exact := func(s string) *StringMatch { return &StringMatch{Match: &StringMatch_Exact{Exact: s}} }
+	glob := func(s string) *StringMatch { return &StringMatch{Match: &StringMatch_Glob{Glob: s}} }
+	regex := func(s string) *StringMatch { return &StringMatch{Match: &StringMatch_Regex{Regex: s}} }
+
+	DescribeTable("Accepts",
+		func(expr *Expression, expected bool) {
+			Expect(expr.Validate()).To(Succeed())
+			Expect(expr.Accepts(an)).To(Equal(expected))
+		},
+		Entry("hostname glob", hostname(glob("web-*")), true),
+		Entry("hostname regex", hostname(regex(`web-\d+`)), true),
+		Entry("regex must match the whole string", hostname(regex(`web`)), false),
+		Entry("label value", label("role", exact("web")), true),
+		Entry("wrong label value", label("role", exact("db")), false),
+		Entry("label exists", label("env", nil), true),
+		Entry("missing label", label("zone", nil), false),
+		Entry("not", &Expression{Expr: &Expression_Not{Not: label("zone", nil)}}, true),
+		Entry("and", &Expression{Expr: &Expression_And{And: &ExpressionList{
+			Items: []*Expression{label("role", exact("web")), hostname(glob("db-*"))},
+		}}}, false),
+		Entry("or", &Expression{Expr: &Expression_Or{Or: &ExpressionList{
+			Items: []*Expression{label("role", exact("db")), hostname(glob("web-*"))},
+		}}}, true),
+		Entry("machine", &Expression{Expr: &Expression_Machine{Machine: exact("x86_64")}}, true),
+		Entry("cloud region", &Expression{Expr: &Expression_Cloud{Cloud: &CloudMatch{
+			Field: &CloudMatch_Region{Region: glob("us-*")},
+		}}}, true),
+		Entry("cloud tag", &Expression{Expr: &Expression_Cloud{Cloud: &CloudMatch{
+			Field: &CloudMatch_Tag{Tag: &LabelMatch{Key: "team", Value: exact("web")}},
+		}}}, false),
+		Entry("cloud-init failed", &Expression{Expr: &Expression_CloudInit{CloudInit: CloudInitState_Error}}, true),
+		Entry("cloud-init done", &Expression{Expr: &Expression_CloudInit{CloudInit: CloudInitState_Done}}, false),
+	)
+
+	It("should reject invalid expressions", func() {
+		Expect((&Expression{}).Validate()).NotTo(Succeed())
+		Expect(hostname(regex("(")).Validate()).NotTo(Succeed())
+		Expect(hostname(glob("[")).Validate()).NotTo(Succeed())
+		Expect(label("", nil).Validate()).NotTo(Succeed())
+		Expect((&Expression{Expr: &Expression_CloudInit{CloudInit: 100}}).Validate()).NotTo(Succeed())
+	})
+
+	It("should reuse regular expressions compiled during validation", func() {
+		expr := hostname(regex(`web-\d+-cached`))
+		Expect(expr.Validate()).To(Succeed())
+		re, err := regexps.compile(`web-\d+-cached`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expr.Accepts(an)).To(BeFalse())
+		again, err := regexps.compile(`web-\d+-cached`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(BeIdenticalTo(re))
+	})
+
+	It("should limit the number of cached regular expressions", func() {
+		cache := &regexpCache{limit: 2, entries: map[string]*regexp.Regexp{}}
+		for _, p := range []string{"a", "b", "c", "d"} {
+			_, err := cache.compile(p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(cache.entries)).To(BeNumerically("<=", 2))
+		}
+		Expect(cache.entries).To(HaveKey("d"))
+		_, err := cache.compile("(")
+		Expect(err).To(HaveOccurred())
+		Expect(cache.entries).NotTo(HaveKey("("))
+	})
+})
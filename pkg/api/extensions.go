@@ -1,6 +1,9 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"strings"
 
 	"golang.org/x/crypto/ssh"
@@ -29,23 +32,93 @@ func matchAuthorizedKey(keys []*AuthorizedKey, match string) bool {
 	return false
 }
 
+// matchIPAddress returns true if any address matches the given list of IP
+// addresses and CIDR ranges (see parseIPMatcher).
 func matchIPAddress(ifaces []*NetworkInterface, match string) bool {
+	m, err := parseIPMatcher(match)
+	if err != nil {
+		return false
+	}
 	for _, iface := range ifaces {
 		for _, addr := range iface.Addresses {
-			if strings.Contains(match, "/") {
-				if addr.Cidr == match {
-					return true
-				}
-			} else {
-				if addr.Address == match {
-					return true
-				}
+			if ip := net.ParseIP(addr.Address); ip != nil && m.matches(ip) {
+				return true
 			}
 		}
 	}
 	return false
 }
 
+// ipMatcher matches addresses against sets of included and excluded networks.
+type ipMatcher struct {
+	include []*net.IPNet
+	exclude []*net.IPNet
+}
+
+// parseIPMatcher parses a comma-separated list of IP addresses and CIDR
+// ranges, such as "10.0.0.0/8, fd00::/8, !10.1.0.0/16". An address matches if
+// it is contained in any of the ranges and none of the ranges prefixed with
+// "!". If only excluded ranges are given, all other addresses match.
+func parseIPMatcher(match string) (*ipMatcher, error) {
+	m := &ipMatcher{}
+	for _, entry := range strings.Split(match, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		exclude := strings.HasPrefix(entry, "!")
+		ipNet, err := parseIPNet(strings.TrimPrefix(entry, "!"))
+		if err != nil {
+			return nil, err
+		}
+		if exclude {
+			m.exclude = append(m.exclude, ipNet)
+		} else {
+			m.include = append(m.include, ipNet)
+		}
+	}
+	if len(m.include) == 0 && len(m.exclude) == 0 {
+		return nil, errors.New("no IP addresses or ranges")
+	}
+	return m, nil
+}
+
+// parseIPNet parses a CIDR range, or a single IP address as a range
+// containing only that address.
+func parseIPNet(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, ipNet, err := net.ParseCIDR(s)
+		return ipNet, err
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", s)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+func (m *ipMatcher) matches(ip net.IP) bool {
+	included := len(m.include) == 0
+	for _, ipNet := range m.include {
+		if ipNet.Contains(ip) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, ipNet := range m.exclude {
+		if ipNet.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
 func matchHostname(hostname, match string) bool {
 	return hostname == match
 }
@@ -58,3 +131,89 @@ func (a *Announcement) Fingerprint() (string, error) {
 	fingerprint := ssh.FingerprintSHA256(pubKey)
 	return fingerprint, nil
 }
+
+// RunAsUser decides which user an instruction sent by the given client runs
+// as, given the user and group it requested. An empty result means the
+// agent's own user. Keys authorized for root can run as any user and group.
+// Other keys can only run as the users they are authorized for, and cannot
+// choose the group. If no user is requested, the instruction runs as the
+// agent's user if the key is authorized for it, and otherwise as the first
+// user the key is authorized for.
+func (a *Announcement) RunAsUser(client *ClientKey, user, group string) (string, error) {
+	user, _, err := a.AuthorizeKey(client, user, group)
+	return user, err
+}
+
+// AuthorizeKey is like RunAsUser, but also returns the authorized key entry
+// which grants access, and whose options therefore apply to the instruction.
+// This is the entry for root if there is one, and otherwise the entry for the
+// user the instruction runs as.
+func (a *Announcement) AuthorizeKey(client *ClientKey, user, group string) (string, *AuthorizedKey, error) {
+	first := a.authorizedKey(client, "")
+	if first == nil {
+		return "", nil, errors.New("client key is not authorized on this host")
+	}
+	if key := a.authorizedKey(client, "root"); key != nil {
+		return user, key, nil
+	}
+	if group != "" {
+		return "", nil, errors.New("only keys authorized for root can choose the group")
+	}
+	if user == "" {
+		if key := a.authorizedKey(client, a.AgentUser); key != nil && a.AgentUser != "" {
+			return "", key, nil
+		}
+		return first.User, first, nil
+	}
+	if key := a.authorizedKey(client, user); key != nil {
+		return user, key, nil
+	}
+	return "", nil, fmt.Errorf("client key is not authorized for user %s", user)
+}
+
+// AuthorizeAgentUser returns the authorized key entry which allows the client
+// to act as the agent's own user, or an error if it is not authorized for the
+// agent's user or for root. This is required for instructions which can only
+// run as the agent's user, such as shells and file transfers.
+func (a *Announcement) AuthorizeAgentUser(client *ClientKey) (*AuthorizedKey, error) {
+	user, key, err := a.AuthorizeKey(client, "", "")
+	if err != nil {
+		return nil, err
+	}
+	if user != "" {
+		return nil, fmt.Errorf("client key is not authorized for user %s", a.AgentUser)
+	}
+	return key, nil
+}
+
+// Authorizes returns true if any of the announced authorized keys authorizes
+// the client (see AuthorizedKey.Authorizes).
+func (a *Announcement) Authorizes(client *ClientKey) bool {
+	for _, k := range a.AuthorizedKeys {
+		if k.Authorizes(client) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizedKey returns the first entry which authorizes the client for the
+// given user, or for any user if user is empty.
+func (a *Announcement) authorizedKey(client *ClientKey, user string) *AuthorizedKey {
+	for _, k := range a.AuthorizedKeys {
+		if k.User != "" && (user == "" || k.User == user) && k.Authorizes(client) {
+			return k
+		}
+	}
+	return nil
+}
+
+// Finished returns true if cloud-init is not expected to change state, either
+// because it has completed or because it will not run at all.
+func (s *CloudInitStatus) Finished() bool {
+	switch s.GetState() {
+	case CloudInitState_NotRun, CloudInitState_Running:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IP address matching", func() {
+	ifaces := []*NetworkInterface{
+		{
+			Device: "eth0",
+			Addresses: []*Addr{
+				{Cidr: "10.1.2.3/24", Address: "10.1.2.3"},
+				{Cidr: "fd00:1::5/64", Address: "fd00:1::5"},
+			},
+		},
+	}
+	DescribeTable("matchIPAddress",
+		func(match string, expected bool) {
+			Expect(matchIPAddress(ifaces, match)).To(Equal(expected))
+		},
+		Entry("exact address", "10.1.2.3", true),
+		Entry("different address", "10.1.2.4", false),
+		Entry("containing IPv4 range", "10.0.0.0/8", true),
+		Entry("address's own CIDR", "10.1.2.3/24", true),
+		Entry("non-containing IPv4 range", "192.168.0.0/16", false),
+		Entry("containing IPv6 range", "fd00::/8", true),
+		Entry("exact IPv6 address", "fd00:1::5", true),
+		Entry("any of several ranges", "192.168.0.0/16, 10.0.0.0/8", true),
+		Entry("excluded subnet", "10.0.0.0/8,!10.1.0.0/16", false),
+		Entry("excluded subnet, other address included", "10.0.0.0/8,!10.1.0.0/16,fd00::/8", true),
+		Entry("only exclusions", "!192.168.0.0/16", true),
+		Entry("only exclusions covering all addresses", "!10.0.0.0/8,!fd00::/8", false),
+		Entry("invalid range", "10.0.0.0/33", false),
+		Entry("empty", "", false),
+	)
+})
+
+var _ = Describe("Run-as users", func() {
+	an := &Announcement{
+		AgentUser: "root",
+		AuthorizedKeys: []*AuthorizedKey{
+			{User: "root", Fingerprint: "admin"},
+			{User: "alice", Fingerprint: "alice"},
+			{User: "alice", Fingerprint: "shared"},
+			{User: "bob", Fingerprint: "shared"},
+		},
+	}
+	DescribeTable("RunAsUser",
+		func(fingerprint, user, group, expected string, allowed bool) {
+			runAs, err := an.RunAsUser(&ClientKey{Fingerprint: fingerprint}, user, group)
+			if !allowed {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runAs).To(Equal(expected))
+		},
+		Entry("root key, no user", "admin", "", "", "", true),
+		Entry("root key, any user", "admin", "bob", "", "bob", true),
+		Entry("root key, any group", "admin", "bob", "wheel", "bob", true),
+		Entry("user key, no user", "alice", "", "", "alice", true),
+		Entry("user key, own user", "alice", "alice", "", "alice", true),
+		Entry("user key, other user", "alice", "bob", "", "", false),
+		Entry("user key, root", "alice", "root", "", "", false),
+		Entry("user key, group", "alice", "alice", "wheel", "", false),
+		Entry("key for several users, no user", "shared", "", "", "alice", true),
+		Entry("key for several users, second user", "shared", "bob", "", "bob", true),
+		Entry("unknown key", "unknown", "", "", "", false),
+	)
+	It("should run as the agent's user if the key is authorized for it", func() {
+		an := &Announcement{
+			AgentUser: "alice",
+			AuthorizedKeys: []*AuthorizedKey{
+				{User: "alice", Fingerprint: "alice"},
+			},
+		}
+		runAs, err := an.RunAsUser(&ClientKey{Fingerprint: "alice"}, "", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runAs).To(BeEmpty())
+		_, err = an.AuthorizeAgentUser(&ClientKey{Fingerprint: "alice"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+	It("should only allow keys authorized for the agent's user to act as it", func() {
+		key, err := an.AuthorizeAgentUser(&ClientKey{Fingerprint: "admin"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key.User).To(Equal("root"))
+		_, err = an.AuthorizeAgentUser(&ClientKey{Fingerprint: "alice"})
+		Expect(err).To(HaveOccurred())
+		_, err = an.AuthorizeAgentUser(&ClientKey{Fingerprint: "unknown"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -9,7 +9,9 @@ package api
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	_ "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -21,12 +23,63 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type OutputSource int32
+
+const (
+	OutputSource_Stdout OutputSource = 0
+	OutputSource_Stderr OutputSource = 1
+)
+
+// Enum value maps for OutputSource.
+var (
+	OutputSource_name = map[int32]string{
+		0: "Stdout",
+		1: "Stderr",
+	}
+	OutputSource_value = map[string]int32{
+		"Stdout": 0,
+		"Stderr": 1,
+	}
+)
+
+func (x OutputSource) Enum() *OutputSource {
+	p := new(OutputSource)
+	*p = x
+	return p
+}
+
+func (x OutputSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutputSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_api_instructions_proto_enumTypes[0].Descriptor()
+}
+
+func (OutputSource) Type() protoreflect.EnumType {
+	return &file_pkg_api_instructions_proto_enumTypes[0]
+}
+
+func (x OutputSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutputSource.Descriptor instead.
+func (OutputSource) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{0}
+}
+
 type InstructionMeta struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PeerFingerprint string `protobuf:"bytes,1,opt,name=PeerFingerprint,proto3" json:"PeerFingerprint,omitempty"`
+	PeerFingerprint   string `protobuf:"bytes,1,opt,name=PeerFingerprint,proto3" json:"PeerFingerprint,omitempty"`
+	StreamID          string `protobuf:"bytes,2,opt,name=StreamID,proto3" json:"StreamID,omitempty"`
+	InstructionID     string `protobuf:"bytes,3,opt,name=InstructionID,proto3" json:"InstructionID,omitempty"`
+	ClientFingerprint string `protobuf:"bytes,4,opt,name=ClientFingerprint,proto3" json:"ClientFingerprint,omitempty"`
+	ClientAddress     string `protobuf:"bytes,5,opt,name=ClientAddress,proto3" json:"ClientAddress,omitempty"`
+	ClientCertificate []byte `protobuf:"bytes,6,opt,name=ClientCertificate,proto3" json:"ClientCertificate,omitempty"`
 }
 
 func (x *InstructionMeta) Reset() {
@@ -68,6 +121,88 @@ func (x *InstructionMeta) GetPeerFingerprint() string {
 	return ""
 }
 
+func (x *InstructionMeta) GetStreamID() string {
+	if x != nil {
+		return x.StreamID
+	}
+	return ""
+}
+
+func (x *InstructionMeta) GetInstructionID() string {
+	if x != nil {
+		return x.InstructionID
+	}
+	return ""
+}
+
+func (x *InstructionMeta) GetClientFingerprint() string {
+	if x != nil {
+		return x.ClientFingerprint
+	}
+	return ""
+}
+
+func (x *InstructionMeta) GetClientAddress() string {
+	if x != nil {
+		return x.ClientAddress
+	}
+	return ""
+}
+
+func (x *InstructionMeta) GetClientCertificate() []byte {
+	if x != nil {
+		return x.ClientCertificate
+	}
+	return nil
+}
+
+type CancelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *InstructionMeta `protobuf:"bytes,1,opt,name=Meta,proto3" json:"Meta,omitempty"`
+}
+
+func (x *CancelRequest) Reset() {
+	*x = CancelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRequest) ProtoMessage() {}
+
+func (x *CancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CancelRequest) GetMeta() *InstructionMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
 type CommandRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -80,7 +215,7 @@ type CommandRequest struct {
 func (x *CommandRequest) Reset() {
 	*x = CommandRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_api_instructions_proto_msgTypes[1]
+		mi := &file_pkg_api_instructions_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -93,7 +228,7 @@ func (x *CommandRequest) String() string {
 func (*CommandRequest) ProtoMessage() {}
 
 func (x *CommandRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_instructions_proto_msgTypes[1]
+	mi := &file_pkg_api_instructions_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -106,7 +241,7 @@ func (x *CommandRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommandRequest.ProtoReflect.Descriptor instead.
 func (*CommandRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{1}
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CommandRequest) GetMeta() *InstructionMeta {
@@ -128,15 +263,21 @@ type Command struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Command string   `protobuf:"bytes,1,opt,name=Command,proto3" json:"Command,omitempty"`
-	Args    []string `protobuf:"bytes,2,rep,name=Args,proto3" json:"Args,omitempty"`
-	Env     []string `protobuf:"bytes,3,rep,name=Env,proto3" json:"Env,omitempty"`
+	Command    string               `protobuf:"bytes,1,opt,name=Command,proto3" json:"Command,omitempty"`
+	Args       []string             `protobuf:"bytes,2,rep,name=Args,proto3" json:"Args,omitempty"`
+	Env        []string             `protobuf:"bytes,3,rep,name=Env,proto3" json:"Env,omitempty"`
+	Timeout    *durationpb.Duration `protobuf:"bytes,4,opt,name=Timeout,proto3" json:"Timeout,omitempty"`
+	User       string               `protobuf:"bytes,5,opt,name=User,proto3" json:"User,omitempty"`
+	Group      string               `protobuf:"bytes,6,opt,name=Group,proto3" json:"Group,omitempty"`
+	WorkingDir string               `protobuf:"bytes,7,opt,name=WorkingDir,proto3" json:"WorkingDir,omitempty"`
+	Umask      *uint32              `protobuf:"varint,8,opt,name=Umask,proto3,oneof" json:"Umask,omitempty"`
+	Stdin      []byte               `protobuf:"bytes,9,opt,name=Stdin,proto3" json:"Stdin,omitempty"`
 }
 
 func (x *Command) Reset() {
 	*x = Command{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_api_instructions_proto_msgTypes[2]
+		mi := &file_pkg_api_instructions_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -149,7 +290,7 @@ func (x *Command) String() string {
 func (*Command) ProtoMessage() {}
 
 func (x *Command) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_instructions_proto_msgTypes[2]
+	mi := &file_pkg_api_instructions_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -162,7 +303,7 @@ func (x *Command) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Command.ProtoReflect.Descriptor instead.
 func (*Command) Descriptor() ([]byte, []int) {
-	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{2}
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Command) GetCommand() string {
@@ -186,6 +327,48 @@ func (x *Command) GetEnv() []string {
 	return nil
 }
 
+func (x *Command) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Command) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Command) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Command) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+func (x *Command) GetUmask() uint32 {
+	if x != nil && x.Umask != nil {
+		return *x.Umask
+	}
+	return 0
+}
+
+func (x *Command) GetStdin() []byte {
+	if x != nil {
+		return x.Stdin
+	}
+	return nil
+}
+
 type ScriptRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -198,7 +381,7 @@ type ScriptRequest struct {
 func (x *ScriptRequest) Reset() {
 	*x = ScriptRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_api_instructions_proto_msgTypes[3]
+		mi := &file_pkg_api_instructions_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -211,7 +394,7 @@ func (x *ScriptRequest) String() string {
 func (*ScriptRequest) ProtoMessage() {}
 
 func (x *ScriptRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_instructions_proto_msgTypes[3]
+	mi := &file_pkg_api_instructions_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -224,7 +407,7 @@ func (x *ScriptRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScriptRequest.ProtoReflect.Descriptor instead.
 func (*ScriptRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{3}
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ScriptRequest) GetMeta() *InstructionMeta {
@@ -246,15 +429,21 @@ type Script struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Interpreter string   `protobuf:"bytes,1,opt,name=Interpreter,proto3" json:"Interpreter,omitempty"`
-	Script      string   `protobuf:"bytes,2,opt,name=Script,proto3" json:"Script,omitempty"`
-	Args        []string `protobuf:"bytes,3,rep,name=Args,proto3" json:"Args,omitempty"`
+	Interpreter string               `protobuf:"bytes,1,opt,name=Interpreter,proto3" json:"Interpreter,omitempty"`
+	Script      string               `protobuf:"bytes,2,opt,name=Script,proto3" json:"Script,omitempty"`
+	Args        []string             `protobuf:"bytes,3,rep,name=Args,proto3" json:"Args,omitempty"`
+	Timeout     *durationpb.Duration `protobuf:"bytes,4,opt,name=Timeout,proto3" json:"Timeout,omitempty"`
+	User        string               `protobuf:"bytes,5,opt,name=User,proto3" json:"User,omitempty"`
+	Group       string               `protobuf:"bytes,6,opt,name=Group,proto3" json:"Group,omitempty"`
+	WorkingDir  string               `protobuf:"bytes,7,opt,name=WorkingDir,proto3" json:"WorkingDir,omitempty"`
+	Umask       *uint32              `protobuf:"varint,8,opt,name=Umask,proto3,oneof" json:"Umask,omitempty"`
+	Stdin       []byte               `protobuf:"bytes,9,opt,name=Stdin,proto3" json:"Stdin,omitempty"`
 }
 
 func (x *Script) Reset() {
 	*x = Script{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_api_instructions_proto_msgTypes[4]
+		mi := &file_pkg_api_instructions_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -267,7 +456,7 @@ func (x *Script) String() string {
 func (*Script) ProtoMessage() {}
 
 func (x *Script) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_instructions_proto_msgTypes[4]
+	mi := &file_pkg_api_instructions_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -280,7 +469,7 @@ func (x *Script) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Script.ProtoReflect.Descriptor instead.
 func (*Script) Descriptor() ([]byte, []int) {
-	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{4}
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Script) GetInterpreter() string {
@@ -304,20 +493,64 @@ func (x *Script) GetArgs() []string {
 	return nil
 }
 
+func (x *Script) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Script) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Script) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Script) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+func (x *Script) GetUmask() uint32 {
+	if x != nil && x.Umask != nil {
+		return *x.Umask
+	}
+	return 0
+}
+
+func (x *Script) GetStdin() []byte {
+	if x != nil {
+		return x.Stdin
+	}
+	return nil
+}
+
 type CommandResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ExitCode int32  `protobuf:"varint,2,opt,name=ExitCode,proto3" json:"ExitCode,omitempty"`
-	Stdout   string `protobuf:"bytes,3,opt,name=Stdout,proto3" json:"Stdout,omitempty"`
-	Stderr   string `protobuf:"bytes,4,opt,name=Stderr,proto3" json:"Stderr,omitempty"`
+	ExitCode          int32  `protobuf:"varint,2,opt,name=ExitCode,proto3" json:"ExitCode,omitempty"`
+	Stdout            string `protobuf:"bytes,3,opt,name=Stdout,proto3" json:"Stdout,omitempty"`
+	Stderr            string `protobuf:"bytes,4,opt,name=Stderr,proto3" json:"Stderr,omitempty"`
+	Terminated        bool   `protobuf:"varint,5,opt,name=Terminated,proto3" json:"Terminated,omitempty"`
+	TerminationReason string `protobuf:"bytes,6,opt,name=TerminationReason,proto3" json:"TerminationReason,omitempty"`
 }
 
 func (x *CommandResponse) Reset() {
 	*x = CommandResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_api_instructions_proto_msgTypes[5]
+		mi := &file_pkg_api_instructions_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -330,7 +563,7 @@ func (x *CommandResponse) String() string {
 func (*CommandResponse) ProtoMessage() {}
 
 func (x *CommandResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_instructions_proto_msgTypes[5]
+	mi := &file_pkg_api_instructions_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -343,7 +576,7 @@ func (x *CommandResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommandResponse.ProtoReflect.Descriptor instead.
 func (*CommandResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{5}
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CommandResponse) GetExitCode() int32 {
@@ -367,20 +600,36 @@ func (x *CommandResponse) GetStderr() string {
 	return ""
 }
 
+func (x *CommandResponse) GetTerminated() bool {
+	if x != nil {
+		return x.Terminated
+	}
+	return false
+}
+
+func (x *CommandResponse) GetTerminationReason() string {
+	if x != nil {
+		return x.TerminationReason
+	}
+	return ""
+}
+
 type ScriptResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ExitCode int32  `protobuf:"varint,1,opt,name=ExitCode,proto3" json:"ExitCode,omitempty"`
-	Stdout   string `protobuf:"bytes,2,opt,name=Stdout,proto3" json:"Stdout,omitempty"`
-	Stderr   string `protobuf:"bytes,3,opt,name=Stderr,proto3" json:"Stderr,omitempty"`
+	ExitCode          int32  `protobuf:"varint,1,opt,name=ExitCode,proto3" json:"ExitCode,omitempty"`
+	Stdout            string `protobuf:"bytes,2,opt,name=Stdout,proto3" json:"Stdout,omitempty"`
+	Stderr            string `protobuf:"bytes,3,opt,name=Stderr,proto3" json:"Stderr,omitempty"`
+	Terminated        bool   `protobuf:"varint,4,opt,name=Terminated,proto3" json:"Terminated,omitempty"`
+	TerminationReason string `protobuf:"bytes,5,opt,name=TerminationReason,proto3" json:"TerminationReason,omitempty"`
 }
 
 func (x *ScriptResponse) Reset() {
 	*x = ScriptResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_api_instructions_proto_msgTypes[6]
+		mi := &file_pkg_api_instructions_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -393,7 +642,7 @@ func (x *ScriptResponse) String() string {
 func (*ScriptResponse) ProtoMessage() {}
 
 func (x *ScriptResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_instructions_proto_msgTypes[6]
+	mi := &file_pkg_api_instructions_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -406,7 +655,7 @@ func (x *ScriptResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScriptResponse.ProtoReflect.Descriptor instead.
 func (*ScriptResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{6}
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ScriptResponse) GetExitCode() int32 {
@@ -430,128 +679,1087 @@ func (x *ScriptResponse) GetStderr() string {
 	return ""
 }
 
-var File_pkg_api_instructions_proto protoreflect.FileDescriptor
+func (x *ScriptResponse) GetTerminated() bool {
+	if x != nil {
+		return x.Terminated
+	}
+	return false
+}
 
-var file_pkg_api_instructions_proto_rawDesc = []byte{
-	0x0a, 0x1a, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x69, 0x6e, 0x73, 0x74, 0x72, 0x75,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x61, 0x70,
-	0x69, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2e,
-	0x0a, 0x0f, 0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74,
-	0x61, 0x12, 0x19, 0x0a, 0x0f, 0x50, 0x65, 0x65, 0x72, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70,
-	0x72, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x59,
-	0x0a, 0x0e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x24, 0x0a, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
-	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x4d, 0x65, 0x74, 0x61, 0x42, 0x00, 0x12, 0x1f, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
-	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f,
-	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x3d, 0x0a, 0x07, 0x43, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x12, 0x11, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x41, 0x72, 0x67, 0x73, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0d, 0x0a, 0x03, 0x45, 0x6e, 0x76, 0x18, 0x03,
-	0x20, 0x03, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x56, 0x0a, 0x0d, 0x53, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x04, 0x4d, 0x65, 0x74,
-	0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x49, 0x6e,
-	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x42, 0x00, 0x12,
-	0x1d, 0x0a, 0x06, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x42, 0x00, 0x3a, 0x00,
-	0x22, 0x43, 0x0a, 0x06, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x15, 0x0a, 0x0b, 0x49, 0x6e,
-	0x74, 0x65, 0x72, 0x70, 0x72, 0x65, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42,
-	0x00, 0x12, 0x10, 0x0a, 0x06, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x41, 0x72, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
-	0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x4b, 0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x08, 0x45, 0x78, 0x69, 0x74,
-	0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06,
-	0x53, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x10,
-	0x0a, 0x06, 0x53, 0x74, 0x64, 0x65, 0x72, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00,
-	0x3a, 0x00, 0x22, 0x4a, 0x0a, 0x0e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x08, 0x45, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x53, 0x74, 0x64, 0x6f,
-	0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x53, 0x74,
-	0x64, 0x65, 0x72, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x42, 0x27,
-	0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x72, 0x61,
-	0x6c, 0x69, 0x63, 0x6b, 0x79, 0x2f, 0x70, 0x6f, 0x73, 0x74, 0x2d, 0x69, 0x6e, 0x69, 0x74, 0x2f,
-	0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *ScriptResponse) GetTerminationReason() string {
+	if x != nil {
+		return x.TerminationReason
+	}
+	return ""
 }
 
-var (
-	file_pkg_api_instructions_proto_rawDescOnce sync.Once
-	file_pkg_api_instructions_proto_rawDescData = file_pkg_api_instructions_proto_rawDesc
-)
+type OutputEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func file_pkg_api_instructions_proto_rawDescGZIP() []byte {
-	file_pkg_api_instructions_proto_rawDescOnce.Do(func() {
-		file_pkg_api_instructions_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_api_instructions_proto_rawDescData)
-	})
-	return file_pkg_api_instructions_proto_rawDescData
+	StreamID  string                 `protobuf:"bytes,1,opt,name=StreamID,proto3" json:"StreamID,omitempty"`
+	Sequence  uint64                 `protobuf:"varint,2,opt,name=Sequence,proto3" json:"Sequence,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	// Types that are assignable to Event:
+	//	*OutputEvent_Output
+	//	*OutputEvent_Exit
+	Event isOutputEvent_Event `protobuf_oneof:"Event"`
 }
 
-var file_pkg_api_instructions_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
-var file_pkg_api_instructions_proto_goTypes = []interface{}{
-	(*InstructionMeta)(nil), // 0: api.InstructionMeta
-	(*CommandRequest)(nil),  // 1: api.CommandRequest
-	(*Command)(nil),         // 2: api.Command
-	(*ScriptRequest)(nil),   // 3: api.ScriptRequest
-	(*Script)(nil),          // 4: api.Script
-	(*CommandResponse)(nil), // 5: api.CommandResponse
-	(*ScriptResponse)(nil),  // 6: api.ScriptResponse
+func (x *OutputEvent) Reset() {
+	*x = OutputEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-var file_pkg_api_instructions_proto_depIdxs = []int32{
-	0, // 0: api.CommandRequest.Meta:type_name -> api.InstructionMeta
-	2, // 1: api.CommandRequest.Command:type_name -> api.Command
-	0, // 2: api.ScriptRequest.Meta:type_name -> api.InstructionMeta
-	4, // 3: api.ScriptRequest.Script:type_name -> api.Script
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+
+func (x *OutputEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_pkg_api_instructions_proto_init() }
-func file_pkg_api_instructions_proto_init() {
-	if File_pkg_api_instructions_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_pkg_api_instructions_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InstructionMeta); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pkg_api_instructions_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CommandRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+func (*OutputEvent) ProtoMessage() {}
+
+func (x *OutputEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_pkg_api_instructions_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Command); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutputEvent.ProtoReflect.Descriptor instead.
+func (*OutputEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *OutputEvent) GetStreamID() string {
+	if x != nil {
+		return x.StreamID
+	}
+	return ""
+}
+
+func (x *OutputEvent) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *OutputEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (m *OutputEvent) GetEvent() isOutputEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *OutputEvent) GetOutput() *OutputChunk {
+	if x, ok := x.GetEvent().(*OutputEvent_Output); ok {
+		return x.Output
+	}
+	return nil
+}
+
+func (x *OutputEvent) GetExit() *ExitStatus {
+	if x, ok := x.GetEvent().(*OutputEvent_Exit); ok {
+		return x.Exit
+	}
+	return nil
+}
+
+type isOutputEvent_Event interface {
+	isOutputEvent_Event()
+}
+
+type OutputEvent_Output struct {
+	Output *OutputChunk `protobuf:"bytes,4,opt,name=Output,proto3,oneof"`
+}
+
+type OutputEvent_Exit struct {
+	Exit *ExitStatus `protobuf:"bytes,5,opt,name=Exit,proto3,oneof"`
+}
+
+func (*OutputEvent_Output) isOutputEvent_Event() {}
+
+func (*OutputEvent_Exit) isOutputEvent_Event() {}
+
+type OutputChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source OutputSource `protobuf:"varint,1,opt,name=Source,proto3,enum=api.OutputSource" json:"Source,omitempty"`
+	Data   []byte       `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
+}
+
+func (x *OutputChunk) Reset() {
+	*x = OutputChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutputChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutputChunk) ProtoMessage() {}
+
+func (x *OutputChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutputChunk.ProtoReflect.Descriptor instead.
+func (*OutputChunk) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OutputChunk) GetSource() OutputSource {
+	if x != nil {
+		return x.Source
+	}
+	return OutputSource_Stdout
+}
+
+func (x *OutputChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ExitStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ExitCode          int32  `protobuf:"varint,1,opt,name=ExitCode,proto3" json:"ExitCode,omitempty"`
+	Terminated        bool   `protobuf:"varint,2,opt,name=Terminated,proto3" json:"Terminated,omitempty"`
+	TerminationReason string `protobuf:"bytes,3,opt,name=TerminationReason,proto3" json:"TerminationReason,omitempty"`
+}
+
+func (x *ExitStatus) Reset() {
+	*x = ExitStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExitStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExitStatus) ProtoMessage() {}
+
+func (x *ExitStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExitStatus.ProtoReflect.Descriptor instead.
+func (*ExitStatus) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExitStatus) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *ExitStatus) GetTerminated() bool {
+	if x != nil {
+		return x.Terminated
+	}
+	return false
+}
+
+func (x *ExitStatus) GetTerminationReason() string {
+	if x != nil {
+		return x.TerminationReason
+	}
+	return ""
+}
+
+type ShellRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta  *InstructionMeta `protobuf:"bytes,1,opt,name=Meta,proto3" json:"Meta,omitempty"`
+	Shell *Shell           `protobuf:"bytes,2,opt,name=Shell,proto3" json:"Shell,omitempty"`
+}
+
+func (x *ShellRequest) Reset() {
+	*x = ShellRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShellRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShellRequest) ProtoMessage() {}
+
+func (x *ShellRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShellRequest.ProtoReflect.Descriptor instead.
+func (*ShellRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ShellRequest) GetMeta() *InstructionMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *ShellRequest) GetShell() *Shell {
+	if x != nil {
+		return x.Shell
+	}
+	return nil
+}
+
+type Shell struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Term string      `protobuf:"bytes,1,opt,name=Term,proto3" json:"Term,omitempty"`
+	Size *WindowSize `protobuf:"bytes,2,opt,name=Size,proto3" json:"Size,omitempty"`
+	Env  []string    `protobuf:"bytes,3,rep,name=Env,proto3" json:"Env,omitempty"`
+}
+
+func (x *Shell) Reset() {
+	*x = Shell{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Shell) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shell) ProtoMessage() {}
+
+func (x *Shell) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shell.ProtoReflect.Descriptor instead.
+func (*Shell) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Shell) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *Shell) GetSize() *WindowSize {
+	if x != nil {
+		return x.Size
+	}
+	return nil
+}
+
+func (x *Shell) GetEnv() []string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+type WindowSize struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rows uint32 `protobuf:"varint,1,opt,name=Rows,proto3" json:"Rows,omitempty"`
+	Cols uint32 `protobuf:"varint,2,opt,name=Cols,proto3" json:"Cols,omitempty"`
+}
+
+func (x *WindowSize) Reset() {
+	*x = WindowSize{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WindowSize) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WindowSize) ProtoMessage() {}
+
+func (x *WindowSize) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WindowSize.ProtoReflect.Descriptor instead.
+func (*WindowSize) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WindowSize) GetRows() uint32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *WindowSize) GetCols() uint32 {
+	if x != nil {
+		return x.Cols
+	}
+	return 0
+}
+
+type ShellInputRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *InstructionMeta `protobuf:"bytes,1,opt,name=Meta,proto3" json:"Meta,omitempty"`
+	// Types that are assignable to Input:
+	//	*ShellInputRequest_Data
+	//	*ShellInputRequest_Resize
+	//	*ShellInputRequest_Signal
+	Input isShellInputRequest_Input `protobuf_oneof:"Input"`
+}
+
+func (x *ShellInputRequest) Reset() {
+	*x = ShellInputRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShellInputRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShellInputRequest) ProtoMessage() {}
+
+func (x *ShellInputRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShellInputRequest.ProtoReflect.Descriptor instead.
+func (*ShellInputRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ShellInputRequest) GetMeta() *InstructionMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (m *ShellInputRequest) GetInput() isShellInputRequest_Input {
+	if m != nil {
+		return m.Input
+	}
+	return nil
+}
+
+func (x *ShellInputRequest) GetData() []byte {
+	if x, ok := x.GetInput().(*ShellInputRequest_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ShellInputRequest) GetResize() *WindowSize {
+	if x, ok := x.GetInput().(*ShellInputRequest_Resize); ok {
+		return x.Resize
+	}
+	return nil
+}
+
+func (x *ShellInputRequest) GetSignal() string {
+	if x, ok := x.GetInput().(*ShellInputRequest_Signal); ok {
+		return x.Signal
+	}
+	return ""
+}
+
+type isShellInputRequest_Input interface {
+	isShellInputRequest_Input()
+}
+
+type ShellInputRequest_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=Data,proto3,oneof"`
+}
+
+type ShellInputRequest_Resize struct {
+	Resize *WindowSize `protobuf:"bytes,3,opt,name=Resize,proto3,oneof"`
+}
+
+type ShellInputRequest_Signal struct {
+	Signal string `protobuf:"bytes,4,opt,name=Signal,proto3,oneof"`
+}
+
+func (*ShellInputRequest_Data) isShellInputRequest_Input() {}
+
+func (*ShellInputRequest_Resize) isShellInputRequest_Input() {}
+
+func (*ShellInputRequest_Signal) isShellInputRequest_Input() {}
+
+type FileInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path      string `protobuf:"bytes,1,opt,name=Path,proto3" json:"Path,omitempty"`
+	Mode      uint32 `protobuf:"varint,2,opt,name=Mode,proto3" json:"Mode,omitempty"`
+	Owner     string `protobuf:"bytes,3,opt,name=Owner,proto3" json:"Owner,omitempty"`
+	Group     string `protobuf:"bytes,4,opt,name=Group,proto3" json:"Group,omitempty"`
+	Size      int64  `protobuf:"varint,5,opt,name=Size,proto3" json:"Size,omitempty"`
+	Checksum  string `protobuf:"bytes,6,opt,name=Checksum,proto3" json:"Checksum,omitempty"`
+	Directory bool   `protobuf:"varint,7,opt,name=Directory,proto3" json:"Directory,omitempty"`
+}
+
+func (x *FileInfo) Reset() {
+	*x = FileInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FileInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileInfo) ProtoMessage() {}
+
+func (x *FileInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileInfo.ProtoReflect.Descriptor instead.
+func (*FileInfo) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *FileInfo) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileInfo) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *FileInfo) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *FileInfo) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *FileInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileInfo) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *FileInfo) GetDirectory() bool {
+	if x != nil {
+		return x.Directory
+	}
+	return false
+}
+
+type PutFileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta   *InstructionMeta `protobuf:"bytes,1,opt,name=Meta,proto3" json:"Meta,omitempty"`
+	Info   *FileInfo        `protobuf:"bytes,2,opt,name=Info,proto3" json:"Info,omitempty"`
+	Offset int64            `protobuf:"varint,3,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	Data   []byte           `protobuf:"bytes,4,opt,name=Data,proto3" json:"Data,omitempty"`
+	Done   bool             `protobuf:"varint,5,opt,name=Done,proto3" json:"Done,omitempty"`
+}
+
+func (x *PutFileRequest) Reset() {
+	*x = PutFileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PutFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutFileRequest) ProtoMessage() {}
+
+func (x *PutFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutFileRequest.ProtoReflect.Descriptor instead.
+func (*PutFileRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PutFileRequest) GetMeta() *InstructionMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *PutFileRequest) GetInfo() *FileInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *PutFileRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *PutFileRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *PutFileRequest) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+type GetFileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta   *InstructionMeta `protobuf:"bytes,1,opt,name=Meta,proto3" json:"Meta,omitempty"`
+	Path   string           `protobuf:"bytes,2,opt,name=Path,proto3" json:"Path,omitempty"`
+	Offset int64            `protobuf:"varint,3,opt,name=Offset,proto3" json:"Offset,omitempty"`
+}
+
+func (x *GetFileRequest) Reset() {
+	*x = GetFileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileRequest) ProtoMessage() {}
+
+func (x *GetFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileRequest.ProtoReflect.Descriptor instead.
+func (*GetFileRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetFileRequest) GetMeta() *InstructionMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *GetFileRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GetFileRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type GetFileResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Info *FileInfo `protobuf:"bytes,1,opt,name=Info,proto3" json:"Info,omitempty"`
+	Data []byte    `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
+	EOF  bool      `protobuf:"varint,3,opt,name=EOF,proto3" json:"EOF,omitempty"`
+}
+
+func (x *GetFileResponse) Reset() {
+	*x = GetFileResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_api_instructions_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileResponse) ProtoMessage() {}
+
+func (x *GetFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_instructions_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileResponse.ProtoReflect.Descriptor instead.
+func (*GetFileResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_instructions_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetFileResponse) GetInfo() *FileInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *GetFileResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *GetFileResponse) GetEOF() bool {
+	if x != nil {
+		return x.EOF
+	}
+	return false
+}
+
+var File_pkg_api_instructions_proto protoreflect.FileDescriptor
+
+var file_pkg_api_instructions_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x69, 0x6e, 0x73, 0x74, 0x72, 0x75,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x61, 0x70,
+	0x69, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0xae, 0x01, 0x0a, 0x0f, 0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d,
+	0x65, 0x74, 0x61, 0x12, 0x19, 0x0a, 0x0f, 0x50, 0x65, 0x65, 0x72, 0x46, 0x69, 0x6e, 0x67, 0x65,
+	0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x12,
+	0x0a, 0x08, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x00, 0x12, 0x17, 0x0a, 0x0d, 0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x1b, 0x0a, 0x11, 0x43,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x17, 0x0a, 0x0d, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x00, 0x12, 0x1b, 0x0a, 0x11, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x3a, 0x00,
+	0x22, 0x37, 0x0a, 0x0d, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x24, 0x0a, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x4d, 0x65, 0x74, 0x61, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x59, 0x0a, 0x0e, 0x43, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x04, 0x4d,
+	0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x42,
+	0x00, 0x12, 0x1f, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x42, 0x00, 0x3a, 0x00, 0x22, 0xd3, 0x01, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x12, 0x11, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x41, 0x72, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x42, 0x00, 0x12, 0x0d, 0x0a, 0x03, 0x45, 0x6e, 0x76, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x42, 0x00, 0x12, 0x2c, 0x0a, 0x07, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x00,
+	0x12, 0x0e, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00,
+	0x12, 0x0f, 0x0a, 0x05, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x00, 0x12, 0x14, 0x0a, 0x0a, 0x57, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x44, 0x69, 0x72, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x14, 0x0a, 0x05, 0x55, 0x6d, 0x61, 0x73, 0x6b,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x00, 0x48, 0x00, 0x88, 0x01, 0x01, 0x12, 0x0f, 0x0a,
+	0x05, 0x53, 0x74, 0x64, 0x69, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x3a, 0x00,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x55, 0x6d, 0x61, 0x73, 0x6b, 0x22, 0x56, 0x0a, 0x0d, 0x53, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x04, 0x4d,
+	0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x42,
+	0x00, 0x12, 0x1d, 0x0a, 0x06, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x42, 0x00,
+	0x3a, 0x00, 0x22, 0xd9, 0x01, 0x0a, 0x06, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x15, 0x0a,
+	0x0b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x72, 0x65, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x41, 0x72, 0x67, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x42, 0x00, 0x12, 0x2c, 0x0a, 0x07, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x42, 0x00, 0x12, 0x0f, 0x0a, 0x05, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x14, 0x0a, 0x0a, 0x57, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67,
+	0x44, 0x69, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x14, 0x0a, 0x05, 0x55,
+	0x6d, 0x61, 0x73, 0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x00, 0x48, 0x00, 0x88, 0x01,
+	0x01, 0x12, 0x0f, 0x0a, 0x05, 0x53, 0x74, 0x64, 0x69, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c,
+	0x42, 0x00, 0x3a, 0x00, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x55, 0x6d, 0x61, 0x73, 0x6b, 0x22, 0x7e,
+	0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x12, 0x0a, 0x08, 0x45, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x53, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x53, 0x74, 0x64, 0x65, 0x72,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x14, 0x0a, 0x0a, 0x54, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x42, 0x00, 0x12,
+	0x1b, 0x0a, 0x11, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x7d,
+	0x0a, 0x0e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x12, 0x0a, 0x08, 0x45, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x53, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x53, 0x74, 0x64, 0x65, 0x72, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x14, 0x0a, 0x0a, 0x54, 0x65, 0x72, 0x6d,
+	0x69, 0x6e, 0x61, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x42, 0x00, 0x12, 0x1b,
+	0x0a, 0x11, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0xba, 0x01,
+	0x0a, 0x0b, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a,
+	0x08, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x00, 0x12, 0x12, 0x0a, 0x08, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x42, 0x00, 0x12, 0x2f, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x42, 0x00, 0x12, 0x24, 0x0a, 0x06, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x00, 0x48, 0x00, 0x12, 0x21, 0x0a, 0x04,
+	0x45, 0x78, 0x69, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x42, 0x00, 0x48, 0x00, 0x3a,
+	0x00, 0x42, 0x07, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x44, 0x0a, 0x0b, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x23, 0x0a, 0x06, 0x53, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x42, 0x00, 0x12, 0x0e,
+	0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x3a, 0x00,
+	0x22, 0x55, 0x0a, 0x0a, 0x45, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12,
+	0x0a, 0x08, 0x45, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x42, 0x00, 0x12, 0x14, 0x0a, 0x0a, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x42, 0x00, 0x12, 0x1b, 0x0a, 0x11, 0x54, 0x65, 0x72, 0x6d,
+	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x53, 0x0a, 0x0c, 0x53, 0x68, 0x65, 0x6c, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x49, 0x6e, 0x73, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x42, 0x00, 0x12, 0x1b, 0x0a,
+	0x05, 0x53, 0x68, 0x65, 0x6c, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x6c, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x49, 0x0a, 0x05,
+	0x53, 0x68, 0x65, 0x6c, 0x6c, 0x12, 0x0e, 0x0a, 0x04, 0x54, 0x65, 0x72, 0x6d, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x1f, 0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x53, 0x69, 0x7a, 0x65, 0x42, 0x00, 0x12, 0x0d, 0x0a, 0x03, 0x45, 0x6e, 0x76, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x09, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x2e, 0x0a, 0x0a, 0x57, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x0e, 0x0a, 0x04, 0x52, 0x6f, 0x77, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x43, 0x6f, 0x6c, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x8f, 0x01, 0x0a, 0x11, 0x53, 0x68, 0x65, 0x6c,
+	0x6c, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a,
+	0x04, 0x4d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74,
+	0x61, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x42, 0x00, 0x48, 0x00, 0x12, 0x23, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x69, 0x6e, 0x64,
+	0x6f, 0x77, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x00, 0x48, 0x00, 0x12, 0x12, 0x0a, 0x06, 0x53, 0x69,
+	0x67, 0x6e, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x48, 0x00, 0x3a, 0x00,
+	0x42, 0x07, 0x0a, 0x05, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x22, 0x87, 0x01, 0x0a, 0x08, 0x46, 0x69,
+	0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x04, 0x50, 0x61, 0x74, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x42, 0x00, 0x12, 0x0f, 0x0a, 0x05, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0f, 0x0a, 0x05, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x42, 0x00, 0x12, 0x12, 0x0a, 0x08, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x73, 0x75, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x13, 0x0a, 0x09,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x42,
+	0x00, 0x3a, 0x00, 0x22, 0x89, 0x01, 0x0a, 0x0e, 0x50, 0x75, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x72,
+	0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x42, 0x00, 0x12, 0x1d, 0x0a, 0x04,
+	0x49, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x4f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x42, 0x00, 0x12, 0x0e, 0x0a,
+	0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x12, 0x0e, 0x0a,
+	0x04, 0x44, 0x6f, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x42, 0x00, 0x3a, 0x00, 0x22,
+	0x5a, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x24, 0x0a, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x4d, 0x65, 0x74, 0x61, 0x42, 0x00, 0x12, 0x0e, 0x0a, 0x04, 0x50, 0x61, 0x74, 0x68, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x00, 0x12, 0x10, 0x0a, 0x06, 0x4f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x42, 0x00, 0x3a, 0x00, 0x22, 0x51, 0x0a, 0x0f, 0x47,
+	0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d,
+	0x0a, 0x04, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x42, 0x00, 0x12, 0x0e, 0x0a,
+	0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x00, 0x12, 0x0d, 0x0a,
+	0x03, 0x45, 0x4f, 0x46, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x42, 0x00, 0x3a, 0x00, 0x2a, 0x28,
+	0x0a, 0x0c, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x0a,
+	0x0a, 0x06, 0x53, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x74,
+	0x64, 0x65, 0x72, 0x72, 0x10, 0x01, 0x1a, 0x00, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x72, 0x61, 0x6c, 0x69, 0x63, 0x6b, 0x79, 0x2f,
+	0x70, 0x6f, 0x73, 0x74, 0x2d, 0x69, 0x6e, 0x69, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70,
+	0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_api_instructions_proto_rawDescOnce sync.Once
+	file_pkg_api_instructions_proto_rawDescData = file_pkg_api_instructions_proto_rawDesc
+)
+
+func file_pkg_api_instructions_proto_rawDescGZIP() []byte {
+	file_pkg_api_instructions_proto_rawDescOnce.Do(func() {
+		file_pkg_api_instructions_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_api_instructions_proto_rawDescData)
+	})
+	return file_pkg_api_instructions_proto_rawDescData
+}
+
+var file_pkg_api_instructions_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_api_instructions_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_pkg_api_instructions_proto_goTypes = []interface{}{
+	(OutputSource)(0),             // 0: api.OutputSource
+	(*InstructionMeta)(nil),       // 1: api.InstructionMeta
+	(*CancelRequest)(nil),         // 2: api.CancelRequest
+	(*CommandRequest)(nil),        // 3: api.CommandRequest
+	(*Command)(nil),               // 4: api.Command
+	(*ScriptRequest)(nil),         // 5: api.ScriptRequest
+	(*Script)(nil),                // 6: api.Script
+	(*CommandResponse)(nil),       // 7: api.CommandResponse
+	(*ScriptResponse)(nil),        // 8: api.ScriptResponse
+	(*OutputEvent)(nil),           // 9: api.OutputEvent
+	(*OutputChunk)(nil),           // 10: api.OutputChunk
+	(*ExitStatus)(nil),            // 11: api.ExitStatus
+	(*ShellRequest)(nil),          // 12: api.ShellRequest
+	(*Shell)(nil),                 // 13: api.Shell
+	(*WindowSize)(nil),            // 14: api.WindowSize
+	(*ShellInputRequest)(nil),     // 15: api.ShellInputRequest
+	(*FileInfo)(nil),              // 16: api.FileInfo
+	(*PutFileRequest)(nil),        // 17: api.PutFileRequest
+	(*GetFileRequest)(nil),        // 18: api.GetFileRequest
+	(*GetFileResponse)(nil),       // 19: api.GetFileResponse
+	(*durationpb.Duration)(nil),   // 20: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 21: google.protobuf.Timestamp
+}
+var file_pkg_api_instructions_proto_depIdxs = []int32{
+	1,  // 0: api.CancelRequest.Meta:type_name -> api.InstructionMeta
+	1,  // 1: api.CommandRequest.Meta:type_name -> api.InstructionMeta
+	4,  // 2: api.CommandRequest.Command:type_name -> api.Command
+	20, // 3: api.Command.Timeout:type_name -> google.protobuf.Duration
+	1,  // 4: api.ScriptRequest.Meta:type_name -> api.InstructionMeta
+	6,  // 5: api.ScriptRequest.Script:type_name -> api.Script
+	20, // 6: api.Script.Timeout:type_name -> google.protobuf.Duration
+	21, // 7: api.OutputEvent.Timestamp:type_name -> google.protobuf.Timestamp
+	10, // 8: api.OutputEvent.Output:type_name -> api.OutputChunk
+	11, // 9: api.OutputEvent.Exit:type_name -> api.ExitStatus
+	0,  // 10: api.OutputChunk.Source:type_name -> api.OutputSource
+	1,  // 11: api.ShellRequest.Meta:type_name -> api.InstructionMeta
+	13, // 12: api.ShellRequest.Shell:type_name -> api.Shell
+	14, // 13: api.Shell.Size:type_name -> api.WindowSize
+	1,  // 14: api.ShellInputRequest.Meta:type_name -> api.InstructionMeta
+	14, // 15: api.ShellInputRequest.Resize:type_name -> api.WindowSize
+	1,  // 16: api.PutFileRequest.Meta:type_name -> api.InstructionMeta
+	16, // 17: api.PutFileRequest.Info:type_name -> api.FileInfo
+	1,  // 18: api.GetFileRequest.Meta:type_name -> api.InstructionMeta
+	16, // 19: api.GetFileResponse.Info:type_name -> api.FileInfo
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_pkg_api_instructions_proto_init() }
+func file_pkg_api_instructions_proto_init() {
+	if File_pkg_api_instructions_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_api_instructions_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InstructionMeta); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
 				return &v.unknownFields
 			default:
 				return nil
 			}
 		}
 		file_pkg_api_instructions_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ScriptRequest); i {
+			switch v := v.(*Command); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -563,7 +1771,7 @@ func file_pkg_api_instructions_proto_init() {
 			}
 		}
 		file_pkg_api_instructions_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Script); i {
+			switch v := v.(*ScriptRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -575,7 +1783,7 @@ func file_pkg_api_instructions_proto_init() {
 			}
 		}
 		file_pkg_api_instructions_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CommandResponse); i {
+			switch v := v.(*Script); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -587,6 +1795,18 @@ func file_pkg_api_instructions_proto_init() {
 			}
 		}
 		file_pkg_api_instructions_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ScriptResponse); i {
 			case 0:
 				return &v.state
@@ -598,19 +1818,163 @@ func file_pkg_api_instructions_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_api_instructions_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutputEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutputChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExitStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShellRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Shell); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WindowSize); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShellInputRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PutFileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_api_instructions_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFileResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pkg_api_instructions_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_pkg_api_instructions_proto_msgTypes[5].OneofWrappers = []interface{}{}
+	file_pkg_api_instructions_proto_msgTypes[8].OneofWrappers = []interface{}{
+		(*OutputEvent_Output)(nil),
+		(*OutputEvent_Exit)(nil),
+	}
+	file_pkg_api_instructions_proto_msgTypes[14].OneofWrappers = []interface{}{
+		(*ShellInputRequest_Data)(nil),
+		(*ShellInputRequest_Resize)(nil),
+		(*ShellInputRequest_Signal)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_api_instructions_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      1,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_pkg_api_instructions_proto_goTypes,
 		DependencyIndexes: file_pkg_api_instructions_proto_depIdxs,
+		EnumInfos:         file_pkg_api_instructions_proto_enumTypes,
 		MessageInfos:      file_pkg_api_instructions_proto_msgTypes,
 	}.Build()
 	File_pkg_api_instructions_proto = out.File
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// KeyOptions holds the options of an authorized key which agents enforce,
+// which have the same meaning as in sshd(8).
+type KeyOptions struct {
+	// If set, commands and scripts run this command instead, and shells and
+	// file transfers are not allowed.
+	Command string
+	// If set, a comma-separated list of address patterns which the client's
+	// address must match. Hostname patterns never match, since only the
+	// client's address is known.
+	From string
+	// If set, the key cannot be used from this time on
+	ExpiryTime time.Time
+	// Shells require a pty, so they are not allowed with no-pty
+	NoPty bool
+	// Agents do not forward ports, so this has no effect, but it is parsed
+	// for completeness.
+	NoPortForwarding bool
+	// If set, the entry's key is a CA, and the entry authorizes user
+	// certificates signed by it instead of the key itself
+	CertAuthority bool
+	// Principals which certificates must be valid for, instead of the
+	// entry's user. Only used with CertAuthority.
+	Principals []string
+
+	// Set by AuthorizedKey.ClientOptions for certificates, which are checked
+	// along with the options.
+	certificate *ssh.Certificate
+	principal   string
+}
+
+// ParseOptions parses the key's options. Options which agents do not
+// enforce are ignored. An error is returned if an option is malformed, in
+// which case the key should not be accepted.
+func (k *AuthorizedKey) ParseOptions() (*KeyOptions, error) {
+	opts := &KeyOptions{}
+	for _, opt := range k.Options {
+		name, value := opt, ""
+		if i := strings.IndexByte(opt, '='); i >= 0 {
+			var err error
+			name = opt[:i]
+			value, err = unquoteOption(opt[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s option: %w", name, err)
+			}
+		}
+		switch strings.ToLower(name) {
+		case "command":
+			opts.Command = value
+		case "from":
+			opts.From = value
+		case "expiry-time":
+			t, err := parseExpiryTime(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid expiry-time option: %w", err)
+			}
+			opts.ExpiryTime = t
+		case "cert-authority":
+			opts.CertAuthority = true
+		case "principals":
+			for _, p := range strings.Split(value, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					opts.Principals = append(opts.Principals, p)
+				}
+			}
+		case "restrict":
+			opts.NoPty = true
+			opts.NoPortForwarding = true
+		case "no-pty":
+			opts.NoPty = true
+		case "pty":
+			opts.NoPty = false
+		case "no-port-forwarding":
+			opts.NoPortForwarding = true
+		case "port-forwarding":
+			opts.NoPortForwarding = false
+		}
+	}
+	return opts, nil
+}
+
+// Check returns an error if the key cannot be used at the given time by a
+// client connecting from the given address. If the options were obtained for
+// a certificate, the certificate is checked as well (see CheckCertificate).
+func (o *KeyOptions) Check(address string, now time.Time) error {
+	if !o.ExpiryTime.IsZero() && !now.Before(o.ExpiryTime) {
+		return errors.New("client key has expired")
+	}
+	if o.From != "" && !matchFrom(o.From, address) {
+		if address == "" {
+			return errors.New("client key is restricted to certain addresses, and the client's address is unknown")
+		}
+		return fmt.Errorf("client key is not authorized from %s", address)
+	}
+	if o.certificate != nil {
+		return CheckCertificate(o.certificate, o.principal, address, now)
+	}
+	return nil
+}
+
+// unquoteOption returns the value of an option, which must be enclosed in
+// double quotes. Quotes inside the value are escaped with a backslash.
+func unquoteOption(value string) (string, error) {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return "", errors.New("value must be quoted")
+	}
+	return strings.ReplaceAll(value[1:len(value)-1], `\"`, `"`), nil
+}
+
+// parseExpiryTime parses a time in the form YYYYMMDD[HHMM[SS]], which is in
+// the local time zone unless it ends with Z.
+func parseExpiryTime(value string) (time.Time, error) {
+	loc := time.Local
+	if strings.HasSuffix(value, "Z") || strings.HasSuffix(value, "z") {
+		loc = time.UTC
+		value = value[:len(value)-1]
+	}
+	var layout string
+	switch len(value) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("invalid time %q", value)
+	}
+	return time.ParseInLocation(layout, value, loc)
+}
+
+// matchFrom returns true if the address matches the comma-separated list of
+// patterns, which can be CIDR ranges or addresses containing the wildcards *
+// and ?. The address must match at least one pattern, and must not match
+// any pattern prefixed with "!".
+func matchFrom(patterns, address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	matched := false
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		var ok bool
+		if strings.Contains(pattern, "/") {
+			_, ipNet, err := net.ParseCIDR(pattern)
+			ok = err == nil && ipNet.Contains(ip)
+		} else {
+			ok, _ = path.Match(pattern, ip.String())
+		}
+		if ok && negated {
+			return false
+		}
+		matched = matched || ok
+	}
+	return matched
+}
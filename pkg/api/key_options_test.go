@@ -0,0 +1,77 @@
+package api
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authorized key options", func() {
+	It("should parse options", func() {
+		key := &AuthorizedKey{
+			Options: []string{
+				`command="echo \"hello\""`,
+				`FROM="10.0.0.0/8"`,
+				`expiry-time="20300102Z"`,
+				`restrict`,
+				`pty`,
+				`environment="FOO=bar"`,
+			},
+		}
+		opts, err := key.ParseOptions()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts).To(Equal(&KeyOptions{
+			Command:          `echo "hello"`,
+			From:             "10.0.0.0/8",
+			ExpiryTime:       time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC),
+			NoPty:            false,
+			NoPortForwarding: true,
+		}))
+	})
+	DescribeTable("invalid options",
+		func(option string) {
+			_, err := (&AuthorizedKey{Options: []string{option}}).ParseOptions()
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unquoted value", `command=true`),
+		Entry("unterminated value", `from="10.0.0.0/8`),
+		Entry("invalid expiry time", `expiry-time="2030"`),
+		Entry("invalid expiry date", `expiry-time="20301301"`),
+	)
+	It("should parse expiry times in local time", func() {
+		opts, err := (&AuthorizedKey{Options: []string{`expiry-time="203001021530"`}}).ParseOptions()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts.ExpiryTime).To(Equal(time.Date(2030, 1, 2, 15, 30, 0, 0, time.Local)))
+	})
+	It("should reject expired keys", func() {
+		opts := &KeyOptions{
+			ExpiryTime: time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC),
+		}
+		Expect(opts.Check("", opts.ExpiryTime.Add(-time.Second))).To(Succeed())
+		Expect(opts.Check("", opts.ExpiryTime)).To(MatchError("client key has expired"))
+	})
+	DescribeTable("from",
+		func(from, address string, expected bool) {
+			err := (&KeyOptions{From: from}).Check(address, time.Now())
+			if expected {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("exact address", "10.1.2.3", "10.1.2.3", true),
+		Entry("different address", "10.1.2.3", "10.1.2.4", false),
+		Entry("CIDR range", "10.0.0.0/8", "10.1.2.3", true),
+		Entry("glob", "10.1.*", "10.1.2.3", true),
+		Entry("single character glob", "10.1.2.?", "10.1.2.34", false),
+		Entry("any of several patterns", "192.168.0.0/16, 10.0.0.0/8", "10.1.2.3", true),
+		Entry("negated pattern", "10.0.0.0/8,!10.1.*", "10.1.2.3", false),
+		Entry("only negated patterns", "!192.168.0.0/16", "10.1.2.3", false),
+		Entry("IPv6 range", "fd00::/8", "fd00:1::5", true),
+		Entry("IPv4-mapped address", "10.0.0.0/8", "::ffff:10.1.2.3", true),
+		Entry("hostname", "*.example.com", "10.1.2.3", false),
+		Entry("unknown address", "*", "", false),
+		Entry("no restriction", "", "", true),
+	)
+})
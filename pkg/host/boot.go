@@ -0,0 +1,88 @@
+package host
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/kralicky/post-init/pkg/api"
+)
+
+// A BootCondition reports whether the host has reached some point in the boot
+// process. Conditions are checked repeatedly until one of them is met.
+type BootCondition interface {
+	// String describes the condition in log messages and announcements.
+	String() string
+	Check(ctx context.Context) (bool, error)
+}
+
+// CloudInitFinished is met once cloud-init has reached a terminal state,
+// including if cloud-init is disabled or not installed.
+func CloudInitFinished(r *CloudInitReader) BootCondition {
+	return &cloudInitCondition{reader: r}
+}
+
+type cloudInitCondition struct {
+	reader *CloudInitReader
+	last   *api.CloudInitStatus
+}
+
+func (c *cloudInitCondition) String() string {
+	if c.last != nil {
+		return fmt.Sprintf("cloud-init (%s)", c.last.State)
+	}
+	return "cloud-init"
+}
+
+func (c *cloudInitCondition) Check(ctx context.Context) (bool, error) {
+	c.last = c.reader.Status(ctx)
+	return c.last.Finished(), nil
+}
+
+// BootTargetActive is met once the given systemd unit, such as
+// multi-user.target, is active.
+func BootTargetActive(target string) BootCondition {
+	return bootTargetCondition(target)
+}
+
+type bootTargetCondition string
+
+func (c bootTargetCondition) String() string {
+	return "target " + string(c)
+}
+
+func (c bootTargetCondition) Check(ctx context.Context) (bool, error) {
+	err := exec.CommandContext(ctx, "systemctl", "is-active", "--quiet", string(c)).Run()
+	if err == nil {
+		return true, nil
+	}
+	if _, ok := err.(*exec.ExitError); ok {
+		// The unit is not active yet
+		return false, nil
+	}
+	return false, err
+}
+
+// FileExists is met once a file exists at the given path, for example a
+// marker file written at the end of a user-data script.
+func FileExists(path string) BootCondition {
+	return fileCondition(path)
+}
+
+type fileCondition string
+
+func (c fileCondition) String() string {
+	return "file " + string(c)
+}
+
+func (c fileCondition) Check(ctx context.Context) (bool, error) {
+	_, err := os.Stat(string(c))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
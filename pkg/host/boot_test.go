@@ -0,0 +1,29 @@
+package host
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Boot Conditions", func() {
+	It("should be met once the marker file exists", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "ready")
+		c := FileExists(path)
+		Expect(c.Check(context.Background())).To(BeFalse())
+		Expect(os.WriteFile(path, nil, 0o644)).To(Succeed())
+		Expect(c.Check(context.Background())).To(BeTrue())
+		Expect(c.String()).To(Equal("file " + path))
+	})
+	It("should be met once cloud-init has finished", func() {
+		running := CloudInitFinished(NewCloudInitReader(WithCloudInitCommand("echo", "status: running")))
+		Expect(running.Check(context.Background())).To(BeFalse())
+		Expect(running.String()).To(Equal("cloud-init (Running)"))
+
+		done := CloudInitFinished(NewCloudInitReader(WithCloudInitCommand("echo", "status: done")))
+		Expect(done.Check(context.Background())).To(BeTrue())
+	})
+})
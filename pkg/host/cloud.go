@@ -0,0 +1,343 @@
+package host
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+)
+
+// DefaultMetadataEndpoint is the link-local address of the instance metadata
+// service used by all supported cloud providers.
+const DefaultMetadataEndpoint = "http://169.254.169.254"
+
+// A MetadataProvider fetches instance metadata from a single cloud provider's
+// metadata service. Fetch should return an error if the service does not
+// respond like the provider's service would.
+type MetadataProvider interface {
+	Name() string
+	Fetch(ctx context.Context, c *MetadataClient) (*api.CloudMetadata, error)
+}
+
+// MetadataClient makes requests to a metadata service.
+type MetadataClient struct {
+	Endpoint string
+	Client   *http.Client
+}
+
+// Get requests the given path from the metadata service, returning an error
+// if the response status is not 200.
+func (c *MetadataClient) Get(ctx context.Context, path string, header http.Header) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, path, header)
+}
+
+func (c *MetadataClient) do(ctx context.Context, method, path string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.Endpoint, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return body, nil
+}
+
+type MetadataCollectorOptions struct {
+	endpoint  string
+	timeout   time.Duration
+	providers []MetadataProvider
+}
+
+type MetadataCollectorOption func(*MetadataCollectorOptions)
+
+func (o *MetadataCollectorOptions) Apply(opts ...MetadataCollectorOption) {
+	for _, op := range opts {
+		op(o)
+	}
+}
+
+// WithMetadataEndpoint overrides the address of the metadata service, for
+// example to use a fake server in tests.
+func WithMetadataEndpoint(endpoint string) MetadataCollectorOption {
+	return func(o *MetadataCollectorOptions) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithMetadataTimeout sets how long to wait for the metadata service before
+// concluding that the host is not running on a supported cloud provider.
+func WithMetadataTimeout(d time.Duration) MetadataCollectorOption {
+	return func(o *MetadataCollectorOptions) {
+		o.timeout = d
+	}
+}
+
+// WithMetadataProviders replaces the default set of providers. Providers
+// earlier in the list take precedence if more than one responds.
+func WithMetadataProviders(providers ...MetadataProvider) MetadataCollectorOption {
+	return func(o *MetadataCollectorOptions) {
+		o.providers = providers
+	}
+}
+
+// DefaultMetadataProviders returns providers for all supported clouds.
+func DefaultMetadataProviders() []MetadataProvider {
+	return []MetadataProvider{
+		AWSMetadataProvider{},
+		GCEMetadataProvider{},
+		AzureMetadataProvider{},
+		OpenStackMetadataProvider{},
+	}
+}
+
+type MetadataCollector struct {
+	options MetadataCollectorOptions
+}
+
+func NewMetadataCollector(opts ...MetadataCollectorOption) *MetadataCollector {
+	options := MetadataCollectorOptions{
+		endpoint:  DefaultMetadataEndpoint,
+		timeout:   2 * time.Second,
+		providers: DefaultMetadataProviders(),
+	}
+	options.Apply(opts...)
+	return &MetadataCollector{
+		options: options,
+	}
+}
+
+// ErrNoCloudMetadata is returned by Collect if no provider recognized the
+// metadata service, including when there is no metadata service at all.
+var ErrNoCloudMetadata = errors.New("no cloud metadata service found")
+
+// Collect probes all providers concurrently and returns the metadata from the
+// first provider, in order of precedence, which responded successfully.
+func (c *MetadataCollector) Collect(ctx context.Context) (*api.CloudMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.options.timeout)
+	defer cancel()
+	client := &MetadataClient{
+		Endpoint: c.options.endpoint,
+		Client: &http.Client{
+			// The metadata service must be reached directly
+			Transport: &http.Transport{Proxy: nil},
+		},
+	}
+	type result struct {
+		md  *api.CloudMetadata
+		err error
+	}
+	results := make([]chan result, len(c.options.providers))
+	for i, p := range c.options.providers {
+		results[i] = make(chan result, 1)
+		go func(p MetadataProvider, ch chan<- result) {
+			md, err := p.Fetch(ctx, client)
+			if err == nil {
+				md.Provider = p.Name()
+			}
+			ch <- result{md, err}
+		}(p, results[i])
+	}
+	for _, ch := range results {
+		if r := <-ch; r.err == nil {
+			return r.md, nil
+		}
+	}
+	return nil, ErrNoCloudMetadata
+}
+
+// AWSMetadataProvider reads metadata from the EC2 instance metadata service,
+// using IMDSv2 session tokens. Tags are only available if the instance allows
+// access to tags in instance metadata.
+type AWSMetadataProvider struct{}
+
+func (AWSMetadataProvider) Name() string {
+	return "aws"
+}
+
+func (AWSMetadataProvider) Fetch(ctx context.Context, c *MetadataClient) (*api.CloudMetadata, error) {
+	token, err := c.do(ctx, http.MethodPut, "/latest/api/token", http.Header{
+		"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": {"60"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{
+		"X-Aws-Ec2-Metadata-Token": {string(token)},
+	}
+	data, err := c.Get(ctx, "/latest/dynamic/instance-identity/document", header)
+	if err != nil {
+		return nil, err
+	}
+	doc := struct {
+		InstanceID       string `json:"instanceId"`
+		InstanceType     string `json:"instanceType"`
+		Region           string `json:"region"`
+		AvailabilityZone string `json:"availabilityZone"`
+	}{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	md := &api.CloudMetadata{
+		InstanceID:   doc.InstanceID,
+		InstanceType: doc.InstanceType,
+		Region:       doc.Region,
+		Zone:         doc.AvailabilityZone,
+		Tags:         map[string]string{},
+	}
+	if keys, err := c.Get(ctx, "/latest/meta-data/tags/instance", header); err == nil {
+		for _, key := range strings.Fields(string(keys)) {
+			value, err := c.Get(ctx, "/latest/meta-data/tags/instance/"+key, header)
+			if err != nil {
+				return nil, err
+			}
+			md.Tags[key] = string(value)
+		}
+	}
+	return md, nil
+}
+
+// GCEMetadataProvider reads metadata from the Google Compute Engine metadata
+// server. Network tags are reported as tags with empty values.
+type GCEMetadataProvider struct{}
+
+func (GCEMetadataProvider) Name() string {
+	return "gce"
+}
+
+func (GCEMetadataProvider) Fetch(ctx context.Context, c *MetadataClient) (*api.CloudMetadata, error) {
+	data, err := c.Get(ctx, "/computeMetadata/v1/instance/?recursive=true", http.Header{
+		"Metadata-Flavor": {"Google"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	instance := struct {
+		ID          json.Number `json:"id"`
+		MachineType string      `json:"machineType"`
+		Zone        string      `json:"zone"`
+		Tags        []string    `json:"tags"`
+	}{}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return nil, err
+	}
+	// Zone and machine type are resource paths, e.g.
+	// projects/123/zones/us-central1-a
+	zone := path.Base(instance.Zone)
+	md := &api.CloudMetadata{
+		InstanceID:   instance.ID.String(),
+		InstanceType: path.Base(instance.MachineType),
+		Zone:         zone,
+		Tags:         map[string]string{},
+	}
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		md.Region = zone[:i]
+	}
+	for _, tag := range instance.Tags {
+		md.Tags[tag] = ""
+	}
+	return md, nil
+}
+
+// AzureMetadataProvider reads metadata from the Azure Instance Metadata
+// Service.
+type AzureMetadataProvider struct{}
+
+func (AzureMetadataProvider) Name() string {
+	return "azure"
+}
+
+func (AzureMetadataProvider) Fetch(ctx context.Context, c *MetadataClient) (*api.CloudMetadata, error) {
+	data, err := c.Get(ctx, "/metadata/instance?api-version=2021-02-01", http.Header{
+		"Metadata": {"true"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	instance := struct {
+		Compute struct {
+			VMID     string `json:"vmId"`
+			VMSize   string `json:"vmSize"`
+			Location string `json:"location"`
+			Zone     string `json:"zone"`
+			TagsList []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"tagsList"`
+		} `json:"compute"`
+	}{}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return nil, err
+	}
+	if instance.Compute.VMID == "" {
+		return nil, errors.New("missing vmId in instance metadata")
+	}
+	md := &api.CloudMetadata{
+		InstanceID:   instance.Compute.VMID,
+		InstanceType: instance.Compute.VMSize,
+		Region:       instance.Compute.Location,
+		Zone:         instance.Compute.Zone,
+		Tags:         map[string]string{},
+	}
+	for _, tag := range instance.Compute.TagsList {
+		md.Tags[tag.Name] = tag.Value
+	}
+	return md, nil
+}
+
+// OpenStackMetadataProvider reads metadata from the OpenStack metadata
+// service. The instance type is read from the EC2-compatible API, if
+// available, and the region is not reported.
+type OpenStackMetadataProvider struct{}
+
+func (OpenStackMetadataProvider) Name() string {
+	return "openstack"
+}
+
+func (OpenStackMetadataProvider) Fetch(ctx context.Context, c *MetadataClient) (*api.CloudMetadata, error) {
+	data, err := c.Get(ctx, "/openstack/latest/meta_data.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	metadata := struct {
+		UUID             string            `json:"uuid"`
+		AvailabilityZone string            `json:"availability_zone"`
+		Meta             map[string]string `json:"meta"`
+	}{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+	if metadata.UUID == "" {
+		return nil, errors.New("missing uuid in instance metadata")
+	}
+	md := &api.CloudMetadata{
+		InstanceID: metadata.UUID,
+		Zone:       metadata.AvailabilityZone,
+		Tags:       metadata.Meta,
+	}
+	if md.Tags == nil {
+		md.Tags = map[string]string{}
+	}
+	if instanceType, err := c.Get(ctx, "/latest/meta-data/instance-type", nil); err == nil {
+		md.InstanceType = string(instanceType)
+	}
+	return md, nil
+}
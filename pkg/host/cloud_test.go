@@ -0,0 +1,111 @@
+package host
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/kralicky/post-init/pkg/host/metadatatest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+var _ = Describe("Cloud Metadata", func() {
+	DescribeTable("collecting metadata from each provider",
+		func(provider string, served *api.CloudMetadata, expected *api.CloudMetadata) {
+			srv := metadatatest.NewServer(provider, served)
+			defer srv.Close()
+			md, err := NewMetadataCollector(WithMetadataEndpoint(srv.URL)).
+				Collect(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmp.Diff(expected, md, protocmp.Transform())).To(BeEmpty())
+		},
+		Entry("aws", "aws",
+			&api.CloudMetadata{
+				InstanceID:   "i-0123456789abcdef0",
+				InstanceType: "t3.micro",
+				Region:       "us-east-1",
+				Zone:         "us-east-1a",
+				Tags:         map[string]string{"Name": "web-1", "env": "staging"},
+			},
+			&api.CloudMetadata{
+				Provider:     "aws",
+				InstanceID:   "i-0123456789abcdef0",
+				InstanceType: "t3.micro",
+				Region:       "us-east-1",
+				Zone:         "us-east-1a",
+				Tags:         map[string]string{"Name": "web-1", "env": "staging"},
+			},
+		),
+		Entry("gce", "gce",
+			&api.CloudMetadata{
+				InstanceID:   "4520623710582935341",
+				InstanceType: "e2-medium",
+				Zone:         "us-central1-a",
+				Tags:         map[string]string{"http-server": ""},
+			},
+			&api.CloudMetadata{
+				Provider:     "gce",
+				InstanceID:   "4520623710582935341",
+				InstanceType: "e2-medium",
+				Region:       "us-central1",
+				Zone:         "us-central1-a",
+				Tags:         map[string]string{"http-server": ""},
+			},
+		),
+		Entry("azure", "azure",
+			&api.CloudMetadata{
+				InstanceID:   "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+				InstanceType: "Standard_B1s",
+				Region:       "westus2",
+				Zone:         "1",
+				Tags:         map[string]string{"env": "prod"},
+			},
+			&api.CloudMetadata{
+				Provider:     "azure",
+				InstanceID:   "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+				InstanceType: "Standard_B1s",
+				Region:       "westus2",
+				Zone:         "1",
+				Tags:         map[string]string{"env": "prod"},
+			},
+		),
+		Entry("openstack", "openstack",
+			&api.CloudMetadata{
+				InstanceID:   "d8e02d56-2648-49a3-bf97-6be8f1204f38",
+				InstanceType: "m1.small",
+				Region:       "ignored",
+				Zone:         "nova",
+				Tags:         map[string]string{"role": "db"},
+			},
+			&api.CloudMetadata{
+				Provider:     "openstack",
+				InstanceID:   "d8e02d56-2648-49a3-bf97-6be8f1204f38",
+				InstanceType: "m1.small",
+				Zone:         "nova",
+				Tags:         map[string]string{"role": "db"},
+			},
+		),
+	)
+
+	It("should report when no metadata service is found", func() {
+		srv := metadatatest.NewServer("none", nil)
+		defer srv.Close()
+		_, err := NewMetadataCollector(WithMetadataEndpoint(srv.URL)).
+			Collect(context.Background())
+		Expect(err).To(MatchError(ErrNoCloudMetadata))
+	})
+
+	It("should give up when the metadata service is unreachable", func() {
+		// Reserved for documentation (RFC 5737), so nothing should answer
+		start := time.Now()
+		_, err := NewMetadataCollector(
+			WithMetadataEndpoint("http://192.0.2.1"),
+			WithMetadataTimeout(100*time.Millisecond),
+		).Collect(context.Background())
+		Expect(err).To(MatchError(ErrNoCloudMetadata))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+})
@@ -0,0 +1,225 @@
+package host
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+)
+
+const (
+	DefaultCloudInitRunDir    = "/run/cloud-init"
+	DefaultCloudInitOutputLog = "/var/log/cloud-init-output.log"
+)
+
+type CloudInitReaderOptions struct {
+	command   []string
+	runDir    string
+	outputLog string
+	logLines  int
+	timeout   time.Duration
+}
+
+type CloudInitReaderOption func(*CloudInitReaderOptions)
+
+func (o *CloudInitReaderOptions) Apply(opts ...CloudInitReaderOption) {
+	for _, op := range opts {
+		op(o)
+	}
+}
+
+// WithCloudInitCommand overrides the command used to query the status of
+// cloud-init. The command should print the status in the same format as
+// "cloud-init status".
+func WithCloudInitCommand(command ...string) CloudInitReaderOption {
+	return func(o *CloudInitReaderOptions) {
+		o.command = command
+	}
+}
+
+// WithCloudInitRunDir overrides the directory containing result.json.
+func WithCloudInitRunDir(dir string) CloudInitReaderOption {
+	return func(o *CloudInitReaderOptions) {
+		o.runDir = dir
+	}
+}
+
+// WithCloudInitOutputLog overrides the path to the cloud-init output log.
+func WithCloudInitOutputLog(path string) CloudInitReaderOption {
+	return func(o *CloudInitReaderOptions) {
+		o.outputLog = path
+	}
+}
+
+// WithCloudInitLogLines sets the number of lines from the end of the output
+// log to include in the status if cloud-init failed.
+func WithCloudInitLogLines(n int) CloudInitReaderOption {
+	return func(o *CloudInitReaderOptions) {
+		o.logLines = n
+	}
+}
+
+// CloudInitReader reads the status of cloud-init on the host.
+type CloudInitReader struct {
+	options CloudInitReaderOptions
+}
+
+func NewCloudInitReader(opts ...CloudInitReaderOption) *CloudInitReader {
+	options := CloudInitReaderOptions{
+		command:   []string{"cloud-init", "status"},
+		runDir:    DefaultCloudInitRunDir,
+		outputLog: DefaultCloudInitOutputLog,
+		logLines:  20,
+		timeout:   10 * time.Second,
+	}
+	options.Apply(opts...)
+	return &CloudInitReader{
+		options: options,
+	}
+}
+
+// Status returns the current status of cloud-init. The state is obtained from
+// the status command if it is available, otherwise it is inferred from the
+// contents of the run directory. Errors are read from result.json, which
+// cloud-init writes once it has finished.
+func (r *CloudInitReader) Status(ctx context.Context) *api.CloudInitStatus {
+	status := &api.CloudInitStatus{}
+	errs, resultErr := r.readResult()
+	if resultErr == nil {
+		status.Errors = errs
+	}
+	state, err := r.queryState(ctx)
+	if err != nil {
+		state = r.inferState(resultErr, len(errs) > 0)
+	}
+	status.State = state
+	if state == api.CloudInitState_Error {
+		status.LogTail, _ = tailLines(r.options.outputLog, r.options.logLines)
+	}
+	return status
+}
+
+func (r *CloudInitReader) queryState(ctx context.Context) (api.CloudInitState, error) {
+	if len(r.options.command) == 0 {
+		return api.CloudInitState_Unknown, errors.New("no status command")
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.options.timeout)
+	defer cancel()
+	// cloud-init status exits with a non-zero code if cloud-init failed, so
+	// only the output is checked.
+	out, err := exec.CommandContext(ctx, r.options.command[0], r.options.command[1:]...).Output()
+	if err != nil && len(out) == 0 {
+		return api.CloudInitState_Unknown, err
+	}
+	return parseCloudInitStatus(out)
+}
+
+// inferState determines the state of cloud-init without the status command.
+func (r *CloudInitReader) inferState(resultErr error, failed bool) api.CloudInitState {
+	switch {
+	case resultErr == nil && failed:
+		return api.CloudInitState_Error
+	case resultErr == nil:
+		return api.CloudInitState_Done
+	}
+	if _, err := os.Stat(filepath.Join(r.options.runDir, "disabled")); err == nil {
+		return api.CloudInitState_Disabled
+	}
+	if _, err := os.Stat(r.options.runDir); err == nil {
+		return api.CloudInitState_Running
+	}
+	if _, err := exec.LookPath("cloud-init"); err == nil {
+		return api.CloudInitState_NotRun
+	}
+	return api.CloudInitState_NotInstalled
+}
+
+func (r *CloudInitReader) readResult() ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(r.options.runDir, "result.json"))
+	if err != nil {
+		return nil, err
+	}
+	result := struct {
+		V1 struct {
+			Errors []string `json:"errors"`
+		} `json:"v1"`
+	}{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result.V1.Errors, nil
+}
+
+// parseCloudInitStatus parses the output of "cloud-init status", which
+// contains a line such as "status: done".
+func parseCloudInitStatus(out []byte) (api.CloudInitState, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		kv := strings.SplitN(scanner.Text(), ":", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) != "status" {
+			continue
+		}
+		switch strings.TrimSpace(kv[1]) {
+		case "not run", "not started":
+			return api.CloudInitState_NotRun, nil
+		case "running":
+			return api.CloudInitState_Running, nil
+		case "done", "degraded done":
+			return api.CloudInitState_Done, nil
+		case "error", "degraded error":
+			return api.CloudInitState_Error, nil
+		case "disabled":
+			return api.CloudInitState_Disabled, nil
+		}
+		return api.CloudInitState_Unknown, errors.New("unrecognized cloud-init status " + kv[1])
+	}
+	return api.CloudInitState_Unknown, errors.New("no status in cloud-init output")
+}
+
+// tailLines returns up to n lines from the end of the file at path. At most
+// the last 64KiB of the file are read.
+func tailLines(path string, n int) ([]string, error) {
+	const maxRead = 64 * 1024
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - maxRead
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimRight(string(data), "\n")
+	if text == "" {
+		return nil, nil
+	}
+	lines := strings.Split(text, "\n")
+	if offset > 0 && len(lines) > 1 {
+		// The first line is likely incomplete
+		lines = lines[1:]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
@@ -0,0 +1,78 @@
+package host
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kralicky/post-init/pkg/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cloud-init Status", func() {
+	var runDir, outputLog string
+	BeforeEach(func() {
+		tmp := GinkgoT().TempDir()
+		runDir = filepath.Join(tmp, "run")
+		outputLog = filepath.Join(tmp, "cloud-init-output.log")
+		Expect(os.Mkdir(runDir, 0o755)).To(Succeed())
+		lines := []string{}
+		for i := 1; i <= 30; i++ {
+			lines = append(lines, fmt.Sprintf("line %d", i))
+		}
+		Expect(os.WriteFile(outputLog, []byte(strings.Join(lines, "\n")+"\n"), 0o644)).To(Succeed())
+	})
+	writeResult := func(errs string) {
+		Expect(os.WriteFile(filepath.Join(runDir, "result.json"),
+			[]byte(`{"v1": {"datasource": "DataSourceNoCloud", "errors": [`+errs+`]}}`), 0o644)).To(Succeed())
+	}
+	reader := func(command ...string) *CloudInitReader {
+		return NewCloudInitReader(
+			WithCloudInitCommand(command...),
+			WithCloudInitRunDir(runDir),
+			WithCloudInitOutputLog(outputLog),
+			WithCloudInitLogLines(5),
+		)
+	}
+
+	It("should report errors and the log tail if cloud-init failed", func() {
+		writeResult(`"module failed"`)
+		status := reader("sh", "-c", "echo 'status: error'; exit 1").Status(context.Background())
+		Expect(status.State).To(Equal(api.CloudInitState_Error))
+		Expect(status.Errors).To(Equal([]string{"module failed"}))
+		Expect(status.LogTail).To(Equal([]string{"line 26", "line 27", "line 28", "line 29", "line 30"}))
+	})
+	It("should not include the log tail if cloud-init succeeded", func() {
+		writeResult("")
+		status := reader("echo", "status: done").Status(context.Background())
+		Expect(status.State).To(Equal(api.CloudInitState_Done))
+		Expect(status.Errors).To(BeEmpty())
+		Expect(status.LogTail).To(BeEmpty())
+		Expect(status.Finished()).To(BeTrue())
+	})
+	It("should report cloud-init as running", func() {
+		status := reader("echo", "status: running").Status(context.Background())
+		Expect(status.State).To(Equal(api.CloudInitState_Running))
+		Expect(status.Finished()).To(BeFalse())
+	})
+	When("the status command is not available", func() {
+		It("should infer the state from result.json", func() {
+			writeResult(`"module failed"`)
+			status := reader("/nonexistent/cloud-init").Status(context.Background())
+			Expect(status.State).To(Equal(api.CloudInitState_Error))
+			Expect(status.LogTail).To(HaveLen(5))
+		})
+		It("should report cloud-init as running if it has not written a result", func() {
+			status := reader("/nonexistent/cloud-init").Status(context.Background())
+			Expect(status.State).To(Equal(api.CloudInitState_Running))
+		})
+		It("should report cloud-init as disabled", func() {
+			Expect(os.WriteFile(filepath.Join(runDir, "disabled"), nil, 0o644)).To(Succeed())
+			status := reader("/nonexistent/cloud-init").Status(context.Background())
+			Expect(status.State).To(Equal(api.CloudInitState_Disabled))
+		})
+	})
+})
@@ -96,7 +96,7 @@ func authorizedKeyFiles() []authorizedKeyFile {
 			if _, err := os.Stat(keyFilePath); err == nil {
 				keyFiles = append(keyFiles, authorizedKeyFile{
 					Path: keyFilePath,
-					User: user.Name,
+					User: user.Username,
 				})
 			}
 		}
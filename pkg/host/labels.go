@@ -0,0 +1,75 @@
+package host
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LabelsEnvVar is the environment variable from which the agent reads
+// additional labels, as a comma-separated list of key=value pairs.
+const LabelsEnvVar = "POST_INIT_LABELS"
+
+// ParseLabels parses a list of key=value pairs into a map. Later pairs
+// override earlier ones with the same key.
+func ParseLabels(pairs []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, pair := range pairs {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid label %q: expected key=value", pair)
+		}
+		key := strings.TrimSpace(kv[0])
+		if key == "" {
+			return nil, fmt.Errorf("invalid label %q: empty key", pair)
+		}
+		labels[key] = strings.TrimSpace(kv[1])
+	}
+	return labels, nil
+}
+
+// GetLabelsFromFile reads labels from a file containing one key=value pair
+// per line. Blank lines and lines starting with # are ignored. A missing file
+// is not an error.
+func GetLabelsFromFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	lines := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	labels, err := ParseLabels(lines)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return labels, nil
+}
+
+// GetLabelsFromEnv reads labels from the POST_INIT_LABELS environment variable.
+func GetLabelsFromEnv() (map[string]string, error) {
+	labels, err := ParseLabels(strings.Split(os.Getenv(LabelsEnvVar), ","))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", LabelsEnvVar, err)
+	}
+	return labels, nil
+}
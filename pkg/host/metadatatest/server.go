@@ -0,0 +1,139 @@
+// Package metadatatest provides fake cloud instance metadata services for
+// testing host.MetadataCollector and code which depends on it.
+package metadatatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+
+	"github.com/kralicky/post-init/pkg/api"
+)
+
+const awsToken = "fake-token"
+
+// NewServer starts a server which imitates the metadata service of the given
+// provider (aws, gce, azure or openstack), serving the given metadata. Any
+// other provider name starts a server which responds 404 to all requests. The
+// caller must close the server.
+func NewServer(provider string, md *api.CloudMetadata) *httptest.Server {
+	mux := http.NewServeMux()
+	switch provider {
+	case "aws":
+		serveAWS(mux, md)
+	case "gce":
+		serveGCE(mux, md)
+	case "azure":
+		serveAzure(mux, md)
+	case "openstack":
+		serveOpenStack(mux, md)
+	}
+	return httptest.NewServer(mux)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func serveAWS(mux *http.ServeMux, md *api.CloudMetadata) {
+	mux.HandleFunc("/latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("X-Aws-Ec2-Metadata-Token-Ttl-Seconds") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, awsToken)
+	})
+	authorized := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Aws-Ec2-Metadata-Token") != awsToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			h(w, r)
+		}
+	}
+	mux.HandleFunc("/latest/dynamic/instance-identity/document", authorized(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"instanceId":       md.InstanceID,
+			"instanceType":     md.InstanceType,
+			"region":           md.Region,
+			"availabilityZone": md.Zone,
+		})
+	}))
+	mux.HandleFunc("/latest/meta-data/tags/instance", authorized(func(w http.ResponseWriter, r *http.Request) {
+		keys := make([]string, 0, len(md.Tags))
+		for k := range md.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Fprint(w, strings.Join(keys, "\n"))
+	}))
+	mux.HandleFunc("/latest/meta-data/tags/instance/", authorized(func(w http.ResponseWriter, r *http.Request) {
+		value, ok := md.Tags[strings.TrimPrefix(r.URL.Path, "/latest/meta-data/tags/instance/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, value)
+	}))
+}
+
+func serveGCE(mux *http.ServeMux, md *api.CloudMetadata) {
+	mux.HandleFunc("/computeMetadata/v1/instance/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" || r.URL.Query().Get("recursive") != "true" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		tags := []string{}
+		for k := range md.Tags {
+			tags = append(tags, k)
+		}
+		sort.Strings(tags)
+		w.Header().Set("Metadata-Flavor", "Google")
+		writeJSON(w, map[string]interface{}{
+			"id":          json.Number(md.InstanceID),
+			"machineType": "projects/123/machineTypes/" + md.InstanceType,
+			"zone":        "projects/123/zones/" + md.Zone,
+			"tags":        tags,
+		})
+	})
+}
+
+func serveAzure(mux *http.ServeMux, md *api.CloudMetadata) {
+	mux.HandleFunc("/metadata/instance", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" || r.URL.Query().Get("api-version") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		tags := []map[string]string{}
+		for k, v := range md.Tags {
+			tags = append(tags, map[string]string{"name": k, "value": v})
+		}
+		writeJSON(w, map[string]interface{}{
+			"compute": map[string]interface{}{
+				"vmId":     md.InstanceID,
+				"vmSize":   md.InstanceType,
+				"location": md.Region,
+				"zone":     md.Zone,
+				"tagsList": tags,
+			},
+		})
+	})
+}
+
+func serveOpenStack(mux *http.ServeMux, md *api.CloudMetadata) {
+	mux.HandleFunc("/openstack/latest/meta_data.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"uuid":              md.InstanceID,
+			"availability_zone": md.Zone,
+			"meta":              md.Tags,
+		})
+	})
+	mux.HandleFunc("/latest/meta-data/instance-type", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, md.InstanceType)
+	})
+}
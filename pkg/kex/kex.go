@@ -19,6 +19,10 @@ import (
 // is sent to the server for verification. If the server verifies the signature,
 // the client proves ownership of the private key corresponding to the public
 // key it sent in the initial exchange.
+//
+// The client's key can also be an OpenSSH certificate, in which case the
+// certificate is included in the signed data, and the signature is made with
+// the certified key. Verify does not check the certificate itself.
 
 var ErrInvalidKeyFormat = errors.New("invalid key format")
 
@@ -98,5 +102,9 @@ func Verify(
 		return err
 	}
 
-	return clientPublicKey.Verify(buf.Bytes(), &signature)
+	verifyingKey := clientPublicKey
+	if cert, ok := clientPublicKey.(*ssh.Certificate); ok {
+		verifyingKey = cert.Key
+	}
+	return verifyingKey.Verify(buf.Bytes(), &signature)
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/kralicky/post-init/pkg/agent"
+	"github.com/kralicky/post-init/pkg/host"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -14,16 +15,53 @@ func BuildAgentCmd() *cobra.Command {
 	var relayCert string
 	var insecure bool
 	var timeout int
+	var maxReconnectDelay int
+	var labels []string
+	var labelsFile string
+	var cloudMetadata bool
+	var cloudInit bool
+	var waitForCloudInit bool
+	var waitForTarget string
+	var waitForFile string
+	var waitTimeout int
 
 	cmd := &cobra.Command{
 		Use:   "agent",
 		Short: "Run the agent and connect to a relay",
 		Run: func(cmd *cobra.Command, args []string) {
+			labelMap, err := host.ParseLabels(labels)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			var collector *host.MetadataCollector
+			if cloudMetadata {
+				collector = host.NewMetadataCollector()
+			}
+			var cloudInitReader *host.CloudInitReader
+			if cloudInit {
+				cloudInitReader = host.NewCloudInitReader()
+			}
+			var conditions []host.BootCondition
+			if waitForCloudInit {
+				conditions = append(conditions, host.CloudInitFinished(host.NewCloudInitReader()))
+			}
+			if waitForTarget != "" {
+				conditions = append(conditions, host.BootTargetActive(waitForTarget))
+			}
+			if waitForFile != "" {
+				conditions = append(conditions, host.FileExists(waitForFile))
+			}
 			d := agent.New(
 				agent.WithInsecure(insecure),
 				agent.WithRelayAddress(relayAddress),
 				agent.WithRelayCACert(relayCert),
 				agent.WithTimeout(time.Duration(timeout)*time.Second),
+				agent.WithReconnectBackoff(time.Second, time.Duration(maxReconnectDelay)*time.Second),
+				agent.WithLabels(labelMap),
+				agent.WithLabelsFile(labelsFile),
+				agent.WithMetadataCollector(collector),
+				agent.WithCloudInitReader(cloudInitReader),
+				agent.WithBootWait(time.Duration(waitTimeout)*time.Second, conditions...),
 			)
 			if err := d.Start(context.Background()); err != nil {
 				logrus.Error(err)
@@ -34,5 +72,14 @@ func BuildAgentCmd() *cobra.Command {
 	cmd.Flags().StringVar(&relayCert, "cacert", "", "(optional) path to a self-signed certificate for the relay")
 	cmd.Flags().BoolVar(&insecure, "insecure", false, "Run the agent in insecure mode (for testing only)")
 	cmd.Flags().IntVar(&timeout, "timeout", 60, "duration in seconds to wait for instructions from the relay before exiting")
+	cmd.Flags().IntVar(&maxReconnectDelay, "max-reconnect-delay", 30, "maximum duration in seconds to wait between attempts to reconnect to the relay")
+	cmd.Flags().StringArrayVarP(&labels, "label", "l", nil, "label to include in the announcement, in the form key=value (can be repeated)")
+	cmd.Flags().StringVar(&labelsFile, "labels-file", "/etc/post-init/labels", "file containing labels to include in the announcement, one key=value pair per line. Labels are also read from $"+host.LabelsEnvVar+" as a comma-separated list")
+	cmd.Flags().BoolVar(&cloudMetadata, "cloud-metadata", false, "include instance metadata from the cloud provider's metadata service in the announcement")
+	cmd.Flags().BoolVar(&cloudInit, "cloud-init-status", true, "report the status of cloud-init in the announcement, and to the relay when it changes")
+	cmd.Flags().BoolVar(&waitForCloudInit, "wait-for-cloud-init", false, "wait for cloud-init to finish before announcing")
+	cmd.Flags().StringVar(&waitForTarget, "wait-for-target", "", "wait for the given systemd unit (e.g. multi-user.target) to become active before announcing")
+	cmd.Flags().StringVar(&waitForFile, "wait-for-file", "", "wait for the given file to exist before announcing")
+	cmd.Flags().IntVar(&waitTimeout, "wait-timeout", 900, "maximum duration in seconds to wait before announcing anyway, or 0 to wait indefinitely. If more than one wait condition is given, the agent announces once any of them is met")
 	return cmd
 }
@@ -0,0 +1,274 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func BuildClientCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "client",
+		Short: "Interact with agents connected to a relay",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(BuildClientWatchCmd())
+	cmd.AddCommand(BuildClientRunCmd())
+	cmd.AddCommand(BuildClientScriptCmd())
+	cmd.AddCommand(BuildClientLsCmd())
+	cmd.AddCommand(BuildClientBroadcastCmd())
+	return cmd
+}
+
+const (
+	outputHuman = "human"
+	outputJSON  = "json"
+)
+
+// printer writes announcements and results in the selected output format. In
+// JSON format, each message is written on its own line.
+type printer struct {
+	mu     sync.Mutex
+	out    io.Writer
+	format string
+}
+
+func addOutputFlag(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVarP(format, "output", "o", outputHuman, "Output format (human or json)")
+}
+
+func newPrinter(out io.Writer, format string) (*printer, error) {
+	switch format {
+	case outputHuman, outputJSON:
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+	return &printer{
+		out:    out,
+		format: format,
+	}, nil
+}
+
+func (p *printer) JSON() bool {
+	return p.format == outputJSON
+}
+
+func (p *printer) Message(msg proto.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.out, string(data))
+	return err
+}
+
+var announcementHeader = []string{"FINGERPRINT", "HOSTNAME", "ADDRESSES", "LABELS", "CLOUD-INIT"}
+
+var eventHeader = append([]string{"EVENT"}, announcementHeader...)
+
+// EventHeader prints the column headers for watch events. Nothing is printed
+// in JSON format.
+func (p *printer) EventHeader() error {
+	return p.header(eventHeader)
+}
+
+// header prints a line of column headers, except in JSON format.
+func (p *printer) header(columns []string) error {
+	if p.JSON() {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := fmt.Fprintln(p.out, strings.Join(columns, "  "))
+	return err
+}
+
+// Event prints a single watch event.
+func (p *printer) Event(ev *api.WatchEvent) error {
+	if p.JSON() {
+		return p.Message(ev)
+	}
+	event := strings.ToLower(ev.Type.String())
+	if ev.Reason != "" {
+		event += " (" + ev.Reason + ")"
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	columns := append([]string{event}, announcementColumns(ev.Fingerprint, ev.Announcement)...)
+	_, err := fmt.Fprintln(p.out, strings.Join(columns, "  "))
+	return err
+}
+
+// AnnouncementRecords prints a table of announcement records, including when
+// each agent connected and disconnected.
+func (p *printer) AnnouncementRecords(records []*api.AnnouncementRecord) error {
+	if p.JSON() {
+		return p.Message(&api.AnnouncementHistory{Items: records})
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	w := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(append(announcementHeader, "CONNECTED", "DISCONNECTED"), "\t"))
+	for _, r := range records {
+		disconnected := "-"
+		if r.DisconnectTime != nil {
+			disconnected = r.DisconnectTime.AsTime().Local().Format(time.RFC3339)
+		}
+		columns := append(announcementColumns(r.Fingerprint, r.Announcement),
+			r.ConnectTime.AsTime().Local().Format(time.RFC3339), disconnected)
+		fmt.Fprintln(w, strings.Join(columns, "\t"))
+	}
+	return w.Flush()
+}
+
+// Agents prints a table of connected agents.
+func (p *printer) Agents(agents []*api.AgentInfo) error {
+	if p.JSON() {
+		return p.Message(&api.AgentList{Items: agents})
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	w := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(append(announcementHeader, "CONNECTED", "LAST ACTIVITY"), "\t"))
+	for _, a := range agents {
+		columns := append(announcementColumns(a.Fingerprint, a.Announcement),
+			a.ConnectTime.AsTime().Local().Format(time.RFC3339),
+			a.LastActivity.AsTime().Local().Format(time.RFC3339))
+		fmt.Fprintln(w, strings.Join(columns, "\t"))
+	}
+	return w.Flush()
+}
+
+var broadcastHeader = []string{"FINGERPRINT", "HOSTNAME", "EXIT", "DURATION", "OUTPUT"}
+
+// BroadcastHeader prints the column headers for broadcast results. Nothing is
+// printed in JSON format.
+func (p *printer) BroadcastHeader() error {
+	return p.header(broadcastHeader)
+}
+
+// BroadcastResult prints the result of a broadcast on a single agent. In
+// human format, only the last line of output is shown.
+func (p *printer) BroadcastResult(r *api.BroadcastResult) error {
+	if p.JSON() {
+		return p.Message(r)
+	}
+	exit := "-"
+	var output string
+	switch {
+	case r.Result.GetError() != "":
+		exit = "error"
+		output = r.Result.GetError()
+	case r.Result.GetCommand().GetTerminated():
+		exit = "terminated"
+		output = r.Result.GetCommand().TerminationReason
+	case r.Result.GetScript().GetTerminated():
+		exit = "terminated"
+		output = r.Result.GetScript().TerminationReason
+	case r.Result.GetCommand() != nil:
+		exit = fmt.Sprint(r.Result.GetCommand().ExitCode)
+		output = lastLine(r.Result.GetCommand().Stdout, r.Result.GetCommand().Stderr)
+	case r.Result.GetScript() != nil:
+		exit = fmt.Sprint(r.Result.GetScript().ExitCode)
+		output = lastLine(r.Result.GetScript().Stdout, r.Result.GetScript().Stderr)
+	}
+	duration := r.EndTime.AsTime().Sub(r.StartTime.AsTime()).Round(time.Millisecond)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	columns := []string{
+		r.AgentFingerprint,
+		orDash(r.Announcement.GetUname().GetHostname()),
+		exit,
+		duration.String(),
+		orDash(output),
+	}
+	_, err := fmt.Fprintln(p.out, strings.Join(columns, "  "))
+	return err
+}
+
+// BroadcastSummary prints the number of agents a broadcast succeeded and
+// failed on. If the broadcast was rolled out in more than one batch, each
+// batch is also summarized.
+func (p *printer) BroadcastSummary(s *api.BroadcastSummary) error {
+	if p.JSON() {
+		return p.Message(s)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.out)
+	if len(s.Batches) > 1 || s.Aborted {
+		for i, b := range s.Batches {
+			duration := b.EndTime.AsTime().Sub(b.StartTime.AsTime()).Round(time.Millisecond)
+			fmt.Fprintf(p.out, "batch %d: %d agents, %d succeeded, %d failed (%s)\n",
+				i+1, b.Size, b.Succeeded, b.Failed, duration)
+		}
+		if s.Aborted {
+			fmt.Fprintf(p.out, "rollout aborted after %d batches, %d agents skipped\n", len(s.Batches), s.Skipped)
+		}
+	}
+	_, err := fmt.Fprintf(p.out, "%d agents: %d succeeded, %d failed, %d skipped\n", s.Total, s.Succeeded, s.Failed, s.Skipped)
+	return err
+}
+
+// lastLine returns the last non-empty line of stdout, or of stderr if stdout
+// is empty.
+func lastLine(stdout, stderr string) string {
+	for _, out := range []string{stdout, stderr} {
+		out = strings.TrimSpace(out)
+		if out == "" {
+			continue
+		}
+		lines := strings.Split(out, "\n")
+		return strings.TrimSpace(lines[len(lines)-1])
+	}
+	return ""
+}
+
+func announcementColumns(fingerprint string, an *api.Announcement) []string {
+	addresses := []string{}
+	for _, iface := range an.GetNetwork().GetNetworkInterfaces() {
+		for _, addr := range iface.Addresses {
+			if ip := net.ParseIP(addr.Address); ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+				continue
+			}
+			addresses = append(addresses, addr.Address)
+		}
+	}
+	labels := []string{}
+	for k, v := range an.GetLabels() {
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+	cloudInit := "-"
+	if an.GetCloudInit() != nil {
+		cloudInit = an.GetCloudInit().State.String()
+	}
+	return []string{
+		fingerprint,
+		orDash(an.GetUname().GetHostname()),
+		orDash(strings.Join(addresses, ",")),
+		orDash(strings.Join(labels, ",")),
+		cloudInit,
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
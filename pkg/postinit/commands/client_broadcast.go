@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/kralicky/post-init/pkg/sdk"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func BuildClientBroadcastCmd() *cobra.Command {
+	var flags clientFlags
+	var selector selectorFlags
+	var output string
+	var scriptFile string
+	var interpreter string
+	var env []string
+	var parallelism int
+	var hostTimeout time.Duration
+	var batchSize string
+	var batchPause time.Duration
+	var maxFailures int
+	var process processFlags
+
+	cmd := &cobra.Command{
+		Use:   "broadcast [--script <file>] -- [<command> [args...]]",
+		Short: "Run a command or script on all matching agents",
+		Long: `Run a command or script on all matching agents.
+
+The instruction runs once on every connected agent which matches the selector
+flags and on which the client's key is authorized. A row is printed for each
+agent as it finishes, followed by a summary. With --script, the remaining
+arguments are passed to the script, which is read from the given local file,
+or from stdin if the file is "-".
+
+With --batch-size, the instruction is rolled out in batches of the given number
+or percentage (e.g. 10 or 25%) of the matching agents, and the rollout is
+aborted once more than --max-failures agents have failed.
+
+The exit code is 1 if the instruction failed or returned a non-zero exit code
+on any agent, or if the rollout was aborted.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			p, err := newPrinter(os.Stdout, output)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if scriptFile == "-" && process.stdinFile == "-" {
+				logrus.Fatal("the script and --stdin cannot both be read from stdin")
+			}
+			var instruction *api.JobStep
+			if scriptFile != "" {
+				var data []byte
+				if scriptFile == "-" {
+					data, err = io.ReadAll(os.Stdin)
+				} else {
+					data, err = os.ReadFile(scriptFile)
+				}
+				if err != nil {
+					logrus.Fatal(err)
+				}
+				script := &api.Script{
+					Interpreter: interpreter,
+					Script:      string(data),
+					Args:        args,
+				}
+				if err := process.ApplyScript(script); err != nil {
+					logrus.Fatal(err)
+				}
+				instruction = sdk.ScriptStep(script)
+			} else {
+				if len(args) == 0 {
+					logrus.Fatal("either a command or --script is required")
+				}
+				command := &api.Command{
+					Command: args[0],
+					Args:    args[1:],
+					Env:     env,
+				}
+				if err := process.ApplyCommand(command); err != nil {
+					logrus.Fatal(err)
+				}
+				instruction = sdk.CommandStep(command)
+			}
+			expr, err := selector.Expression()
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			opts := []sdk.BroadcastOption{
+				sdk.WithParallelism(parallelism),
+				sdk.WithHostTimeout(hostTimeout),
+			}
+			if batchSize != "" {
+				opt, err := parseBatchSize(batchSize)
+				if err != nil {
+					logrus.Fatal(err)
+				}
+				opts = append(opts, opt, sdk.WithBatchPause(batchPause), sdk.WithMaxFailures(maxFailures))
+			} else if cmd.Flags().Changed("batch-pause") || cmd.Flags().Changed("max-failures") {
+				logrus.Fatal("--batch-pause and --max-failures require --batch-size")
+			}
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			client, err := flags.Connect(ctx)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if err := p.BroadcastHeader(); err != nil {
+				logrus.Fatal(err)
+			}
+			summary, err := client.Broadcast(ctx, expr, instruction, func(result *api.BroadcastResult) {
+				if err := p.BroadcastResult(result); err != nil {
+					logrus.Error(err)
+				}
+			}, opts...)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if err := p.BroadcastSummary(summary); err != nil {
+				logrus.Fatal(err)
+			}
+			if summary.Failed > 0 || summary.Aborted {
+				os.Exit(1)
+			}
+		},
+	}
+	flags.AddFlags(cmd)
+	selector.AddFlags(cmd)
+	addOutputFlag(cmd, &output)
+	cmd.Flags().StringVar(&scriptFile, "script", "", "run the script in the given local file instead of a command (\"-\" to read from stdin)")
+	cmd.Flags().StringVar(&interpreter, "interpreter", "/bin/sh", "interpreter used to run the script on each agent")
+	cmd.Flags().StringArrayVarP(&env, "env", "e", nil, "environment variable to set for the command, in the form KEY=VALUE (can be repeated)")
+	cmd.Flags().IntVarP(&parallelism, "parallelism", "p", 10, "maximum number of agents to run on at once (0 for no limit)")
+	cmd.Flags().DurationVar(&hostTimeout, "host-timeout", 0, "time limit for the instruction on each agent (e.g. 30s, 0 for no limit)")
+	cmd.Flags().StringVar(&batchSize, "batch-size", "", "roll out to this many agents at a time, or a percentage of the matching agents (e.g. 10 or 25%)")
+	cmd.Flags().DurationVar(&batchPause, "batch-pause", 0, "time to wait between batches")
+	cmd.Flags().IntVar(&maxFailures, "max-failures", 0, "abort the rollout once more than this many agents have failed (-1 to never abort)")
+	process.AddFlags(cmd)
+	return cmd
+}
+
+// parseBatchSize parses a batch size given as a number of agents, or as a
+// percentage of the matching agents.
+func parseBatchSize(s string) (sdk.BroadcastOption, error) {
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid batch percentage %q", s)
+		}
+		return sdk.WithBatchPercent(percent), nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("invalid batch size %q", s)
+	}
+	return sdk.WithBatchSize(n), nil
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func BuildClientLsCmd() *cobra.Command {
+	var flags clientFlags
+	var selector selectorFlags
+	var output string
+	var all bool
+	var since time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List agents connected to the relay",
+		Long: `List agents connected to the relay.
+
+Only agents on which the client's key is authorized are shown. With --all,
+the relay's announcement history is listed instead, including agents which
+have since disconnected.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			p, err := newPrinter(os.Stdout, output)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			expr, err := selector.Expression()
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			ctx := context.Background()
+			client, err := flags.Connect(ctx)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if !all {
+				agents, err := client.ListAgents(ctx, expr)
+				if err != nil {
+					logrus.Fatal(err)
+				}
+				if err := p.Agents(agents); err != nil {
+					logrus.Fatal(err)
+				}
+				return
+			}
+			var start time.Time
+			if since > 0 {
+				start = time.Now().Add(-since)
+			}
+			history, err := client.AnnouncementHistory(ctx, start, time.Time{})
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			records := []*api.AnnouncementRecord{}
+			for _, record := range history {
+				if expr.Accepts(record.Announcement) {
+					records = append(records, record)
+				}
+			}
+			if err := p.AnnouncementRecords(records); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	flags.AddFlags(cmd)
+	selector.AddFlags(cmd)
+	addOutputFlag(cmd, &output)
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "include agents which have disconnected")
+	cmd.Flags().DurationVar(&since, "since", 0, "with --all, only include agents which connected within the given duration (e.g. 24h)")
+	return cmd
+}
@@ -0,0 +1,269 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/kralicky/post-init/pkg/sdk"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func BuildClientRunCmd() *cobra.Command {
+	var flags clientFlags
+	var output string
+	var env []string
+	var timeout time.Duration
+	var process processFlags
+
+	cmd := &cobra.Command{
+		Use:   "run <fingerprint> -- <command> [args...]",
+		Short: "Run a command on an agent",
+		Long: `Run a command on an agent.
+
+In human output format, the command's output is printed as it is produced. In
+JSON format, the complete response is printed once the command exits. The exit
+code of the command is used as the exit code of this command.
+
+If the command times out or this command is interrupted, the command's process
+group is killed on the agent, and the exit code is 1.`,
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			p, err := newPrinter(os.Stdout, output)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			client, err := flags.Connect(context.Background())
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			// Interrupting cancels the instruction, which needs the connection to
+			// remain open.
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			cc := client.Control(ctx, args[0])
+			command := &api.Command{
+				Command: args[1],
+				Args:    args[2:],
+				Env:     env,
+			}
+			if timeout > 0 {
+				command.Timeout = durationpb.New(timeout)
+			}
+			if err := process.ApplyCommand(command); err != nil {
+				logrus.Fatal(err)
+			}
+			exit := &api.ExitStatus{}
+			if p.JSON() {
+				resp, err := cc.RunCommand(command)
+				if err != nil {
+					logrus.Fatal(err)
+				}
+				if err := p.Message(resp); err != nil {
+					logrus.Fatal(err)
+				}
+				exit.ExitCode = resp.ExitCode
+				exit.Terminated = resp.Terminated
+			} else {
+				exit, err = streamOutput(func(h sdk.OutputHandler) error {
+					return cc.StreamCommand(command, h)
+				})
+				if err != nil {
+					logrus.Fatal(err)
+				}
+			}
+			os.Exit(exitCode(exit))
+		},
+	}
+	flags.AddFlags(cmd)
+	addOutputFlag(cmd, &output)
+	cmd.Flags().StringArrayVarP(&env, "env", "e", nil, "environment variable to set for the command, in the form KEY=VALUE (can be repeated)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "time limit for the command (e.g. 30s, 0 for no limit)")
+	process.AddFlags(cmd)
+	return cmd
+}
+
+func BuildClientScriptCmd() *cobra.Command {
+	var flags clientFlags
+	var output string
+	var interpreter string
+	var timeout time.Duration
+	var process processFlags
+
+	cmd := &cobra.Command{
+		Use:   "script <fingerprint> <file> [args...]",
+		Short: "Run a script on an agent",
+		Long: `Run a script on an agent.
+
+The script is read from the given local file, or from stdin if the file is "-".
+Output is printed and the script is terminated in the same way as the run
+command.`,
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			p, err := newPrinter(os.Stdout, output)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if args[1] == "-" && process.stdinFile == "-" {
+				logrus.Fatal("the script and --stdin cannot both be read from stdin")
+			}
+			var data []byte
+			if args[1] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[1])
+			}
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			client, err := flags.Connect(context.Background())
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			// Interrupting cancels the instruction, which needs the connection to
+			// remain open.
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			cc := client.Control(ctx, args[0])
+			script := &api.Script{
+				Interpreter: interpreter,
+				Script:      string(data),
+				Args:        args[2:],
+			}
+			if timeout > 0 {
+				script.Timeout = durationpb.New(timeout)
+			}
+			if err := process.ApplyScript(script); err != nil {
+				logrus.Fatal(err)
+			}
+			exit := &api.ExitStatus{}
+			if p.JSON() {
+				resp, err := cc.RunScript(script)
+				if err != nil {
+					logrus.Fatal(err)
+				}
+				if err := p.Message(resp); err != nil {
+					logrus.Fatal(err)
+				}
+				exit.ExitCode = resp.ExitCode
+				exit.Terminated = resp.Terminated
+			} else {
+				exit, err = streamOutput(func(h sdk.OutputHandler) error {
+					return cc.StreamScript(script, h)
+				})
+				if err != nil {
+					logrus.Fatal(err)
+				}
+			}
+			os.Exit(exitCode(exit))
+		},
+	}
+	flags.AddFlags(cmd)
+	addOutputFlag(cmd, &output)
+	cmd.Flags().StringVar(&interpreter, "interpreter", "/bin/sh", "interpreter used to run the script on the agent")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "time limit for the script (e.g. 30s, 0 for no limit)")
+	process.AddFlags(cmd)
+	return cmd
+}
+
+// processFlags holds flags which control how the process which runs a
+// command or script is started on the agent.
+type processFlags struct {
+	user       string
+	group      string
+	workingDir string
+	umask      string
+	stdinFile  string
+}
+
+func (f *processFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.user, "user", "u", "", "user to run as, by name or ID (the agent must be running as root)")
+	cmd.Flags().StringVarP(&f.group, "group", "g", "", "group to run as, by name or ID (default: the user's primary group)")
+	cmd.Flags().StringVarP(&f.workingDir, "workdir", "w", "", "absolute path of the working directory on the agent")
+	cmd.Flags().StringVar(&f.umask, "umask", "", "file mode creation mask, in octal (e.g. 022)")
+	cmd.Flags().StringVar(&f.stdinFile, "stdin", "", "send the contents of the given local file to stdin (\"-\" to read from stdin)")
+}
+
+func (f *processFlags) ApplyCommand(cmd *api.Command) error {
+	umask, stdin, err := f.parse()
+	if err != nil {
+		return err
+	}
+	cmd.User = f.user
+	cmd.Group = f.group
+	cmd.WorkingDir = f.workingDir
+	cmd.Umask = umask
+	cmd.Stdin = stdin
+	return nil
+}
+
+func (f *processFlags) ApplyScript(script *api.Script) error {
+	umask, stdin, err := f.parse()
+	if err != nil {
+		return err
+	}
+	script.User = f.user
+	script.Group = f.group
+	script.WorkingDir = f.workingDir
+	script.Umask = umask
+	script.Stdin = stdin
+	return nil
+}
+
+func (f *processFlags) parse() (umask *uint32, stdin []byte, err error) {
+	if f.umask != "" {
+		n, err := strconv.ParseUint(f.umask, 8, 32)
+		if err != nil || n > 0777 {
+			return nil, nil, fmt.Errorf("invalid umask %q", f.umask)
+		}
+		mask := uint32(n)
+		umask = &mask
+	}
+	switch f.stdinFile {
+	case "":
+	case "-":
+		stdin, err = io.ReadAll(os.Stdin)
+	default:
+		stdin, err = os.ReadFile(f.stdinFile)
+	}
+	return umask, stdin, err
+}
+
+// streamOutput runs an instruction, writing its output to stdout and stderr
+// as it is received, and returns its exit status. If the instruction was
+// terminated, the reason is written to stderr.
+func streamOutput(run func(sdk.OutputHandler) error) (*api.ExitStatus, error) {
+	exit := &api.ExitStatus{}
+	err := run(func(ev *api.OutputEvent) {
+		switch e := ev.Event.(type) {
+		case *api.OutputEvent_Output:
+			if e.Output.Source == api.OutputSource_Stderr {
+				os.Stderr.Write(e.Output.Data)
+			} else {
+				os.Stdout.Write(e.Output.Data)
+			}
+		case *api.OutputEvent_Exit:
+			exit = e.Exit
+			if exit.Terminated {
+				fmt.Fprintf(os.Stderr, "terminated: %s\n", exit.TerminationReason)
+			}
+		}
+	})
+	return exit, err
+}
+
+// exitCode returns the exit code to use for an instruction's exit status.
+func exitCode(exit *api.ExitStatus) int {
+	if exit.Terminated {
+		return 1
+	}
+	return int(exit.ExitCode)
+}
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/kralicky/post-init/pkg/host"
+	"github.com/kralicky/post-init/pkg/sdk"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// selectorFlags holds flags which select agents by the contents of their
+// announcements.
+type selectorFlags struct {
+	hostname      string
+	ipAddress     string
+	authorizedKey string
+	labels        []string
+	cloudInit     string
+}
+
+func (f *selectorFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.hostname, "hostname", "", "select agents whose hostname matches a glob pattern")
+	cmd.Flags().StringVar(&f.ipAddress, "ip", "", "select agents with an address in a comma-separated list of IP addresses and CIDR ranges (prefix with ! to exclude)")
+	cmd.Flags().StringVar(&f.authorizedKey, "authorized-key", "", "select agents with the given key fingerprint in their authorized keys")
+	cmd.Flags().StringArrayVarP(&f.labels, "label", "l", nil, "select agents with the given label, in the form key=value or key (can be repeated)")
+	cmd.Flags().StringVar(&f.cloudInit, "cloud-init", "", "select agents by cloud-init state (e.g. error, running, done)")
+}
+
+// Expression returns an expression matching agents which satisfy all of the
+// given flags. If no flags are given, all agents are matched.
+func (f *selectorFlags) Expression() (*api.Expression, error) {
+	exprs := []*api.Expression{}
+	if f.hostname != "" {
+		exprs = append(exprs, sdk.Hostname(sdk.Glob(f.hostname)))
+	}
+	if f.ipAddress != "" {
+		exprs = append(exprs, sdk.IPAddress(f.ipAddress))
+	}
+	if f.authorizedKey != "" {
+		exprs = append(exprs, sdk.AuthorizedKey(f.authorizedKey))
+	}
+	for _, label := range f.labels {
+		if strings.Contains(label, "=") {
+			kv, err := host.ParseLabels([]string{label})
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range kv {
+				exprs = append(exprs, sdk.Label(k, sdk.Exact(v)))
+			}
+		} else {
+			exprs = append(exprs, sdk.Label(label, nil))
+		}
+	}
+	if f.cloudInit != "" {
+		state, err := parseCloudInitState(f.cloudInit)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, sdk.CloudInit(state))
+	}
+	expr := sdk.And(exprs...)
+	return expr, expr.Validate()
+}
+
+func parseCloudInitState(s string) (api.CloudInitState, error) {
+	normalized := strings.NewReplacer("-", "", "_", "", " ", "").Replace(s)
+	for value, name := range api.CloudInitState_name {
+		if strings.EqualFold(name, normalized) {
+			return api.CloudInitState(value), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown cloud-init state %q", s)
+}
+
+func BuildClientWatchCmd() *cobra.Command {
+	var flags clientFlags
+	var selector selectorFlags
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print events from matching agents as they connect, change and disconnect",
+		Long: `Print events from matching agents as they connect, change and disconnect.
+
+Agents which are already connected are printed first. Only agents on which the
+client's key is authorized are shown. Watch runs until interrupted.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			p, err := newPrinter(os.Stdout, output)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			expr, err := selector.Expression()
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			client, err := flags.Connect(ctx)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if err := p.EventHeader(); err != nil {
+				logrus.Fatal(err)
+			}
+			closed := make(chan string, 1)
+			if _, err := client.WatchEvents(ctx, expr, func(ev *api.WatchEvent, _ sdk.ControlContext) {
+				if err := p.Event(ev); err != nil {
+					logrus.Error(err)
+				}
+				if ev.Type == api.WatchEventType_Closed {
+					closed <- ev.Reason
+				}
+			}); err != nil {
+				logrus.Fatal(err)
+			}
+			select {
+			case <-ctx.Done():
+			case reason := <-closed:
+				logrus.Fatalf("Watch closed by the relay: %s", reason)
+			}
+		},
+	}
+	flags.AddFlags(cmd)
+	selector.AddFlags(cmd)
+	addOutputFlag(cmd, &output)
+	return cmd
+}
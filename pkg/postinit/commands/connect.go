@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kralicky/post-init/pkg/sdk"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+// clientFlags holds the flags common to all commands which connect to a
+// relay as a client.
+type clientFlags struct {
+	relayAddress   string
+	relayCert      string
+	insecure       bool
+	identity       string
+	keyFingerprint string
+	certificate    string
+}
+
+func (f *clientFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.relayAddress, "relay-address", "", "Address of the relay to connect to")
+	cmd.Flags().StringVar(&f.relayCert, "cacert", "", "(optional) path to a self-signed certificate for the relay")
+	cmd.Flags().BoolVar(&f.insecure, "insecure", false, "Connect to the relay in insecure mode (for testing only)")
+	cmd.Flags().StringVarP(&f.identity, "identity", "i", "", "Path to the private key used to authenticate with the relay (default: use ssh-agent if $SSH_AUTH_SOCK is set, otherwise ~/.ssh/id_ed25519)")
+	cmd.Flags().StringVar(&f.keyFingerprint, "key-fingerprint", "", "SHA256 fingerprint of the ssh-agent key used to authenticate with the relay, if the agent holds more than one key")
+	cmd.Flags().StringVar(&f.certificate, "certificate", "", "Path to an OpenSSH user certificate for the key (default: <identity>-cert.pub, if it exists)")
+}
+
+// Connect loads the client's private key, or selects a key from ssh-agent,
+// and connects to the relay. Like ssh(1), a certificate next to the private
+// key is used along with it.
+func (f *clientFlags) Connect(ctx context.Context) (*sdk.RelayClient, error) {
+	conf := &sdk.ClientConfig{
+		Address:  f.relayAddress,
+		Insecure: f.insecure,
+		CACert:   f.relayCert,
+	}
+	certificate := f.certificate
+	switch {
+	case f.identity != "":
+		signer, err := loadSigner(f.identity)
+		if err != nil {
+			return nil, err
+		}
+		conf.Signer = signer
+		if certificate == "" {
+			certificate = defaultCertificate(f.identity)
+		}
+	case os.Getenv("SSH_AUTH_SOCK") != "":
+		conf.SSHAgent = true
+		conf.KeyFingerprint = f.keyFingerprint
+	case f.keyFingerprint != "":
+		return nil, fmt.Errorf("--key-fingerprint requires ssh-agent: %w", sdk.ErrNoSSHAgent)
+	default:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		identity := filepath.Join(home, ".ssh", "id_ed25519")
+		signer, err := loadSigner(identity)
+		if err != nil {
+			return nil, err
+		}
+		conf.Signer = signer
+		if certificate == "" {
+			certificate = defaultCertificate(identity)
+		}
+	}
+	if certificate != "" {
+		cert, err := loadCertificate(certificate)
+		if err != nil {
+			return nil, err
+		}
+		conf.Certificate = cert
+	}
+	client, err := sdk.NewRelayClient(conf)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Connect(ctx); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// loadSigner reads a private key from the given path, prompting for a
+// passphrase if the key is encrypted.
+func loadSigner(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	if _, ok := err.(*ssh.PassphraseMissingError); ok {
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			return nil, fmt.Errorf("key %s is encrypted and no terminal is available to prompt for a passphrase", path)
+		}
+		fmt.Fprintf(os.Stderr, "Enter passphrase for key '%s': ", path)
+		passphrase, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, err
+		}
+		return ssh.ParsePrivateKeyWithPassphrase(data, passphrase)
+	}
+	return signer, err
+}
+
+// defaultCertificate returns the path of the certificate for the private key
+// at the given path, or an empty string if there is none.
+func defaultCertificate(identity string) string {
+	path := identity + "-cert.pub"
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// loadCertificate reads an OpenSSH certificate from the given path.
+func loadCertificate(path string) (*ssh.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, err
+	}
+	cert, ok := key.(*ssh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a certificate", path)
+	}
+	return cert, nil
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/kralicky/post-init/pkg/sdk"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func BuildCpCmd() *cobra.Command {
+	var flags clientFlags
+	var owner string
+	var mode string
+
+	cmd := &cobra.Command{
+		Use:   "cp <src> <dest>",
+		Short: "Copy files and directories to or from an agent",
+		Long: `Copy files and directories to or from an agent.
+
+Exactly one of src or dest must be a path on an agent, written as
+fingerprint:/absolute/path, for example SHA256:abc...:/etc/hosts.
+Directories are copied recursively.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			srcFingerprint, srcPath := parseRemotePath(args[0])
+			destFingerprint, destPath := parseRemotePath(args[1])
+			if (srcFingerprint == "") == (destFingerprint == "") {
+				logrus.Fatal("exactly one of src or dest must be a path on an agent")
+			}
+
+			var opts []sdk.FileOption
+			if owner != "" {
+				parts := strings.SplitN(owner, ":", 2)
+				group := ""
+				if len(parts) == 2 {
+					group = parts[1]
+				}
+				opts = append(opts, sdk.WithOwner(parts[0], group))
+			}
+			if mode != "" {
+				m, err := strconv.ParseUint(mode, 8, 32)
+				if err != nil {
+					logrus.Fatalf("invalid mode %q: %v", mode, err)
+				}
+				opts = append(opts, sdk.WithMode(fs.FileMode(m)))
+			}
+
+			ctx := context.Background()
+			client, err := flags.Connect(ctx)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if destFingerprint != "" {
+				err = client.Control(ctx, destFingerprint).PutFile(srcPath, destPath, opts...)
+			} else {
+				if len(opts) > 0 {
+					logrus.Warn("--owner and --mode only apply when copying to an agent")
+				}
+				err = client.Control(ctx, srcFingerprint).GetFile(srcPath, destPath)
+			}
+			if err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	flags.AddFlags(cmd)
+	cmd.Flags().StringVar(&owner, "owner", "", "user[:group] which will own the copied files on the agent")
+	cmd.Flags().StringVar(&mode, "mode", "", "octal permission bits of the copied file on the agent")
+	return cmd
+}
+
+// parseRemotePath splits a path of the form fingerprint:/path into its
+// fingerprint and path. If the path does not refer to an agent, the returned
+// fingerprint is empty.
+func parseRemotePath(arg string) (string, string) {
+	const prefix = "SHA256:"
+	if !strings.HasPrefix(arg, prefix) {
+		return "", arg
+	}
+	parts := strings.SplitN(arg[len(prefix):], ":", 2)
+	if len(parts) != 2 {
+		return "", arg
+	}
+	return prefix + parts[0], parts[1]
+}
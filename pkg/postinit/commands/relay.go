@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"time"
 
 	"github.com/kralicky/post-init/pkg/relay"
 	"github.com/sirupsen/logrus"
@@ -12,15 +13,26 @@ func BuildRelayCmd() *cobra.Command {
 	var servingCert string
 	var servingKey string
 	var insecure bool
+	var dataDir string
+	var trustedUserCAKeys string
+	var retention time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "relay",
 		Short: "Run the post-init relay",
 		Run: func(cmd *cobra.Command, args []string) {
-			srv := relay.NewRelayServer(
+			opts := []relay.RelayServerOption{
 				relay.ServingCerts(servingCert, servingKey),
 				relay.Insecure(insecure),
-			)
+				relay.TrustedUserCAKeys(trustedUserCAKeys),
+			}
+			if dataDir != "" {
+				opts = append(opts, relay.DataDir(dataDir), relay.Retention(retention))
+			} else {
+				logrus.Warn("No data directory configured, relay state will not persist across restarts")
+				opts = append(opts, relay.Storage(relay.NewMemoryStore(relay.WithRetention(retention))))
+			}
+			srv := relay.NewRelayServer(opts...)
 			ctx := context.Background()
 			logrus.Info("Starting post-init relay")
 			if err := srv.Serve(ctx); err != nil {
@@ -31,6 +43,9 @@ func BuildRelayCmd() *cobra.Command {
 	cmd.Flags().StringVar(&servingCert, "serving-cert", "", "Path to the serving certificate")
 	cmd.Flags().StringVar(&servingKey, "serving-key", "", "Path to the serving key")
 	cmd.Flags().BoolVar(&insecure, "insecure", false, "Run the relay in insecure mode (for testing only)")
+	cmd.Flags().StringVar(&dataDir, "data-dir", relay.DefaultDataDir(), "Directory in which to store jobs, announcement history and audit records (set to \"\" to keep them in memory)")
+	cmd.Flags().DurationVar(&retention, "retention", relay.DefaultRetention, "How long to keep announcement history and audit records (0 to keep them forever)")
+	cmd.Flags().StringVar(&trustedUserCAKeys, "trusted-user-ca-keys", "", "Path to a file of CA public keys (in authorized_keys format) whose user certificates clients can authenticate with")
 
 	return cmd
 }
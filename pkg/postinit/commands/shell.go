@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/kralicky/post-init/pkg/sdk"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func BuildShellCmd() *cobra.Command {
+	var flags clientFlags
+
+	cmd := &cobra.Command{
+		Use:   "shell <fingerprint>",
+		Short: "Open an interactive shell on an agent",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			client, err := flags.Connect(ctx)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			exitCode, err := runShell(client.Control(ctx, args[0]))
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			os.Exit(int(exitCode))
+		},
+	}
+	flags.AddFlags(cmd)
+	return cmd
+}
+
+// runShell attaches the local terminal to a shell on the agent, and returns
+// the shell's exit code once it exits.
+func runShell(cc sdk.ControlContext) (int32, error) {
+	fd := int(os.Stdin.Fd())
+	isTerminal := term.IsTerminal(fd)
+
+	sh := &api.Shell{
+		Term: os.Getenv("TERM"),
+	}
+	if isTerminal {
+		if cols, rows, err := term.GetSize(fd); err == nil {
+			sh.Size = &api.WindowSize{
+				Rows: uint32(rows),
+				Cols: uint32(cols),
+			}
+		}
+	}
+
+	var exitCode int32
+	session, err := cc.Shell(sh, func(ev *api.OutputEvent) {
+		switch e := ev.Event.(type) {
+		case *api.OutputEvent_Output:
+			os.Stdout.Write(e.Output.Data)
+		case *api.OutputEvent_Exit:
+			exitCode = e.Exit.ExitCode
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if isTerminal {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return 0, err
+		}
+		defer term.Restore(fd, state)
+
+		winch := make(chan os.Signal, 1)
+		signal.Notify(winch, syscall.SIGWINCH)
+		defer signal.Stop(winch)
+		go func() {
+			for range winch {
+				if cols, rows, err := term.GetSize(fd); err == nil {
+					session.Resize(uint32(rows), uint32(cols))
+				}
+			}
+		}()
+	}
+
+	go io.Copy(session, os.Stdin)
+
+	if err := session.Wait(); err != nil {
+		return 0, err
+	}
+	return exitCode, nil
+}
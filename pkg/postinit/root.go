@@ -17,6 +17,9 @@ func CreateRootCmd() *cobra.Command {
 
 	rootCmd.AddCommand(commands.BuildAgentCmd())
 	rootCmd.AddCommand(commands.BuildRelayCmd())
+	rootCmd.AddCommand(commands.BuildShellCmd())
+	rootCmd.AddCommand(commands.BuildCpCmd())
+	rootCmd.AddCommand(commands.BuildClientCmd())
 	return rootCmd
 }
 
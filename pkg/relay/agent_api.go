@@ -2,12 +2,15 @@ package relay
 
 import (
 	context "context"
+	"errors"
+	"io"
 
 	"github.com/kralicky/post-init/pkg/api"
 	"github.com/sirupsen/logrus"
 	grpc "google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type agentApiServer struct {
@@ -19,6 +22,10 @@ type agentApiServer struct {
 
 	// Closes when an announcement has been received.
 	anRecv chan struct{}
+	// Fingerprint of the agent and the context of the announcement, set when
+	// an announcement has been received.
+	fingerprint string
+	announceCtx context.Context
 }
 
 func NewAgentAPIServer(ctrl Controller) *agentApiServer {
@@ -38,10 +45,13 @@ func (s *agentApiServer) Announce(
 ) (*api.AnnouncementResponse, error) {
 	logrus.Info("Announcement received")
 
-	if _, err := an.Fingerprint(); err != nil {
+	fp, err := an.Fingerprint()
+	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	s.fingerprint = fp
+	s.announceCtx = ctx
 	close(s.anRecv)
 	s.ctrl.AgentConnected(ctx, an, s.instructionClient)
 
@@ -51,6 +61,51 @@ func (s *agentApiServer) Announce(
 	}, nil
 }
 
+func (s *agentApiServer) WriteOutput(
+	ctx context.Context,
+	ev *api.OutputEvent,
+) (*emptypb.Empty, error) {
+	select {
+	case <-s.anRecv:
+	default:
+		return nil, status.Error(codes.FailedPrecondition, "no announcement received")
+	}
+	if err := s.ctrl.WriteOutput(ctx, s.fingerprint, ev); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *agentApiServer) UpdateStatus(
+	ctx context.Context,
+	update *api.StatusUpdate,
+) (*emptypb.Empty, error) {
+	select {
+	case <-s.anRecv:
+	default:
+		return nil, status.Error(codes.FailedPrecondition, "no announcement received")
+	}
+	if err := s.ctrl.UpdateStatus(ctx, s.fingerprint, update); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// StreamClosed reports the error which ended the agent's stream, after an
+// announcement has been received.
+func (s *agentApiServer) StreamClosed(err error) {
+	var reason string
+	switch {
+	case err == nil, errors.Is(err, io.EOF):
+		reason = "agent closed the connection"
+	case status.Code(err) == codes.Canceled:
+		reason = "connection lost"
+	default:
+		reason = "stream error: " + err.Error()
+	}
+	s.ctrl.AgentDisconnecting(s.announceCtx, s.fingerprint, reason)
+}
+
 func (s *agentApiServer) AnnouncementReceived() <-chan struct{} {
 	return s.anRecv
 }
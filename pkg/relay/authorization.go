@@ -0,0 +1,194 @@
+package relay
+
+import (
+	context "context"
+
+	"github.com/kralicky/post-init/pkg/api"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// authorizingClient wraps an agent's instruction client so that every
+// instruction is checked against the agent's current announcement before it
+// is sent. The key of the client identified in the instruction's meta must
+// be authorized on the agent, and commands and scripts run as the user it is
+// authorized for (see api.Announcement.RunAsUser). The controller only hands
+// out the wrapped client, including to jobs which start when the agent
+// announces, so the same rules apply to jobs and broadcasts.
+type authorizingClient struct {
+	api.InstructionClient
+	announcement func() *api.Announcement
+}
+
+func newAuthorizingClient(client api.InstructionClient, announcement func() *api.Announcement) api.InstructionClient {
+	if client == nil {
+		return nil
+	}
+	return &authorizingClient{
+		InstructionClient: client,
+		announcement:      announcement,
+	}
+}
+
+func (c *authorizingClient) Command(
+	ctx context.Context,
+	in *api.CommandRequest,
+	opts ...grpc.CallOption,
+) (*api.CommandResponse, error) {
+	in, err := c.authorizeCommand(in)
+	if err != nil {
+		return nil, err
+	}
+	return c.InstructionClient.Command(ctx, in, opts...)
+}
+
+func (c *authorizingClient) Script(
+	ctx context.Context,
+	in *api.ScriptRequest,
+	opts ...grpc.CallOption,
+) (*api.ScriptResponse, error) {
+	in, err := c.authorizeScript(in)
+	if err != nil {
+		return nil, err
+	}
+	return c.InstructionClient.Script(ctx, in, opts...)
+}
+
+func (c *authorizingClient) CommandStream(
+	ctx context.Context,
+	in *api.CommandRequest,
+	opts ...grpc.CallOption,
+) (*emptypb.Empty, error) {
+	in, err := c.authorizeCommand(in)
+	if err != nil {
+		return nil, err
+	}
+	return c.InstructionClient.CommandStream(ctx, in, opts...)
+}
+
+func (c *authorizingClient) ScriptStream(
+	ctx context.Context,
+	in *api.ScriptRequest,
+	opts ...grpc.CallOption,
+) (*emptypb.Empty, error) {
+	in, err := c.authorizeScript(in)
+	if err != nil {
+		return nil, err
+	}
+	return c.InstructionClient.ScriptStream(ctx, in, opts...)
+}
+
+func (c *authorizingClient) Shell(
+	ctx context.Context,
+	in *api.ShellRequest,
+	opts ...grpc.CallOption,
+) (*emptypb.Empty, error) {
+	if err := c.authorizeAgentUser(in.Meta); err != nil {
+		return nil, err
+	}
+	return c.InstructionClient.Shell(ctx, in, opts...)
+}
+
+func (c *authorizingClient) ShellInput(
+	ctx context.Context,
+	in *api.ShellInputRequest,
+	opts ...grpc.CallOption,
+) (*emptypb.Empty, error) {
+	if err := c.authorizeAgentUser(in.Meta); err != nil {
+		return nil, err
+	}
+	return c.InstructionClient.ShellInput(ctx, in, opts...)
+}
+
+func (c *authorizingClient) PutFile(
+	ctx context.Context,
+	in *api.PutFileRequest,
+	opts ...grpc.CallOption,
+) (*emptypb.Empty, error) {
+	if err := c.authorizeAgentUser(in.Meta); err != nil {
+		return nil, err
+	}
+	return c.InstructionClient.PutFile(ctx, in, opts...)
+}
+
+func (c *authorizingClient) GetFile(
+	ctx context.Context,
+	in *api.GetFileRequest,
+	opts ...grpc.CallOption,
+) (*api.GetFileResponse, error) {
+	if err := c.authorizeAgentUser(in.Meta); err != nil {
+		return nil, err
+	}
+	return c.InstructionClient.GetFile(ctx, in, opts...)
+}
+
+// authorizeCommand returns a copy of the request with the user set to the
+// one the command will run as. The request is copied since the command may
+// be shared with other agents, on which it could run as a different user.
+func (c *authorizingClient) authorizeCommand(in *api.CommandRequest) (*api.CommandRequest, error) {
+	user, err := c.runAsUser(in.Meta, in.Command.GetUser(), in.Command.GetGroup())
+	if err != nil {
+		return nil, err
+	}
+	in = proto.Clone(in).(*api.CommandRequest)
+	if in.Command != nil {
+		in.Command.User = user
+	}
+	return in, nil
+}
+
+// authorizeScript is like authorizeCommand, for scripts.
+func (c *authorizingClient) authorizeScript(in *api.ScriptRequest) (*api.ScriptRequest, error) {
+	user, err := c.runAsUser(in.Meta, in.Script.GetUser(), in.Script.GetGroup())
+	if err != nil {
+		return nil, err
+	}
+	in = proto.Clone(in).(*api.ScriptRequest)
+	if in.Script != nil {
+		in.Script.User = user
+	}
+	return in, nil
+}
+
+func (c *authorizingClient) runAsUser(meta *api.InstructionMeta, user, group string) (string, error) {
+	an, client, err := c.currentAnnouncement(meta)
+	if err != nil {
+		return "", err
+	}
+	user, err = an.RunAsUser(client, user, group)
+	if err != nil {
+		return "", status.Error(codes.PermissionDenied, err.Error())
+	}
+	return user, nil
+}
+
+func (c *authorizingClient) authorizeAgentUser(meta *api.InstructionMeta) error {
+	an, client, err := c.currentAnnouncement(meta)
+	if err != nil {
+		return err
+	}
+	if _, err := an.AuthorizeAgentUser(client); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+// currentAnnouncement returns the agent's current announcement, and the key
+// of the client which sent the instruction.
+func (c *authorizingClient) currentAnnouncement(meta *api.InstructionMeta) (*api.Announcement, *api.ClientKey, error) {
+	if meta.GetClientFingerprint() == "" {
+		return nil, nil, status.Error(codes.PermissionDenied, "missing client fingerprint")
+	}
+	client, err := meta.ClientKey()
+	if err != nil {
+		return nil, nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	an := c.announcement()
+	if an == nil {
+		return nil, nil, status.Error(codes.NotFound, "agent is no longer connected")
+	}
+	return an, client, nil
+}
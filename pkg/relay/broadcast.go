@@ -0,0 +1,272 @@
+package relay
+
+import (
+	context "context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type broadcastTarget struct {
+	fingerprint  string
+	announcement *api.Announcement
+}
+
+// Broadcast runs the request's instruction on every connected agent which
+// matches its selector and on which the client's key is authorized, in
+// batches if the request has a rollout. The callback is called with each
+// agent's result as soon as it is available, but is never called
+// concurrently. Broadcast returns once the instruction has finished on every
+// agent, the rollout is aborted, or ctx is canceled.
+//
+// The client's address is passed on to the agents, which check it against
+// any from= option on the client's key.
+func (c *controller) Broadcast(
+	ctx context.Context,
+	clientKey ssh.PublicKey,
+	clientAddress string,
+	req *api.BroadcastRequest,
+	callback func(*api.BroadcastResult),
+) (*api.BroadcastSummary, error) {
+	if err := validateBroadcast(req); err != nil {
+		return nil, err
+	}
+	owner := api.NewClientKey(clientKey)
+	targets := c.broadcastTargets(owner, req.Selector())
+	summary := &api.BroadcastSummary{
+		Total: int32(len(targets)),
+	}
+	if len(targets) == 0 {
+		return summary, nil
+	}
+	batches := broadcastBatches(targets, req.Rollout)
+	lg := logrus.WithField("broadcast", req.BroadcastID)
+	lg.WithField("agents", len(targets)).
+		WithField("batches", len(batches)).
+		Info("Starting broadcast")
+
+	var mu sync.Mutex
+	for i, batch := range batches {
+		if i > 0 {
+			if rolloutAborted(req.Rollout, summary) {
+				for _, remaining := range batches[i:] {
+					summary.Skipped += int32(len(remaining))
+				}
+				summary.Aborted = true
+				lg.WithField("failed", summary.Failed).Warn("Too many agents failed, aborting rollout")
+				break
+			}
+			if pause := req.Rollout.GetPause(); pause != nil {
+				select {
+				case <-time.After(pause.AsDuration()):
+				case <-ctx.Done():
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		batchSummary := &api.BatchSummary{
+			Size:      int32(len(batch)),
+			StartTime: timestamppb.Now(),
+		}
+		summary.Batches = append(summary.Batches, batchSummary)
+		c.runBatch(ctx, owner, clientAddress, req, batch, func(result *api.BroadcastResult) {
+			result.Batch = int32(i)
+			mu.Lock()
+			defer mu.Unlock()
+			if stepFailed(result.Result) {
+				summary.Failed++
+				batchSummary.Failed++
+			} else {
+				summary.Succeeded++
+				batchSummary.Succeeded++
+			}
+			callback(result)
+		})
+		batchSummary.EndTime = timestamppb.Now()
+	}
+	if ctx.Err() != nil {
+		return nil, status.Error(codes.Canceled, "broadcast canceled")
+	}
+	lg.WithField("succeeded", summary.Succeeded).
+		WithField("failed", summary.Failed).
+		WithField("skipped", summary.Skipped).
+		Info("Broadcast complete")
+	return summary, nil
+}
+
+// broadcastTargets returns the connected agents which the broadcast will run
+// on, ordered by fingerprint.
+func (c *controller) broadcastTargets(owner *api.ClientKey, selector api.Selector) []broadcastTarget {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	targets := []broadcastTarget{}
+	for fp, agent := range c.activeAgents {
+		if !agent.announcement.Authorizes(owner) || !selector.Accepts(agent.announcement) {
+			continue
+		}
+		targets = append(targets, broadcastTarget{
+			fingerprint:  fp,
+			announcement: agent.announcement,
+		})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].fingerprint < targets[j].fingerprint
+	})
+	return targets
+}
+
+// broadcastBatches splits the targets into batches according to the rollout.
+// Without a rollout, all targets are in a single batch.
+func broadcastBatches(targets []broadcastTarget, rollout *api.Rollout) [][]broadcastTarget {
+	size := len(targets)
+	switch {
+	case rollout.GetBatchSize() > 0:
+		size = int(rollout.BatchSize)
+	case rollout.GetBatchPercent() > 0:
+		size = (len(targets)*int(rollout.BatchPercent) + 99) / 100
+	}
+	batches := [][]broadcastTarget{}
+	for len(targets) > 0 {
+		n := size
+		if n > len(targets) {
+			n = len(targets)
+		}
+		batches = append(batches, targets[:n])
+		targets = targets[n:]
+	}
+	return batches
+}
+
+// rolloutAborted returns true if more agents have failed than the rollout
+// allows.
+func rolloutAborted(rollout *api.Rollout, summary *api.BroadcastSummary) bool {
+	if rollout == nil || rollout.MaxFailures < 0 {
+		return false
+	}
+	return summary.Failed > rollout.MaxFailures
+}
+
+// runBatch runs the instruction on each agent in the batch, with at most
+// req.Parallelism agents running at once, and returns once all of them have
+// finished.
+func (c *controller) runBatch(
+	ctx context.Context,
+	owner *api.ClientKey,
+	clientAddress string,
+	req *api.BroadcastRequest,
+	batch []broadcastTarget,
+	callback func(*api.BroadcastResult),
+) {
+	parallelism := int(req.Parallelism)
+	if parallelism <= 0 || parallelism > len(batch) {
+		parallelism = len(batch)
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+LOOP:
+	for _, target := range batch {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break LOOP
+		}
+		wg.Add(1)
+		go func(target broadcastTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			callback(c.runBroadcast(ctx, owner, clientAddress, req, target))
+		}(target)
+	}
+	wg.Wait()
+}
+
+func (c *controller) runBroadcast(
+	ctx context.Context,
+	owner *api.ClientKey,
+	clientAddress string,
+	req *api.BroadcastRequest,
+	target broadcastTarget,
+) *api.BroadcastResult {
+	if req.Timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout.AsDuration())
+		defer cancel()
+	}
+	result := &api.BroadcastResult{
+		BroadcastID:      req.BroadcastID,
+		AgentFingerprint: target.fingerprint,
+		Announcement:     target.announcement,
+		StartTime:        timestamppb.Now(),
+	}
+	// Rollouts can take a long time, so the agent is looked up when its batch
+	// starts rather than when the broadcast starts, in case it has since
+	// reconnected.
+	if client, err := c.Lookup(ctx, target.fingerprint); err != nil {
+		result.Result = &api.JobStepResult{
+			Error: "agent is no longer connected",
+		}
+	} else {
+		result.Result = runStep(ctx, req.Instruction, &api.InstructionMeta{
+			PeerFingerprint:   target.fingerprint,
+			ClientFingerprint: owner.Fingerprint,
+			ClientAddress:     clientAddress,
+			ClientCertificate: owner.MarshalCertificate(),
+		}, client)
+	}
+	result.EndTime = timestamppb.Now()
+	c.Audit(&api.AuditRecord{
+		Time:              result.StartTime,
+		ClientFingerprint: owner.Fingerprint,
+		AgentFingerprint:  target.fingerprint,
+		Action:            "Broadcast",
+		Detail:            describeStep(req.Instruction),
+		Error:             result.Result.Error,
+	})
+	return result
+}
+
+func validateBroadcast(req *api.BroadcastRequest) error {
+	switch {
+	case req.Filter != nil && req.Expression != nil:
+		return status.Error(codes.InvalidArgument, "only one of filter or expression can be set")
+	case req.Expression != nil:
+		if err := req.Expression.Validate(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	case req.Filter == nil:
+		return status.Error(codes.InvalidArgument, "missing filter or expression")
+	}
+	if err := validateStep(req.Instruction); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Parallelism < 0 {
+		return status.Error(codes.InvalidArgument, "parallelism cannot be negative")
+	}
+	if req.Timeout != nil && req.Timeout.AsDuration() <= 0 {
+		return status.Error(codes.InvalidArgument, "timeout must be positive")
+	}
+	if rollout := req.Rollout; rollout != nil {
+		switch {
+		case rollout.BatchSize < 0 || rollout.BatchPercent < 0:
+			return status.Error(codes.InvalidArgument, "batch size cannot be negative")
+		case rollout.BatchSize > 0 && rollout.BatchPercent > 0:
+			return status.Error(codes.InvalidArgument, "only one of batch size or batch percent can be set")
+		case rollout.BatchSize == 0 && rollout.BatchPercent == 0:
+			return status.Error(codes.InvalidArgument, "missing batch size or batch percent")
+		case rollout.BatchPercent > 100:
+			return status.Error(codes.InvalidArgument, "batch percent cannot be more than 100")
+		case rollout.Pause != nil && rollout.Pause.AsDuration() < 0:
+			return status.Error(codes.InvalidArgument, "pause cannot be negative")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package relay
+
+import (
+	context "context"
+
+	"github.com/kralicky/post-init/pkg/api"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// cancelingClient wraps an agent's instruction client so that commands,
+// scripts and shells are canceled on the agent when the caller's context is
+// done (see api.RunCancelable). Totem does not propagate cancellation to the
+// agent, so without this the process would keep running after the caller gave
+// up on it.
+type cancelingClient struct {
+	api.InstructionClient
+}
+
+func newCancelingClient(client api.InstructionClient) api.InstructionClient {
+	if client == nil {
+		return nil
+	}
+	return &cancelingClient{
+		InstructionClient: client,
+	}
+}
+
+func (c *cancelingClient) Command(
+	ctx context.Context,
+	in *api.CommandRequest,
+	opts ...grpc.CallOption,
+) (resp *api.CommandResponse, err error) {
+	in.Meta, err = withInstructionID(in.Meta)
+	if err != nil {
+		return nil, err
+	}
+	err = api.RunCancelable(ctx, c.InstructionClient, in.Meta, func(ctx context.Context) (err error) {
+		resp, err = c.InstructionClient.Command(ctx, in, opts...)
+		return
+	})
+	return
+}
+
+func (c *cancelingClient) Script(
+	ctx context.Context,
+	in *api.ScriptRequest,
+	opts ...grpc.CallOption,
+) (resp *api.ScriptResponse, err error) {
+	in.Meta, err = withInstructionID(in.Meta)
+	if err != nil {
+		return nil, err
+	}
+	err = api.RunCancelable(ctx, c.InstructionClient, in.Meta, func(ctx context.Context) (err error) {
+		resp, err = c.InstructionClient.Script(ctx, in, opts...)
+		return
+	})
+	return
+}
+
+func (c *cancelingClient) CommandStream(
+	ctx context.Context,
+	in *api.CommandRequest,
+	opts ...grpc.CallOption,
+) (resp *emptypb.Empty, err error) {
+	in.Meta, err = withInstructionID(in.Meta)
+	if err != nil {
+		return nil, err
+	}
+	err = api.RunCancelable(ctx, c.InstructionClient, in.Meta, func(ctx context.Context) (err error) {
+		resp, err = c.InstructionClient.CommandStream(ctx, in, opts...)
+		return
+	})
+	return
+}
+
+func (c *cancelingClient) ScriptStream(
+	ctx context.Context,
+	in *api.ScriptRequest,
+	opts ...grpc.CallOption,
+) (resp *emptypb.Empty, err error) {
+	in.Meta, err = withInstructionID(in.Meta)
+	if err != nil {
+		return nil, err
+	}
+	err = api.RunCancelable(ctx, c.InstructionClient, in.Meta, func(ctx context.Context) (err error) {
+		resp, err = c.InstructionClient.ScriptStream(ctx, in, opts...)
+		return
+	})
+	return
+}
+
+func (c *cancelingClient) Shell(
+	ctx context.Context,
+	in *api.ShellRequest,
+	opts ...grpc.CallOption,
+) (resp *emptypb.Empty, err error) {
+	in.Meta, err = withInstructionID(in.Meta)
+	if err != nil {
+		return nil, err
+	}
+	err = api.RunCancelable(ctx, c.InstructionClient, in.Meta, func(ctx context.Context) (err error) {
+		resp, err = c.InstructionClient.Shell(ctx, in, opts...)
+		return
+	})
+	return
+}
+
+// withInstructionID returns a copy of meta with a random instruction ID, if
+// it does not already have one.
+func withInstructionID(meta *api.InstructionMeta) (*api.InstructionMeta, error) {
+	if meta.GetInstructionID() != "" {
+		return meta, nil
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		meta = &api.InstructionMeta{}
+	} else {
+		meta = proto.Clone(meta).(*api.InstructionMeta)
+	}
+	meta.InstructionID = id
+	return meta, nil
+}
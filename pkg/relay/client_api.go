@@ -2,11 +2,15 @@ package relay
 
 import (
 	context "context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/kralicky/post-init/pkg/api"
 	"github.com/kralicky/post-init/pkg/kex"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
 	grpc "google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -19,30 +23,56 @@ type clientApiServer struct {
 	ctrl Controller
 
 	// Filled in by the relay server
-	watchClient api.WatchClient
-	kexClient   api.KeyExchangeClient
+	watchClient     api.WatchClient
+	kexClient       api.KeyExchangeClient
+	outputClient    api.OutputStreamClient
+	broadcastClient api.BroadcastStreamClient
 
-	lock        sync.Mutex
+	// Set once by Connect. Connect holds the write lock until the key has
+	// been verified; every other method only reads it, and none hold the lock
+	// while calling into the controller or an agent.
+	keyMu       sync.RWMutex
 	verifiedKey ssh.PublicKey
+	// The IP address the client is connected from, if known
+	clientAddress string
+	// CAs whose user certificates clients can authenticate with
+	trustedUserCAKeys []ssh.PublicKey
+	// IDs of output streams opened by this client, and the fingerprints of
+	// the agents they were opened on
+	streamsMu sync.Mutex
+	streams   map[string]string
+
+	// Instructions started by this client which have not finished yet, by
+	// instruction ID, and the same for broadcasts, by broadcast ID
+	instructionsMu sync.Mutex
+	instructions   map[string]api.InstructionClient
+	broadcasts     map[string]context.CancelFunc
 }
 
-func NewClientAPIServer(ctrl Controller) *clientApiServer {
+func NewClientAPIServer(ctrl Controller, clientAddress string, trustedUserCAKeys []ssh.PublicKey) *clientApiServer {
 	return &clientApiServer{
-		ctrl: ctrl,
+		ctrl:              ctrl,
+		clientAddress:     clientAddress,
+		trustedUserCAKeys: trustedUserCAKeys,
+		streams:           make(map[string]string),
+		instructions:      make(map[string]api.InstructionClient),
+		broadcasts:        make(map[string]context.CancelFunc),
 	}
 }
 
 func (s *clientApiServer) InitClients(cc grpc.ClientConnInterface) {
 	s.watchClient = api.NewWatchClient(cc)
 	s.kexClient = api.NewKeyExchangeClient(cc)
+	s.outputClient = api.NewOutputStreamClient(cc)
+	s.broadcastClient = api.NewBroadcastStreamClient(cc)
 }
 
 func (s *clientApiServer) Connect(
 	ctx context.Context,
 	req *api.ConnectionRequest,
 ) (*api.ConnectionResponse, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.keyMu.Lock()
+	defer s.keyMu.Unlock()
 	if s.verifiedKey != nil {
 		return nil, status.Error(codes.FailedPrecondition, "already connected")
 	}
@@ -50,6 +80,14 @@ func (s *clientApiServer) Connect(
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
+	if cert, ok := pk.(*ssh.Certificate); ok {
+		if err := s.checkCertificate(cert); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		logrus.WithField("keyID", cert.KeyId).
+			WithField("principals", cert.ValidPrincipals).
+			Info("Client is using a certificate")
+	}
 	if err := s.verifyClientPublicKey(ctx, pk); err != nil {
 		return nil, err
 	}
@@ -62,12 +100,11 @@ func (s *clientApiServer) Watch(
 	ctx context.Context,
 	req *api.WatchRequest,
 ) (*emptypb.Empty, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-	if s.verifiedKey == nil {
-		return nil, status.Error(codes.FailedPrecondition, "not connected")
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, err
 	}
-	ch, err := s.ctrl.Watch(ctx, s.verifiedKey, req)
+	ch, err := s.ctrl.Watch(ctx, key, req)
 	if err != nil {
 		return nil, err
 	}
@@ -76,44 +113,388 @@ func (s *clientApiServer) Watch(
 			select {
 			case <-ctx.Done():
 				return
-			case an := <-ch:
-				s.watchClient.Notify(ctx, an)
+			case ev, ok := <-ch:
+				if !ok {
+					return
+				}
+				s.watchClient.Notify(ctx, ev)
 			}
 		}
 	}()
 	return &emptypb.Empty{}, nil
 }
 
+func (s *clientApiServer) Unwatch(
+	ctx context.Context,
+	ref *api.WatchReference,
+) (*emptypb.Empty, error) {
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ctrl.Unwatch(ctx, key, ref.WatchID); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
 func (s *clientApiServer) RunCommand(
 	ctx context.Context,
 	req *api.CommandRequest,
 ) (*api.CommandResponse, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-	if s.verifiedKey == nil {
-		return nil, status.Error(codes.FailedPrecondition, "not connected")
+	instructionClient, key, err := s.lookup(ctx, req.Meta)
+	if err != nil {
+		return nil, err
 	}
-	instructionClient, err := s.ctrl.Lookup(ctx, req.Meta.PeerFingerprint)
+	done, err := s.trackInstruction(req.Meta, instructionClient)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, "peer not found")
+		return nil, err
 	}
-	return instructionClient.Command(ctx, req)
+	defer done()
+	resp, err := instructionClient.Command(ctx, req)
+	s.audit(key, "RunCommand", req.Meta, describeCommand(req.Command), err)
+	return resp, err
 }
 
 func (s *clientApiServer) RunScript(
 	ctx context.Context,
 	req *api.ScriptRequest,
 ) (*api.ScriptResponse, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-	if s.verifiedKey == nil {
-		return nil, status.Error(codes.FailedPrecondition, "not connected")
+	instructionClient, key, err := s.lookup(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	done, err := s.trackInstruction(req.Meta, instructionClient)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	resp, err := instructionClient.Script(ctx, req)
+	s.audit(key, "RunScript", req.Meta, describeScript(req.Script), err)
+	return resp, err
+}
+
+func (s *clientApiServer) RunCommandStream(
+	ctx context.Context,
+	req *api.CommandRequest,
+) (*emptypb.Empty, error) {
+	instructionClient, err := s.lookupForStream(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	done, err := s.trackInstruction(req.Meta, instructionClient)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	err = s.streamOutput(ctx, req.Meta, func() error {
+		_, err := instructionClient.CommandStream(ctx, req)
+		return err
+	})
+	s.auditStream("RunCommandStream", req.Meta, describeCommand(req.Command), err)
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *clientApiServer) RunScriptStream(
+	ctx context.Context,
+	req *api.ScriptRequest,
+) (*emptypb.Empty, error) {
+	instructionClient, err := s.lookupForStream(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	done, err := s.trackInstruction(req.Meta, instructionClient)
+	if err != nil {
+		return nil, err
 	}
-	instructionClient, err := s.ctrl.Lookup(ctx, req.Meta.PeerFingerprint)
+	defer done()
+	err = s.streamOutput(ctx, req.Meta, func() error {
+		_, err := instructionClient.ScriptStream(ctx, req)
+		return err
+	})
+	s.auditStream("RunScriptStream", req.Meta, describeScript(req.Script), err)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, "peer not found")
+		return nil, err
 	}
-	return instructionClient.Script(ctx, req)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *clientApiServer) RunShell(
+	ctx context.Context,
+	req *api.ShellRequest,
+) (*emptypb.Empty, error) {
+	instructionClient, err := s.lookupForStream(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	err = s.streamOutput(ctx, req.Meta, func() error {
+		_, err := instructionClient.Shell(ctx, req)
+		return err
+	})
+	s.auditStream("RunShell", req.Meta, req.Shell.GetTerm(), err)
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *clientApiServer) ShellInput(
+	ctx context.Context,
+	req *api.ShellInputRequest,
+) (*emptypb.Empty, error) {
+	instructionClient, err := s.lookupForStream(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	if !s.ownsStream(req.Meta) {
+		return nil, status.Error(codes.NotFound, "stream not found")
+	}
+	return instructionClient.ShellInput(ctx, req)
+}
+
+// Cancel terminates a command or script previously started by this client,
+// identified by the instruction ID the client set in its request. The
+// pending call returns once the process has been killed.
+func (s *clientApiServer) Cancel(
+	ctx context.Context,
+	req *api.CancelRequest,
+) (*emptypb.Empty, error) {
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, err
+	}
+	id := req.Meta.GetInstructionID()
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing instruction ID")
+	}
+	s.instructionsMu.Lock()
+	instructionClient, ok := s.instructions[id]
+	s.instructionsMu.Unlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "instruction not found")
+	}
+	logrus.Infof("Canceling instruction %s", id)
+	// The agent only cancels instructions started by the same client
+	s.identifyClient(key, req.Meta)
+	return instructionClient.Cancel(ctx, req)
+}
+
+func (s *clientApiServer) PutFile(
+	ctx context.Context,
+	req *api.PutFileRequest,
+) (*emptypb.Empty, error) {
+	instructionClient, err := s.lookupForStream(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.continueTransfer(req.Meta, req.Info != nil); err != nil {
+		return nil, err
+	}
+	resp, err := instructionClient.PutFile(ctx, req)
+	if req.Done || err != nil {
+		s.endTransfer(req.Meta)
+	}
+	// Only the first and last chunks of a transfer are recorded
+	if req.Offset == 0 || req.Done || err != nil {
+		s.auditStream("PutFile", req.Meta, req.Info.GetPath(), err)
+	}
+	return resp, err
+}
+
+func (s *clientApiServer) GetFile(
+	ctx context.Context,
+	req *api.GetFileRequest,
+) (*api.GetFileResponse, error) {
+	instructionClient, err := s.lookupForStream(ctx, req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.continueTransfer(req.Meta, req.Offset == 0 && req.Path != ""); err != nil {
+		return nil, err
+	}
+	resp, err := instructionClient.GetFile(ctx, req)
+	if resp.GetEOF() || err != nil {
+		s.endTransfer(req.Meta)
+	}
+	// Only the first chunk of a transfer is recorded
+	if req.Offset == 0 || err != nil {
+		s.auditStream("GetFile", req.Meta, req.Path, err)
+	}
+	return resp, err
+}
+
+// lookup returns the instruction client of the agent identified in meta, and
+// the client's verified key. It sets the client fingerprint in meta, which
+// the agent's instruction client uses to authorize the instruction.
+func (s *clientApiServer) lookup(
+	ctx context.Context,
+	meta *api.InstructionMeta,
+) (api.InstructionClient, ssh.PublicKey, error) {
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	instructionClient, err := s.ctrl.Lookup(ctx, meta.GetPeerFingerprint())
+	if err != nil {
+		return nil, nil, status.Error(codes.NotFound, "peer not found")
+	}
+	s.identifyClient(key, meta)
+	return instructionClient, key, nil
+}
+
+// lookupForStream is like lookup, for instructions which are identified by a
+// stream ID.
+func (s *clientApiServer) lookupForStream(
+	ctx context.Context,
+	meta *api.InstructionMeta,
+) (api.InstructionClient, error) {
+	if meta.GetStreamID() == "" {
+		if _, err := s.connectedKey(); err != nil {
+			return nil, err
+		}
+		return nil, status.Error(codes.InvalidArgument, "missing stream ID")
+	}
+	instructionClient, _, err := s.lookup(ctx, meta)
+	return instructionClient, err
+}
+
+// ownsStream returns true if the stream identified in meta was opened by this
+// client on the agent identified in meta.
+func (s *clientApiServer) ownsStream(meta *api.InstructionMeta) bool {
+	s.streamsMu.Lock()
+	defer s.streamsMu.Unlock()
+	fp, ok := s.streams[meta.GetStreamID()]
+	return ok && fp == meta.GetPeerFingerprint()
+}
+
+// continueTransfer checks that a file transfer request belongs to a transfer
+// this client started on the agent identified in meta, or records that the
+// client started it if start is true. File transfers are made of separate
+// requests with the same stream ID, which the agent only authorizes when the
+// transfer starts.
+func (s *clientApiServer) continueTransfer(meta *api.InstructionMeta, start bool) error {
+	s.streamsMu.Lock()
+	defer s.streamsMu.Unlock()
+	id := meta.GetStreamID()
+	fp, ok := s.streams[id]
+	switch {
+	case start && ok:
+		return status.Error(codes.AlreadyExists, "stream already exists")
+	case start:
+		s.streams[id] = meta.GetPeerFingerprint()
+	case !ok || fp != meta.GetPeerFingerprint():
+		return status.Error(codes.NotFound, "stream not found")
+	}
+	return nil
+}
+
+// endTransfer forgets the file transfer identified in meta once it has
+// finished or failed.
+func (s *clientApiServer) endTransfer(meta *api.InstructionMeta) {
+	s.streamsMu.Lock()
+	defer s.streamsMu.Unlock()
+	delete(s.streams, meta.GetStreamID())
+}
+
+// identifyClient sets the fields of meta which identify the client to the
+// agent.
+func (s *clientApiServer) identifyClient(key ssh.PublicKey, meta *api.InstructionMeta) {
+	client := api.NewClientKey(key)
+	meta.ClientFingerprint = client.Fingerprint
+	meta.ClientCertificate = client.MarshalCertificate()
+	meta.ClientAddress = s.clientAddress
+}
+
+// trackInstruction records an instruction started by the client, so that the
+// client can cancel it. The returned function must be called once the
+// instruction has finished. Instructions without an ID are not tracked.
+func (s *clientApiServer) trackInstruction(
+	meta *api.InstructionMeta,
+	instructionClient api.InstructionClient,
+) (func(), error) {
+	id := meta.GetInstructionID()
+	if id == "" {
+		return func() {}, nil
+	}
+	s.instructionsMu.Lock()
+	defer s.instructionsMu.Unlock()
+	if _, ok := s.instructions[id]; ok {
+		return nil, status.Error(codes.AlreadyExists, "instruction already exists")
+	}
+	s.instructions[id] = instructionClient
+	return func() {
+		s.instructionsMu.Lock()
+		defer s.instructionsMu.Unlock()
+		delete(s.instructions, id)
+	}, nil
+}
+
+// trackBroadcast records a broadcast started by the client, so that the client
+// can cancel it, and returns the context it should run with. The returned
+// function must be called once the broadcast has finished.
+func (s *clientApiServer) trackBroadcast(ctx context.Context, id string) (context.Context, func(), error) {
+	s.instructionsMu.Lock()
+	defer s.instructionsMu.Unlock()
+	if _, ok := s.broadcasts[id]; ok {
+		return nil, nil, status.Error(codes.AlreadyExists, "broadcast already exists")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s.broadcasts[id] = cancel
+	return ctx, func() {
+		s.instructionsMu.Lock()
+		defer s.instructionsMu.Unlock()
+		delete(s.broadcasts, id)
+		cancel()
+	}, nil
+}
+
+// streamOutput forwards output events written by the agent for the stream
+// identified in meta to the client, until run returns. All events received
+// before run returns are forwarded before streamOutput returns.
+func (s *clientApiServer) streamOutput(
+	ctx context.Context,
+	meta *api.InstructionMeta,
+	run func() error,
+) error {
+	ch, err := s.ctrl.OpenOutputStream(ctx, meta.GetPeerFingerprint(), meta.GetStreamID())
+	if err != nil {
+		return err
+	}
+	s.streamsMu.Lock()
+	s.streams[meta.GetStreamID()] = meta.GetPeerFingerprint()
+	s.streamsMu.Unlock()
+	defer func() {
+		s.streamsMu.Lock()
+		delete(s.streams, meta.GetStreamID())
+		s.streamsMu.Unlock()
+	}()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev := range ch {
+			if _, err := s.outputClient.Write(ctx, ev); err != nil {
+				logrus.Errorf("Failed to forward output for stream %s: %v", ev.StreamID, err)
+			}
+		}
+	}()
+	err = run()
+	s.ctrl.CloseOutputStream(meta.GetStreamID())
+	<-done
+	return err
+}
+
+// checkCertificate checks a client's certificate before the client is allowed
+// to connect. The certificate must be signed by one of the relay's trusted
+// user CAs. Agents check it again against their own cert-authority entries
+// for each instruction.
+func (s *clientApiServer) checkCertificate(cert *ssh.Certificate) error {
+	if !api.IsSignedBy(cert, s.trustedUserCAKeys) {
+		return errors.New("certificate is not signed by a trusted user CA")
+	}
+	return api.CheckCertificate(cert, "", s.clientAddress, time.Now())
 }
 
 func (s *clientApiServer) verifyClientPublicKey(ctx context.Context, clientPubKey ssh.PublicKey) error {
@@ -159,3 +540,225 @@ func (s *clientApiServer) verifyClientPublicKey(ctx context.Context, clientPubKe
 
 	return nil
 }
+
+func (s *clientApiServer) CreateJob(
+	ctx context.Context,
+	job *api.Job,
+) (*api.Job, error) {
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, err
+	}
+	return s.ctrl.CreateJob(ctx, key, job)
+}
+
+func (s *clientApiServer) ListJobs(
+	ctx context.Context,
+	_ *emptypb.Empty,
+) (*api.JobList, error) {
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := s.ctrl.ListJobs(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &api.JobList{
+		Items: jobs,
+	}, nil
+}
+
+func (s *clientApiServer) DeleteJob(
+	ctx context.Context,
+	ref *api.JobReference,
+) (*emptypb.Empty, error) {
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ctrl.DeleteJob(ctx, key, ref.ID); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *clientApiServer) GetJobResults(
+	ctx context.Context,
+	ref *api.JobReference,
+) (*api.JobResultList, error) {
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.ctrl.JobResults(ctx, key, ref.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &api.JobResultList{
+		Items: results,
+	}, nil
+}
+
+func (s *clientApiServer) ListAnnouncementHistory(
+	ctx context.Context,
+	req *api.TimeRange,
+) (*api.AnnouncementHistory, error) {
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, err
+	}
+	since, until := timeRange(req)
+	records, err := s.ctrl.AnnouncementHistory(ctx, key, since, until)
+	if err != nil {
+		return nil, err
+	}
+	return &api.AnnouncementHistory{
+		Items: records,
+	}, nil
+}
+
+func (s *clientApiServer) ListAgents(
+	ctx context.Context,
+	req *api.ListAgentsRequest,
+) (*api.AgentList, error) {
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, err
+	}
+	agents, err := s.ctrl.ListAgents(ctx, key, req)
+	if err != nil {
+		return nil, err
+	}
+	return &api.AgentList{
+		Items: agents,
+	}, nil
+}
+
+func (s *clientApiServer) Broadcast(
+	ctx context.Context,
+	req *api.BroadcastRequest,
+) (*api.BroadcastSummary, error) {
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, err
+	}
+	if req.BroadcastID == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing broadcast ID")
+	}
+	broadcastCtx, done, err := s.trackBroadcast(ctx, req.BroadcastID)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	// Results are forwarded with the stream's context rather than the
+	// broadcast's, so that those of agents on which the instruction was
+	// canceled are still delivered.
+	return s.ctrl.Broadcast(broadcastCtx, key, s.clientAddress, req, func(result *api.BroadcastResult) {
+		if _, err := s.broadcastClient.Result(ctx, result); err != nil {
+			logrus.Errorf("Failed to forward result for broadcast %s: %v", req.BroadcastID, err)
+		}
+	})
+}
+
+// CancelBroadcast stops a broadcast previously started by this client. The
+// broadcast's context is canceled, which stops further batches, and cancels
+// the instruction on the agents it is still running on.
+func (s *clientApiServer) CancelBroadcast(
+	ctx context.Context,
+	ref *api.BroadcastReference,
+) (*emptypb.Empty, error) {
+	if _, err := s.connectedKey(); err != nil {
+		return nil, err
+	}
+	s.instructionsMu.Lock()
+	cancel, ok := s.broadcasts[ref.BroadcastID]
+	s.instructionsMu.Unlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "broadcast not found")
+	}
+	logrus.Infof("Canceling broadcast %s", ref.BroadcastID)
+	cancel()
+	return &emptypb.Empty{}, nil
+}
+
+func (s *clientApiServer) GetAuditLog(
+	ctx context.Context,
+	req *api.TimeRange,
+) (*api.AuditLog, error) {
+	key, err := s.connectedKey()
+	if err != nil {
+		return nil, err
+	}
+	since, until := timeRange(req)
+	records, err := s.ctrl.AuditLog(ctx, key, since, until)
+	if err != nil {
+		return nil, err
+	}
+	return &api.AuditLog{
+		Items: records,
+	}, nil
+}
+
+// audit records an instruction sent by the client to an agent.
+func (s *clientApiServer) audit(
+	key ssh.PublicKey,
+	action string,
+	meta *api.InstructionMeta,
+	detail string,
+	err error,
+) {
+	record := &api.AuditRecord{
+		ClientFingerprint: api.NewClientKey(key).Fingerprint,
+		AgentFingerprint:  meta.GetPeerFingerprint(),
+		Action:            action,
+		Detail:            detail,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	s.ctrl.Audit(record)
+}
+
+// auditStream is like audit, using the client's verified key.
+func (s *clientApiServer) auditStream(
+	action string,
+	meta *api.InstructionMeta,
+	detail string,
+	err error,
+) {
+	key, keyErr := s.connectedKey()
+	if keyErr != nil {
+		return
+	}
+	s.audit(key, action, meta, detail, err)
+}
+
+// connectedKey returns the client's verified key, or an error if the client
+// has not connected yet.
+func (s *clientApiServer) connectedKey() (ssh.PublicKey, error) {
+	s.keyMu.RLock()
+	defer s.keyMu.RUnlock()
+	if s.verifiedKey == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not connected")
+	}
+	return s.verifiedKey, nil
+}
+
+func timeRange(tr *api.TimeRange) (since, until time.Time) {
+	if tr.GetSince() != nil {
+		since = tr.Since.AsTime()
+	}
+	if tr.GetUntil() != nil {
+		until = tr.Until.AsTime()
+	}
+	return
+}
+
+func describeCommand(cmd *api.Command) string {
+	return strings.Join(append([]string{cmd.GetCommand()}, cmd.GetArgs()...), " ")
+}
+
+func describeScript(script *api.Script) string {
+	return fmt.Sprintf("%s script (%d bytes)", script.GetInterpreter(), len(script.GetScript()))
+}
@@ -2,120 +2,561 @@ package relay
 
 import (
 	context "context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/kralicky/post-init/pkg/api"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Controller interface {
 	AgentConnected(ctx context.Context, an *api.Announcement, client api.InstructionClient)
+	UpdateStatus(ctx context.Context, agentFingerprint string, update *api.StatusUpdate) error
 	ClientConnected(ctx context.Context, clientKey ssh.PublicKey)
-	Watch(ctx context.Context, clientKey ssh.PublicKey, req *api.WatchRequest) (<-chan *api.Announcement, error)
+	AgentDisconnecting(ctx context.Context, agentFingerprint string, reason string)
+	Watch(ctx context.Context, clientKey ssh.PublicKey, req *api.WatchRequest) (<-chan *api.WatchEvent, error)
+	Unwatch(ctx context.Context, clientKey ssh.PublicKey, watchID string) error
 	Lookup(ctx context.Context, fingerprint string) (api.InstructionClient, error)
+	ListAgents(ctx context.Context, clientKey ssh.PublicKey, req *api.ListAgentsRequest) ([]*api.AgentInfo, error)
+	Broadcast(ctx context.Context, clientKey ssh.PublicKey, clientAddress string, req *api.BroadcastRequest, callback func(*api.BroadcastResult)) (*api.BroadcastSummary, error)
+	OpenOutputStream(ctx context.Context, agentFingerprint string, id string) (<-chan *api.OutputEvent, error)
+	CloseOutputStream(id string)
+	WriteOutput(ctx context.Context, agentFingerprint string, ev *api.OutputEvent) error
+	CreateJob(ctx context.Context, clientKey ssh.PublicKey, job *api.Job) (*api.Job, error)
+	ListJobs(ctx context.Context, clientKey ssh.PublicKey) ([]*api.Job, error)
+	DeleteJob(ctx context.Context, clientKey ssh.PublicKey, id string) error
+	JobResults(ctx context.Context, clientKey ssh.PublicKey, id string) ([]*api.JobResult, error)
+	AnnouncementHistory(ctx context.Context, clientKey ssh.PublicKey, since, until time.Time) ([]*api.AnnouncementRecord, error)
+	Audit(record *api.AuditRecord)
+	AuditLog(ctx context.Context, clientKey ssh.PublicKey, since, until time.Time) ([]*api.AuditRecord, error)
+}
+
+type ControllerOptions struct {
+	store Store
+}
+
+type ControllerOption func(*ControllerOptions)
+
+func (o *ControllerOptions) Apply(opts ...ControllerOption) {
+	for _, op := range opts {
+		op(o)
+	}
+}
+
+func WithStore(store Store) ControllerOption {
+	return func(o *ControllerOptions) {
+		o.store = store
+	}
 }
 
 type activeAgent struct {
+	ctx          context.Context
 	client       api.InstructionClient
 	announcement *api.Announcement
+	record       *api.AnnouncementRecord
+	lastActivity time.Time
+	// Set by AgentDisconnecting
+	disconnectReason string
 }
 
 type activeWatch struct {
-	ch  chan *api.Announcement
+	id  string
+	ch  chan *api.WatchEvent
 	req *api.WatchRequest
 }
 
+type outputStream struct {
+	mu     sync.Mutex
+	agent  string
+	ch     chan *api.OutputEvent
+	closed bool
+}
+
 type controller struct {
 	mu            sync.Mutex
 	activeAgents  map[string]activeAgent
 	activeClients map[string]ssh.PublicKey
-	activeWatches map[string]activeWatch
+	// Keys are client key fingerprints, then watch IDs
+	activeWatches map[string]map[string]*activeWatch
+	outputStreams map[string]*outputStream
+	store         Store
+	// Keys are "<job id>/<agent fingerprint>"
+	runningJobs map[string]struct{}
+	// Fingerprints of agents which have announced recently, and when they were
+	// last connected (see forgetAgentsAfter)
+	knownAgents map[string]time.Time
 }
 
-func NewController() Controller {
+// Agents which reconnect within this long of disconnecting are reported to
+// watches as Reannounced rather than Connected. Agents which have not been
+// connected for longer are forgotten, so that the relay does not remember
+// every agent it has ever seen.
+const forgetAgentsAfter = 24 * time.Hour
+
+// watchBufferSize is the number of events which can be waiting to be sent to
+// a client for each of its watches. A watch which falls further behind than
+// this is closed (see sendWatchEvent).
+const watchBufferSize = 256
+
+func NewController(opts ...ControllerOption) Controller {
+	options := ControllerOptions{}
+	options.Apply(opts...)
+	if options.store == nil {
+		options.store = NewMemoryStore()
+	}
 	return &controller{
 		activeAgents:  make(map[string]activeAgent),
 		activeClients: make(map[string]ssh.PublicKey),
-		activeWatches: make(map[string]activeWatch),
+		activeWatches: make(map[string]map[string]*activeWatch),
+		outputStreams: make(map[string]*outputStream),
+		store:         options.store,
+		runningJobs:   make(map[string]struct{}),
+		knownAgents:   make(map[string]time.Time),
 	}
 }
 
 func (c *controller) AgentConnected(ctx context.Context, an *api.Announcement, client api.InstructionClient) {
-	logrus.Info("Agent connected: " + string(an.PreferredHostPublicKey))
+	fp, _ := an.Fingerprint() // error already checked in Announce
+	logrus.Info("Agent connected: " + fp)
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	fp, _ := an.Fingerprint() // error already checked in Announce
-	c.activeAgents[fp] = activeAgent{
-		client:       client,
+	record := &api.AnnouncementRecord{
+		Fingerprint:  fp,
+		Announcement: an,
+		ConnectTime:  timestamppb.Now(),
+	}
+	if err := c.store.PutAnnouncement(record); err != nil {
+		logrus.WithError(err).Error("Failed to store announcement")
+	}
+	agent := activeAgent{
+		ctx: ctx,
+		client: newCancelingClient(newAuthorizingClient(client, func() *api.Announcement {
+			return c.currentAnnouncement(fp)
+		})),
 		announcement: an,
+		record:       record,
+		lastActivity: record.ConnectTime.AsTime(),
 	}
-	for _, authorizedKey := range an.AuthorizedKeys {
-		if watch, ok := c.activeWatches[authorizedKey.Fingerprint]; ok {
-			if an.FilterAccepts(watch.req.Filter) {
-				logrus.Info("Handling late-join")
-				watch.ch <- an
-			} else {
-				logrus.Info("Filtered out late-join")
-			}
-		}
+	c.activeAgents[fp] = agent
+	eventType := api.WatchEventType_Connected
+	if _, ok := c.knownAgents[fp]; ok {
+		eventType = api.WatchEventType_Reannounced
 	}
+	c.forgetInactiveAgents(record.ConnectTime.AsTime())
+	c.knownAgents[fp] = record.ConnectTime.AsTime()
+	c.notifyWatches(an, func(*activeWatch) *api.WatchEvent {
+		return &api.WatchEvent{
+			Type:         eventType,
+			Fingerprint:  fp,
+			Announcement: an,
+			Time:         record.ConnectTime,
+		}
+	})
+	c.runPendingJobs(ctx, fp, an, agent.client)
 	go func() {
 		<-ctx.Done()
 		c.mu.Lock()
 		defer c.mu.Unlock()
+		record.DisconnectTime = timestamppb.Now()
+		if err := c.store.PutAnnouncement(record); err != nil {
+			logrus.WithError(err).Error("Failed to store announcement")
+		}
+		agent := c.activeAgents[fp]
+		if agent.ctx != ctx {
+			// The agent has already reconnected on a new stream
+			return
+		}
 		delete(c.activeAgents, fp)
+		c.knownAgents[fp] = record.DisconnectTime.AsTime()
+		reason := agent.disconnectReason
+		if reason == "" {
+			reason = "connection lost"
+		}
+		logrus.WithField("reason", reason).Info("Agent disconnected")
+		c.notifyWatches(agent.announcement, func(*activeWatch) *api.WatchEvent {
+			return &api.WatchEvent{
+				Type:         api.WatchEventType_Disconnected,
+				Fingerprint:  fp,
+				Announcement: agent.announcement,
+				Time:         record.DisconnectTime,
+				Reason:       reason,
+			}
+		})
 	}()
 }
 
+// AgentDisconnecting records why an agent's stream is about to end, to be
+// included in the Disconnected events sent to watches. It has no effect if
+// the agent has since reconnected on a different stream.
+func (c *controller) AgentDisconnecting(ctx context.Context, agentFingerprint string, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if agent, ok := c.activeAgents[agentFingerprint]; ok && agent.ctx == ctx {
+		agent.disconnectReason = reason
+		c.activeAgents[agentFingerprint] = agent
+	}
+}
+
+// UpdateStatus applies a status update to the agent's announcement, and sends
+// StatusChanged events to watches which match the updated announcement.
+// Pending jobs which now match are run on the agent.
+func (c *controller) UpdateStatus(ctx context.Context, agentFingerprint string, update *api.StatusUpdate) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	agent, ok := c.activeAgents[agentFingerprint]
+	if !ok {
+		return status.Error(codes.NotFound, "agent not found")
+	}
+	prev := agent.announcement
+	// Announcements may be in use outside of the lock, so they are replaced
+	// instead of modified.
+	an := proto.Clone(prev).(*api.Announcement)
+	if update.CloudInit != nil {
+		an.CloudInit = update.CloudInit
+	}
+	logrus.WithField("cloud-init", an.GetCloudInit().GetState()).Info("Agent status updated")
+	agent.announcement = an
+	agent.record.Announcement = an
+	agent.lastActivity = time.Now()
+	c.activeAgents[agentFingerprint] = agent
+	if err := c.store.PutAnnouncement(agent.record); err != nil {
+		logrus.WithError(err).Error("Failed to store announcement")
+	}
+	now := timestamppb.Now()
+	c.notifyWatches(an, func(watch *activeWatch) *api.WatchEvent {
+		return &api.WatchEvent{
+			Type:              api.WatchEventType_StatusChanged,
+			Fingerprint:       agentFingerprint,
+			Announcement:      an,
+			Time:              now,
+			PreviouslyMatched: watch.req.Selector().Accepts(prev),
+		}
+	})
+	c.runPendingJobs(agent.ctx, agentFingerprint, an, agent.client)
+	return nil
+}
+
+// notifyWatches sends an event to each watch which accepts the announcement,
+// if the watching client's key is authorized on the agent. The controller lock
+// must be held.
+func (c *controller) notifyWatches(an *api.Announcement, event func(*activeWatch) *api.WatchEvent) {
+	for fp, watches := range c.activeWatches {
+		clientKey, ok := c.activeClients[fp]
+		if !ok || !an.Authorizes(api.NewClientKey(clientKey)) {
+			continue
+		}
+		for _, watch := range watches {
+			if !watch.req.Selector().Accepts(an) {
+				continue
+			}
+			ev := event(watch)
+			ev.WatchID = watch.id
+			c.sendWatchEvent(fp, watch, ev)
+		}
+	}
+}
+
+// sendWatchEvent queues an event for the client without blocking. If the
+// watch's buffer is full, the client is not keeping up, and the watch is
+// closed instead: the event is replaced by a Closed event, which takes the
+// slot kept free for it, and the watch's channel is closed. Events are never
+// dropped from a watch which stays open, so that clients can rely on seeing
+// an agent's Connected event before its Disconnected event. The controller
+// lock must be held.
+func (c *controller) sendWatchEvent(clientFp string, watch *activeWatch, ev *api.WatchEvent) {
+	if len(watch.ch) < cap(watch.ch)-1 {
+		watch.ch <- ev
+		return
+	}
+	logrus.WithField("watch", watch.id).Warn("Client is not keeping up with watch events, closing watch")
+	watch.ch <- &api.WatchEvent{
+		Type:    api.WatchEventType_Closed,
+		Time:    timestamppb.Now(),
+		Reason:  "too many events waiting to be sent",
+		WatchID: watch.id,
+	}
+	c.removeWatch(clientFp, watch.id)
+}
+
+// removeWatch closes the watch's channel and forgets it. The controller lock
+// must be held.
+func (c *controller) removeWatch(clientFp, watchID string) {
+	watch, ok := c.activeWatches[clientFp][watchID]
+	if !ok {
+		return
+	}
+	close(watch.ch)
+	delete(c.activeWatches[clientFp], watchID)
+	if len(c.activeWatches[clientFp]) == 0 {
+		delete(c.activeWatches, clientFp)
+	}
+}
+
+// forgetInactiveAgents removes agents which have not been connected within
+// forgetAgentsAfter of now from knownAgents. The controller lock must be
+// held.
+func (c *controller) forgetInactiveAgents(now time.Time) {
+	for fp, lastSeen := range c.knownAgents {
+		if _, ok := c.activeAgents[fp]; ok {
+			continue
+		}
+		if now.Sub(lastSeen) > forgetAgentsAfter {
+			delete(c.knownAgents, fp)
+		}
+	}
+}
+
 func (c *controller) ClientConnected(ctx context.Context, clientKey ssh.PublicKey) {
 	logrus.Info("Client connected")
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	fp := ssh.FingerprintSHA256(clientKey)
+	fp := api.NewClientKey(clientKey).Fingerprint
 	c.activeClients[fp] = clientKey
 	go func() {
 		<-ctx.Done()
 		c.mu.Lock()
 		defer c.mu.Unlock()
+		for _, watch := range c.activeWatches[fp] {
+			close(watch.ch)
+		}
 		delete(c.activeWatches, fp)
 		delete(c.activeClients, fp)
 	}()
 }
 
-func (c *controller) Watch(ctx context.Context, clientKey ssh.PublicKey, req *api.WatchRequest) (<-chan *api.Announcement, error) {
+func (c *controller) Watch(ctx context.Context, clientKey ssh.PublicKey, req *api.WatchRequest) (<-chan *api.WatchEvent, error) {
 	logrus.Info("Watch requested by client")
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	fp := ssh.FingerprintSHA256(clientKey)
+	client := api.NewClientKey(clientKey)
+	fp := client.Fingerprint
 	if _, ok := c.activeClients[fp]; !ok {
 		return nil, status.Error(codes.PermissionDenied, "key is not authorized")
 	}
-	if _, ok := c.activeWatches[fp]; ok {
-		return nil, status.Error(codes.AlreadyExists, "already watching")
+	if req.WatchID == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing watch ID")
 	}
-	ch := make(chan *api.Announcement, 256)
-	c.activeWatches[fp] = activeWatch{
-		ch:  ch,
-		req: req,
+	if _, ok := c.activeWatches[fp][req.WatchID]; ok {
+		return nil, status.Error(codes.AlreadyExists, "watch already exists")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	// late join; all other notifications happen upon receiving announcements
-	for _, v := range c.activeAgents {
-		if v.announcement.FilterAccepts(req.Filter) {
-			logrus.Info("Handling late-join")
-			ch <- v.announcement
+	// late join; all other events are sent as agents connect and change
+	late := []*api.WatchEvent{}
+	for agentFp, v := range c.activeAgents {
+		if v.announcement.Authorizes(client) && req.Selector().Accepts(v.announcement) {
+			late = append(late, &api.WatchEvent{
+				Type:         api.WatchEventType_Connected,
+				Fingerprint:  agentFp,
+				Announcement: v.announcement,
+				Time:         v.record.ConnectTime,
+				WatchID:      req.WatchID,
+			})
 		}
 	}
+	if len(late) > 0 {
+		logrus.Infof("Handling late-join for %d agents", len(late))
+	}
+	// The buffer has room for the late-join events on top of the usual
+	// limit, so that they can never cause the watch to be closed.
+	ch := make(chan *api.WatchEvent, len(late)+watchBufferSize)
+	for _, ev := range late {
+		ch <- ev
+	}
+	if c.activeWatches[fp] == nil {
+		c.activeWatches[fp] = make(map[string]*activeWatch)
+	}
+	c.activeWatches[fp][req.WatchID] = &activeWatch{
+		id:  req.WatchID,
+		ch:  ch,
+		req: req,
+	}
 	return ch, nil
 }
 
+// Unwatch stops the client's watch with the given ID. The watch's channel is
+// closed, and no further events are sent for it.
+func (c *controller) Unwatch(ctx context.Context, clientKey ssh.PublicKey, watchID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fp := api.NewClientKey(clientKey).Fingerprint
+	if _, ok := c.activeWatches[fp][watchID]; !ok {
+		return status.Error(codes.NotFound, "watch not found")
+	}
+	c.removeWatch(fp, watchID)
+	return nil
+}
+
 func (c *controller) Lookup(ctx context.Context, fingerprint string) (api.InstructionClient, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if an, ok := c.activeAgents[fingerprint]; ok {
+		// Instructions are only sent to agents after looking them up
+		c.touch(fingerprint)
 		return an.client, nil
 	}
 	return nil, status.Error(codes.NotFound, "not found")
 }
+
+// currentAnnouncement returns the latest announcement of the connected agent
+// with the given fingerprint, or nil if it is not connected.
+func (c *controller) currentAnnouncement(fingerprint string) *api.Announcement {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if agent, ok := c.activeAgents[fingerprint]; ok {
+		return agent.announcement
+	}
+	return nil
+}
+
+// ListAgents returns the currently connected agents on which the client's key
+// is authorized, and which match the request's selector if it has one.
+func (c *controller) ListAgents(ctx context.Context, clientKey ssh.PublicKey, req *api.ListAgentsRequest) ([]*api.AgentInfo, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	selector := req.Selector()
+	client := api.NewClientKey(clientKey)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	agents := []*api.AgentInfo{}
+	for agentFp, agent := range c.activeAgents {
+		if !agent.announcement.Authorizes(client) {
+			continue
+		}
+		if selector != nil && !selector.Accepts(agent.announcement) {
+			continue
+		}
+		agents = append(agents, &api.AgentInfo{
+			Fingerprint:  agentFp,
+			Announcement: agent.announcement,
+			ConnectTime:  agent.record.ConnectTime,
+			LastActivity: timestamppb.New(agent.lastActivity),
+		})
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		ti, tj := agents[i].ConnectTime.AsTime(), agents[j].ConnectTime.AsTime()
+		if ti.Equal(tj) {
+			return agents[i].Fingerprint < agents[j].Fingerprint
+		}
+		return ti.Before(tj)
+	})
+	return agents, nil
+}
+
+// touch updates the last activity time of an agent. The controller lock must
+// be held.
+func (c *controller) touch(fingerprint string) {
+	if agent, ok := c.activeAgents[fingerprint]; ok {
+		agent.lastActivity = time.Now()
+		c.activeAgents[fingerprint] = agent
+	}
+}
+
+// OpenOutputStream registers a stream which the agent with the given
+// fingerprint can write output events to. Events are delivered on the returned
+// channel in the order they were written, until CloseOutputStream is called.
+func (c *controller) OpenOutputStream(ctx context.Context, agentFingerprint string, id string) (<-chan *api.OutputEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing stream ID")
+	}
+	if _, ok := c.outputStreams[id]; ok {
+		return nil, status.Error(codes.AlreadyExists, "stream already exists")
+	}
+	ch := make(chan *api.OutputEvent, 256)
+	c.outputStreams[id] = &outputStream{
+		agent: agentFingerprint,
+		ch:    ch,
+	}
+	return ch, nil
+}
+
+func (c *controller) CloseOutputStream(id string) {
+	c.mu.Lock()
+	s, ok := c.outputStreams[id]
+	delete(c.outputStreams, id)
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	close(s.ch)
+}
+
+func (c *controller) WriteOutput(ctx context.Context, agentFingerprint string, ev *api.OutputEvent) error {
+	c.mu.Lock()
+	s, ok := c.outputStreams[ev.StreamID]
+	c.touch(agentFingerprint)
+	c.mu.Unlock()
+	if !ok {
+		return status.Error(codes.NotFound, "stream not found")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.agent != agentFingerprint {
+		return status.Error(codes.PermissionDenied, "stream belongs to a different agent")
+	}
+	if s.closed {
+		return status.Error(codes.FailedPrecondition, "stream is closed")
+	}
+	select {
+	case s.ch <- ev:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AnnouncementHistory returns records of agents which connected within the
+// given time range, and on which the client's key is authorized.
+func (c *controller) AnnouncementHistory(ctx context.Context, clientKey ssh.PublicKey, since, until time.Time) ([]*api.AnnouncementRecord, error) {
+	records, err := c.store.ListAnnouncements(since, until)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	client := api.NewClientKey(clientKey)
+	visible := []*api.AnnouncementRecord{}
+	for _, record := range records {
+		if record.Announcement.Authorizes(client) {
+			visible = append(visible, record)
+		}
+	}
+	return visible, nil
+}
+
+func (c *controller) Audit(record *api.AuditRecord) {
+	if record.Time == nil {
+		record.Time = timestamppb.Now()
+	}
+	if err := c.store.PutAuditRecord(record); err != nil {
+		logrus.WithError(err).Error("Failed to store audit record")
+	}
+}
+
+// AuditLog returns audit records of instructions sent by the client's key
+// within the given time range.
+func (c *controller) AuditLog(ctx context.Context, clientKey ssh.PublicKey, since, until time.Time) ([]*api.AuditRecord, error) {
+	records, err := c.store.ListAuditRecords(since, until)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	fp := api.NewClientKey(clientKey).Fingerprint
+	own := []*api.AuditRecord{}
+	for _, record := range records {
+		if record.ClientFingerprint == fp {
+			own = append(own, record)
+		}
+	}
+	return own, nil
+}
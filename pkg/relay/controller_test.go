@@ -5,6 +5,8 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/kralicky/post-init/pkg/api"
@@ -12,6 +14,11 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 var _ = Describe("Controller", Ordered, func() {
@@ -28,7 +35,7 @@ var _ = Describe("Controller", Ordered, func() {
 		mockClient = mock_api.NewMockInstructionClient(mockCtrl)
 		mockClient.EXPECT().
 			Command(gomock.Any(), gomock.Any()).
-			DoAndReturn(func(_ context.Context, in *api.CommandRequest) (*api.CommandResponse, error) {
+			DoAndReturn(func(_ context.Context, in *api.CommandRequest, _ ...grpc.CallOption) (*api.CommandResponse, error) {
 				if in.Command.Command != "echo" {
 					panic("invalid test")
 				}
@@ -82,6 +89,137 @@ var _ = Describe("Controller", Ordered, func() {
 			}, mockClient)
 		})
 	})
+	When("a client watches with an expression", func() {
+		It("should reject invalid expressions", func() {
+			_, err := c.Watch(context.Background(), pubKey, &api.WatchRequest{
+				WatchID: "invalid",
+				Expression: &api.Expression{
+					Expr: &api.Expression_Hostname{
+						Hostname: &api.StringMatch{
+							Match: &api.StringMatch_Regex{Regex: "("},
+						},
+					},
+				},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+		It("should notify the client of matching agents", func() {
+			ch, err := c.Watch(context.Background(), pubKey, &api.WatchRequest{
+				WatchID: "watch",
+				Expression: &api.Expression{
+					Expr: &api.Expression_And{
+						And: &api.ExpressionList{
+							Items: []*api.Expression{
+								{
+									Expr: &api.Expression_AuthorizedKey{
+										AuthorizedKey: ssh.FingerprintSHA256(pubKey),
+									},
+								},
+								{
+									Expr: &api.Expression_Not{
+										Not: &api.Expression{
+											Expr: &api.Expression_Label{
+												Label: &api.LabelMatch{Key: "role"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(ch).Should(Receive())
+		})
+	})
+	When("a client requests the announcement history", func() {
+		It("should include agents on which the client's key is authorized", func() {
+			records, err := c.AnnouncementHistory(context.Background(), pubKey, time.Time{}, time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].DisconnectTime).To(BeNil())
+
+			otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+			otherKey, _ := ssh.NewPublicKey(otherPub)
+			records, err = c.AnnouncementHistory(context.Background(), otherKey, time.Time{}, time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(BeEmpty())
+		})
+	})
+	When("an agent writes output to a stream", func() {
+		It("should deliver the output in order", func() {
+			ch, err := c.OpenOutputStream(context.Background(), "agent", "stream")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = c.OpenOutputStream(context.Background(), "agent", "stream")
+			Expect(err).To(HaveOccurred())
+
+			for i := uint64(1); i <= 3; i++ {
+				Expect(c.WriteOutput(context.Background(), "agent", &api.OutputEvent{
+					StreamID: "stream",
+					Sequence: i,
+				})).To(Succeed())
+			}
+			c.CloseOutputStream("stream")
+
+			var seq []uint64
+			for ev := range ch {
+				seq = append(seq, ev.Sequence)
+			}
+			Expect(seq).To(Equal([]uint64{1, 2, 3}))
+		})
+		It("should reject output from other agents", func() {
+			_, err := c.OpenOutputStream(context.Background(), "agent", "stream2")
+			Expect(err).NotTo(HaveOccurred())
+			defer c.CloseOutputStream("stream2")
+
+			Expect(c.WriteOutput(context.Background(), "other-agent", &api.OutputEvent{
+				StreamID: "stream2",
+			})).NotTo(Succeed())
+		})
+		It("should reject output for unknown streams", func() {
+			Expect(c.WriteOutput(context.Background(), "agent", &api.OutputEvent{
+				StreamID: "stream",
+			})).NotTo(Succeed())
+		})
+	})
+	When("a client creates a job", func() {
+		It("should run the job on matching agents and store the results", func() {
+			job, err := c.CreateJob(context.Background(), pubKey, &api.Job{
+				Filter: &api.BasicFilter{
+					Operator:         api.Operator_Or,
+					HasAuthorizedKey: ssh.FingerprintSHA256(pubKey),
+				},
+				Steps: []*api.JobStep{
+					{
+						Step: &api.JobStep_Command{
+							Command: &api.Command{
+								Command: "echo",
+								Args:    []string{"hello"},
+							},
+						},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.ID).NotTo(BeEmpty())
+			Expect(job.Owner).To(Equal(ssh.FingerprintSHA256(pubKey)))
+
+			Eventually(func() ([]*api.JobResult, error) {
+				return c.JobResults(context.Background(), pubKey, job.ID)
+			}).Should(HaveLen(1))
+			results, _ := c.JobResults(context.Background(), pubKey, job.ID)
+			Expect(results[0].Steps).To(HaveLen(1))
+			Expect(results[0].Steps[0].GetCommand().Stdout).To(Equal("[hello]\n"))
+		})
+		It("should reject invalid jobs", func() {
+			_, err := c.CreateJob(context.Background(), pubKey, &api.Job{
+				Filter: &api.BasicFilter{},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
 	When("a client disconnects", func() {
 		It("should remove the client from the active clients", func() {
 			clientCancel()
@@ -109,3 +247,1126 @@ var _ = Describe("Controller", Ordered, func() {
 		})
 	})
 })
+
+var _ = Describe("Watch Events", func() {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	pubKey, _ := ssh.NewPublicKey(pub)
+	hostPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	hostKey, _ := ssh.NewPublicKey(hostPub)
+	fp := ssh.FingerprintSHA256(hostKey)
+	newAnnouncement := func(state api.CloudInitState) *api.Announcement {
+		return &api.Announcement{
+			PreferredHostPublicKey: hostKey.Marshal(),
+			AuthorizedKeys: []*api.AuthorizedKey{
+				{User: "root", Fingerprint: ssh.FingerprintSHA256(pubKey)},
+				{User: "ubuntu", Fingerprint: ssh.FingerprintSHA256(pubKey)},
+			},
+			CloudInit: &api.CloudInitStatus{State: state},
+		}
+	}
+	var c *controller
+	var ctx context.Context
+	var cancel context.CancelFunc
+	BeforeEach(func() {
+		c = NewController().(*controller)
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		c.ClientConnected(ctx, pubKey)
+	})
+
+	It("should send status changes to watches which match the updated announcement", func() {
+		ch, err := c.Watch(ctx, pubKey, &api.WatchRequest{
+			WatchID: "watch",
+			Expression: &api.Expression{
+				Expr: &api.Expression_CloudInit{CloudInit: api.CloudInitState_Error},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		c.AgentConnected(ctx, newAnnouncement(api.CloudInitState_Running), nil)
+		Consistently(ch, 100*time.Millisecond).ShouldNot(Receive())
+
+		Expect(c.UpdateStatus(ctx, fp, &api.StatusUpdate{
+			CloudInit: &api.CloudInitStatus{
+				State:  api.CloudInitState_Error,
+				Errors: []string{"module failed"},
+			},
+		})).To(Succeed())
+		var ev *api.WatchEvent
+		Eventually(ch).Should(Receive(&ev))
+		Expect(ev.Type).To(Equal(api.WatchEventType_StatusChanged))
+		Expect(ev.Fingerprint).To(Equal(fp))
+		Expect(ev.PreviouslyMatched).To(BeFalse())
+		Expect(ev.Announcement.CloudInit.Errors).To(Equal([]string{"module failed"}))
+		// The key is authorized for two users, but only one event is sent
+		Consistently(ch, 100*time.Millisecond).ShouldNot(Receive())
+
+		records, err := c.AnnouncementHistory(ctx, pubKey, time.Time{}, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Announcement.CloudInit.State).To(Equal(api.CloudInitState_Error))
+
+		Expect(c.UpdateStatus(ctx, fp, &api.StatusUpdate{
+			CloudInit: &api.CloudInitStatus{State: api.CloudInitState_Error},
+		})).To(Succeed())
+		Eventually(ch).Should(Receive(&ev))
+		Expect(ev.PreviouslyMatched).To(BeTrue())
+	})
+	It("should send disconnect and re-announce events", func() {
+		ch, err := c.Watch(ctx, pubKey, &api.WatchRequest{
+			WatchID: "watch",
+			Expression: &api.Expression{
+				Expr: &api.Expression_And{And: &api.ExpressionList{}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		agentCtx, agentCancel := context.WithCancel(ctx)
+		c.AgentConnected(agentCtx, newAnnouncement(api.CloudInitState_Done), nil)
+		var ev *api.WatchEvent
+		Eventually(ch).Should(Receive(&ev))
+		Expect(ev.Type).To(Equal(api.WatchEventType_Connected))
+
+		c.AgentDisconnecting(agentCtx, fp, "agent closed the connection")
+		agentCancel()
+		Eventually(ch).Should(Receive(&ev))
+		Expect(ev.Type).To(Equal(api.WatchEventType_Disconnected))
+		Expect(ev.Reason).To(Equal("agent closed the connection"))
+		Expect(ev.Announcement).NotTo(BeNil())
+
+		agentCtx, agentCancel = context.WithCancel(ctx)
+		c.AgentConnected(agentCtx, newAnnouncement(api.CloudInitState_Done), nil)
+		Eventually(ch).Should(Receive(&ev))
+		Expect(ev.Type).To(Equal(api.WatchEventType_Reannounced))
+
+		// A stream which ends after the agent has reconnected on a new stream
+		// does not disconnect the agent
+		newCtx, newCancel := context.WithCancel(ctx)
+		defer newCancel()
+		c.AgentConnected(newCtx, newAnnouncement(api.CloudInitState_Done), nil)
+		Eventually(ch).Should(Receive(&ev))
+		Expect(ev.Type).To(Equal(api.WatchEventType_Reannounced))
+		agentCancel()
+		Consistently(ch, 100*time.Millisecond).ShouldNot(Receive())
+
+		newCancel()
+		Eventually(ch).Should(Receive(&ev))
+		Expect(ev.Type).To(Equal(api.WatchEventType_Disconnected))
+		Expect(ev.Reason).To(Equal("connection lost"))
+	})
+	It("should route events to each of the client's watches", func() {
+		failed, err := c.Watch(ctx, pubKey, &api.WatchRequest{
+			WatchID: "errors",
+			Expression: &api.Expression{
+				Expr: &api.Expression_CloudInit{CloudInit: api.CloudInitState_Error},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		all, err := c.Watch(ctx, pubKey, &api.WatchRequest{
+			WatchID: "all",
+			Expression: &api.Expression{
+				Expr: &api.Expression_And{And: &api.ExpressionList{}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.Watch(ctx, pubKey, &api.WatchRequest{
+			WatchID: "all",
+			Expression: &api.Expression{
+				Expr: &api.Expression_And{And: &api.ExpressionList{}},
+			},
+		})
+		Expect(err).To(HaveOccurred())
+
+		c.AgentConnected(ctx, newAnnouncement(api.CloudInitState_Done), nil)
+		var ev *api.WatchEvent
+		Eventually(all).Should(Receive(&ev))
+		Expect(ev.WatchID).To(Equal("all"))
+		Expect(ev.Type).To(Equal(api.WatchEventType_Connected))
+		Consistently(failed, 100*time.Millisecond).ShouldNot(Receive())
+
+		Expect(c.UpdateStatus(ctx, fp, &api.StatusUpdate{
+			CloudInit: &api.CloudInitStatus{State: api.CloudInitState_Error},
+		})).To(Succeed())
+		Eventually(failed).Should(Receive(&ev))
+		Expect(ev.WatchID).To(Equal("errors"))
+		Expect(ev.PreviouslyMatched).To(BeFalse())
+		Eventually(all).Should(Receive(&ev))
+		Expect(ev.WatchID).To(Equal("all"))
+		Expect(ev.PreviouslyMatched).To(BeTrue())
+
+		Expect(c.Unwatch(ctx, pubKey, "errors")).To(Succeed())
+		Eventually(failed).Should(BeClosed())
+		Expect(c.Unwatch(ctx, pubKey, "errors")).NotTo(Succeed())
+		Expect(c.UpdateStatus(ctx, fp, &api.StatusUpdate{
+			CloudInit: &api.CloudInitStatus{State: api.CloudInitState_Error},
+		})).To(Succeed())
+		Eventually(all).Should(Receive(&ev))
+		Expect(ev.WatchID).To(Equal("all"))
+
+		// The ID of a stopped watch can be reused
+		_, err = c.Watch(ctx, pubKey, &api.WatchRequest{
+			WatchID: "errors",
+			Expression: &api.Expression{
+				Expr: &api.Expression_CloudInit{CloudInit: api.CloudInitState_Error},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+	It("should require a watch ID", func() {
+		_, err := c.Watch(ctx, pubKey, &api.WatchRequest{
+			Expression: &api.Expression{
+				Expr: &api.Expression_And{And: &api.ExpressionList{}},
+			},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+	It("should close watches when the client disconnects", func() {
+		ch, err := c.Watch(ctx, pubKey, &api.WatchRequest{
+			WatchID: "watch",
+			Expression: &api.Expression{
+				Expr: &api.Expression_And{And: &api.ExpressionList{}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		cancel()
+		Eventually(ch).Should(BeClosed())
+	})
+	It("should reject updates from unknown agents", func() {
+		Expect(c.UpdateStatus(context.Background(), "SHA256:unknown", &api.StatusUpdate{})).NotTo(Succeed())
+	})
+	It("should close watches which fall too far behind", func() {
+		ch, err := c.Watch(ctx, pubKey, &api.WatchRequest{
+			WatchID: "watch",
+			Expression: &api.Expression{
+				Expr: &api.Expression_And{And: &api.ExpressionList{}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		c.AgentConnected(ctx, newAnnouncement(api.CloudInitState_Running), nil)
+		// Nothing reads from the watch, but status updates are not blocked
+		for i := 0; i < 2*watchBufferSize; i++ {
+			Expect(c.UpdateStatus(ctx, fp, &api.StatusUpdate{
+				CloudInit: &api.CloudInitStatus{State: api.CloudInitState_Running},
+			})).To(Succeed())
+		}
+
+		events := []*api.WatchEvent{}
+		for ev := range ch {
+			events = append(events, ev)
+		}
+		Expect(events).To(HaveLen(watchBufferSize))
+		Expect(events[0].Type).To(Equal(api.WatchEventType_Connected))
+		for _, ev := range events[1 : len(events)-1] {
+			Expect(ev.Type).To(Equal(api.WatchEventType_StatusChanged))
+		}
+		closed := events[len(events)-1]
+		Expect(closed.Type).To(Equal(api.WatchEventType_Closed))
+		Expect(closed.WatchID).To(Equal("watch"))
+		Expect(closed.Reason).NotTo(BeEmpty())
+		Expect(c.Unwatch(ctx, pubKey, "watch")).NotTo(Succeed())
+	})
+	It("should not close watches because of late-join events", func() {
+		for i := 0; i < 2*watchBufferSize; i++ {
+			agentPub, _, _ := ed25519.GenerateKey(rand.Reader)
+			agentKey, _ := ssh.NewPublicKey(agentPub)
+			an := newAnnouncement(api.CloudInitState_Done)
+			an.PreferredHostPublicKey = agentKey.Marshal()
+			c.AgentConnected(ctx, an, nil)
+		}
+		ch, err := c.Watch(ctx, pubKey, &api.WatchRequest{
+			WatchID: "watch",
+			Expression: &api.Expression{
+				Expr: &api.Expression_And{And: &api.ExpressionList{}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		for i := 0; i < 2*watchBufferSize; i++ {
+			var ev *api.WatchEvent
+			Expect(ch).To(Receive(&ev))
+			Expect(ev.Type).To(Equal(api.WatchEventType_Connected))
+		}
+		Expect(c.Unwatch(ctx, pubKey, "watch")).To(Succeed())
+	})
+	It("should forget agents which have not been connected for a long time", func() {
+		agentCtx, agentCancel := context.WithCancel(ctx)
+		c.AgentConnected(agentCtx, newAnnouncement(api.CloudInitState_Done), nil)
+		agentCancel()
+		Eventually(func() bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			_, ok := c.activeAgents[fp]
+			return ok
+		}).Should(BeFalse())
+
+		ch, err := c.Watch(ctx, pubKey, &api.WatchRequest{
+			WatchID: "watch",
+			Expression: &api.Expression{
+				Expr: &api.Expression_And{And: &api.ExpressionList{}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		c.mu.Lock()
+		Expect(c.knownAgents).To(HaveKey(fp))
+		c.knownAgents[fp] = time.Now().Add(-forgetAgentsAfter - time.Minute)
+		c.mu.Unlock()
+
+		// Another agent connecting prunes the forgotten agent
+		otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+		otherKey, _ := ssh.NewPublicKey(otherPub)
+		other := newAnnouncement(api.CloudInitState_Done)
+		other.PreferredHostPublicKey = otherKey.Marshal()
+		c.AgentConnected(ctx, other, nil)
+		c.mu.Lock()
+		Expect(c.knownAgents).NotTo(HaveKey(fp))
+		Expect(c.knownAgents).To(HaveLen(1))
+		c.mu.Unlock()
+		var ev *api.WatchEvent
+		Eventually(ch).Should(Receive(&ev))
+
+		c.AgentConnected(ctx, newAnnouncement(api.CloudInitState_Done), nil)
+		Eventually(ch).Should(Receive(&ev))
+		Expect(ev.Fingerprint).To(Equal(fp))
+		Expect(ev.Type).To(Equal(api.WatchEventType_Connected))
+	})
+})
+
+var _ = Describe("Listing Agents", func() {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	pubKey, _ := ssh.NewPublicKey(pub)
+	newHostKey := func() ssh.PublicKey {
+		hostPub, _, _ := ed25519.GenerateKey(rand.Reader)
+		hostKey, _ := ssh.NewPublicKey(hostPub)
+		return hostKey
+	}
+
+	It("should list visible agents matching the selector", func() {
+		c := NewController().(*controller)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		web, db, other := newHostKey(), newHostKey(), newHostKey()
+		for _, an := range []*api.Announcement{
+			{
+				PreferredHostPublicKey: web.Marshal(),
+				AuthorizedKeys:         []*api.AuthorizedKey{{Fingerprint: ssh.FingerprintSHA256(pubKey)}},
+				Labels:                 map[string]string{"role": "web"},
+			},
+			{
+				PreferredHostPublicKey: db.Marshal(),
+				AuthorizedKeys:         []*api.AuthorizedKey{{Fingerprint: ssh.FingerprintSHA256(pubKey)}},
+				Labels:                 map[string]string{"role": "db"},
+			},
+			{
+				PreferredHostPublicKey: other.Marshal(),
+				Labels:                 map[string]string{"role": "web"},
+			},
+		} {
+			c.AgentConnected(ctx, an, nil)
+		}
+
+		agents, err := c.ListAgents(ctx, pubKey, &api.ListAgentsRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agents).To(HaveLen(2))
+
+		agents, err = c.ListAgents(ctx, pubKey, &api.ListAgentsRequest{
+			Expression: &api.Expression{
+				Expr: &api.Expression_Label{
+					Label: &api.LabelMatch{
+						Key:   "role",
+						Value: &api.StringMatch{Match: &api.StringMatch_Exact{Exact: "web"}},
+					},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agents).To(HaveLen(1))
+		Expect(agents[0].Fingerprint).To(Equal(ssh.FingerprintSHA256(web)))
+		Expect(agents[0].ConnectTime).NotTo(BeNil())
+		connected := agents[0].LastActivity.AsTime()
+
+		time.Sleep(10 * time.Millisecond)
+		_, err = c.Lookup(ctx, ssh.FingerprintSHA256(web))
+		Expect(err).NotTo(HaveOccurred())
+		agents, err = c.ListAgents(ctx, pubKey, &api.ListAgentsRequest{
+			Filter: &api.BasicFilter{
+				Operator:         api.Operator_Or,
+				HasAuthorizedKey: ssh.FingerprintSHA256(pubKey),
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agents).To(HaveLen(2))
+		for _, a := range agents {
+			if a.Fingerprint == ssh.FingerprintSHA256(web) {
+				Expect(a.LastActivity.AsTime()).To(BeTemporally(">", connected))
+			} else {
+				Expect(a.LastActivity.AsTime()).To(Equal(a.ConnectTime.AsTime()))
+			}
+		}
+	})
+	It("should reject invalid selectors", func() {
+		c := NewController()
+		_, err := c.ListAgents(context.Background(), pubKey, &api.ListAgentsRequest{
+			Filter:     &api.BasicFilter{},
+			Expression: &api.Expression{},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Broadcast", func() {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	pubKey, _ := ssh.NewPublicKey(pub)
+	newAnnouncement := func(hostname string, authorized bool) (string, *api.Announcement) {
+		hostPub, _, _ := ed25519.GenerateKey(rand.Reader)
+		hostKey, _ := ssh.NewPublicKey(hostPub)
+		an := &api.Announcement{
+			PreferredHostPublicKey: hostKey.Marshal(),
+			Uname:                  &api.UnameInfo{Hostname: hostname},
+		}
+		if authorized {
+			an.AuthorizedKeys = []*api.AuthorizedKey{{User: "root", Fingerprint: ssh.FingerprintSHA256(pubKey)}}
+		}
+		return ssh.FingerprintSHA256(hostKey), an
+	}
+	echo := &api.JobStep{
+		Step: &api.JobStep_Command{
+			Command: &api.Command{Command: "echo"},
+		},
+	}
+	var c *controller
+	var ctx context.Context
+	var mockCtrl *gomock.Controller
+	BeforeEach(func() {
+		c = NewController().(*controller)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	It("should run the instruction on visible matching agents", func() {
+		mockClient := mock_api.NewMockInstructionClient(mockCtrl)
+		mockClient.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *api.CommandRequest, _ ...grpc.CallOption) (*api.CommandResponse, error) {
+				if in.Meta.PeerFingerprint == "" {
+					panic("invalid test")
+				}
+				return &api.CommandResponse{Stdout: in.Meta.PeerFingerprint}, nil
+			}).
+			Times(2)
+		failingClient := mock_api.NewMockInstructionClient(mockCtrl)
+		failingClient.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			Return(&api.CommandResponse{ExitCode: 1}, nil).
+			Times(1)
+
+		expected := map[string]bool{}
+		for _, host := range []struct {
+			hostname   string
+			authorized bool
+			client     api.InstructionClient
+		}{
+			{"web-1", true, mockClient},
+			{"web-2", true, mockClient},
+			{"web-3", true, failingClient},
+			{"web-4", false, nil},
+			{"db-1", true, nil},
+		} {
+			fp, an := newAnnouncement(host.hostname, host.authorized)
+			c.AgentConnected(ctx, an, host.client)
+			if host.client != nil {
+				expected[fp] = host.client == failingClient
+			}
+		}
+
+		results := map[string]*api.BroadcastResult{}
+		summary, err := c.Broadcast(ctx, pubKey, "", &api.BroadcastRequest{
+			Expression: &api.Expression{
+				Expr: &api.Expression_Hostname{
+					Hostname: &api.StringMatch{Match: &api.StringMatch_Glob{Glob: "web-*"}},
+				},
+			},
+			Instruction: echo,
+			BroadcastID: "broadcast",
+		}, func(result *api.BroadcastResult) {
+			results[result.AgentFingerprint] = result
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Total).To(BeEquivalentTo(3))
+		Expect(summary.Succeeded).To(BeEquivalentTo(2))
+		Expect(summary.Failed).To(BeEquivalentTo(1))
+		Expect(results).To(HaveLen(3))
+		for fp, failed := range expected {
+			Expect(results).To(HaveKey(fp))
+			result := results[fp]
+			Expect(result.BroadcastID).To(Equal("broadcast"))
+			Expect(result.StartTime).NotTo(BeNil())
+			Expect(result.EndTime).NotTo(BeNil())
+			if failed {
+				Expect(result.Result.GetCommand().ExitCode).To(BeEquivalentTo(1))
+			} else {
+				Expect(result.Result.GetCommand().Stdout).To(Equal(fp))
+			}
+		}
+
+		log, err := c.AuditLog(ctx, pubKey, time.Time{}, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(log).To(HaveLen(3))
+		Expect(log[0].Action).To(Equal("Broadcast"))
+	})
+	It("should limit the number of agents running at once", func() {
+		var mu sync.Mutex
+		running, maxRunning := 0, 0
+		mockClient := mock_api.NewMockInstructionClient(mockCtrl)
+		mockClient.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, *api.CommandRequest, ...grpc.CallOption) (*api.CommandResponse, error) {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+				time.Sleep(20 * time.Millisecond)
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return &api.CommandResponse{}, nil
+			}).
+			Times(6)
+		for i := 0; i < 6; i++ {
+			_, an := newAnnouncement(fmt.Sprintf("host-%d", i), true)
+			c.AgentConnected(ctx, an, mockClient)
+		}
+		summary, err := c.Broadcast(ctx, pubKey, "", &api.BroadcastRequest{
+			Expression:  &api.Expression{Expr: &api.Expression_And{And: &api.ExpressionList{}}},
+			Instruction: echo,
+			Parallelism: 2,
+			BroadcastID: "broadcast",
+		}, func(*api.BroadcastResult) {})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Succeeded).To(BeEquivalentTo(6))
+		Expect(maxRunning).To(Equal(2))
+	})
+	It("should cancel and fail agents which exceed the per-host timeout", func() {
+		canceled := make(chan string, 1)
+		mockClient := mock_api.NewMockInstructionClient(mockCtrl)
+		mockClient.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *api.CommandRequest, _ ...grpc.CallOption) (*api.CommandResponse, error) {
+				if id := <-canceled; id == "" || id != req.Meta.InstructionID {
+					return nil, fmt.Errorf("wrong instruction canceled: %q", id)
+				}
+				return &api.CommandResponse{
+					ExitCode:          -1,
+					Terminated:        true,
+					TerminationReason: "canceled",
+				}, nil
+			})
+		mockClient.EXPECT().
+			Cancel(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *api.CancelRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+				canceled <- req.Meta.InstructionID
+				return &emptypb.Empty{}, nil
+			})
+		_, an := newAnnouncement("slow", true)
+		c.AgentConnected(ctx, an, mockClient)
+		var result *api.BroadcastResult
+		summary, err := c.Broadcast(ctx, pubKey, "", &api.BroadcastRequest{
+			Expression:  &api.Expression{Expr: &api.Expression_And{And: &api.ExpressionList{}}},
+			Instruction: echo,
+			Timeout:     durationpb.New(50 * time.Millisecond),
+			BroadcastID: "broadcast",
+		}, func(r *api.BroadcastResult) {
+			result = r
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Failed).To(BeEquivalentTo(1))
+		Expect(result.Result.Error).To(BeEmpty())
+		Expect(result.Result.GetCommand().Terminated).To(BeTrue())
+	})
+	It("should roll out in batches and abort after too many failures", func() {
+		// Agents fail on the second batch onwards
+		var mu sync.Mutex
+		calls := 0
+		mockClient := mock_api.NewMockInstructionClient(mockCtrl)
+		mockClient.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, *api.CommandRequest, ...grpc.CallOption) (*api.CommandResponse, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls++
+				if calls > 3 {
+					return &api.CommandResponse{ExitCode: 2}, nil
+				}
+				return &api.CommandResponse{}, nil
+			}).
+			Times(6)
+		for i := 0; i < 10; i++ {
+			_, an := newAnnouncement(fmt.Sprintf("host-%d", i), true)
+			c.AgentConnected(ctx, an, mockClient)
+		}
+		batches := map[int32]int{}
+		summary, err := c.Broadcast(ctx, pubKey, "", &api.BroadcastRequest{
+			Expression:  &api.Expression{Expr: &api.Expression_And{And: &api.ExpressionList{}}},
+			Instruction: echo,
+			Parallelism: 1,
+			BroadcastID: "broadcast",
+			Rollout: &api.Rollout{
+				BatchPercent: 25,
+				Pause:        durationpb.New(10 * time.Millisecond),
+				MaxFailures:  2,
+			},
+		}, func(result *api.BroadcastResult) {
+			batches[result.Batch]++
+		})
+		Expect(err).NotTo(HaveOccurred())
+		// 25% of 10 agents is rounded up to batches of 3
+		Expect(batches).To(Equal(map[int32]int{0: 3, 1: 3}))
+		Expect(summary.Total).To(BeEquivalentTo(10))
+		Expect(summary.Succeeded).To(BeEquivalentTo(3))
+		Expect(summary.Failed).To(BeEquivalentTo(3))
+		Expect(summary.Skipped).To(BeEquivalentTo(4))
+		Expect(summary.Aborted).To(BeTrue())
+		Expect(summary.Batches).To(HaveLen(2))
+		Expect(summary.Batches[1].Failed).To(BeEquivalentTo(3))
+		Expect(summary.Batches[1].StartTime.AsTime().Sub(summary.Batches[0].EndTime.AsTime())).
+			To(BeNumerically(">=", 10*time.Millisecond))
+	})
+	It("should not abort rollouts with a negative failure limit", func() {
+		mockClient := mock_api.NewMockInstructionClient(mockCtrl)
+		mockClient.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			Return(&api.CommandResponse{ExitCode: 1}, nil).
+			Times(5)
+		for i := 0; i < 5; i++ {
+			_, an := newAnnouncement(fmt.Sprintf("host-%d", i), true)
+			c.AgentConnected(ctx, an, mockClient)
+		}
+		summary, err := c.Broadcast(ctx, pubKey, "", &api.BroadcastRequest{
+			Expression:  &api.Expression{Expr: &api.Expression_And{And: &api.ExpressionList{}}},
+			Instruction: echo,
+			BroadcastID: "broadcast",
+			Rollout: &api.Rollout{
+				BatchSize:   2,
+				MaxFailures: -1,
+			},
+		}, func(*api.BroadcastResult) {})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Failed).To(BeEquivalentTo(5))
+		Expect(summary.Aborted).To(BeFalse())
+		Expect(summary.Batches).To(HaveLen(3))
+	})
+	It("should reject invalid requests", func() {
+		for _, req := range []*api.BroadcastRequest{
+			{Instruction: echo},
+			{Filter: &api.BasicFilter{}},
+			{Filter: &api.BasicFilter{}, Instruction: echo, Parallelism: -1},
+			{Filter: &api.BasicFilter{}, Instruction: echo, Timeout: durationpb.New(0)},
+			{Filter: &api.BasicFilter{}, Instruction: echo, Rollout: &api.Rollout{}},
+			{Filter: &api.BasicFilter{}, Instruction: echo, Rollout: &api.Rollout{BatchSize: 1, BatchPercent: 10}},
+			{Filter: &api.BasicFilter{}, Instruction: echo, Rollout: &api.Rollout{BatchPercent: 101}},
+		} {
+			_, err := c.Broadcast(ctx, pubKey, "", req, func(*api.BroadcastResult) {})
+			Expect(err).To(HaveOccurred())
+		}
+	})
+})
+
+var _ = Describe("Instruction Authorization", func() {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	pubKey, _ := ssh.NewPublicKey(pub)
+	clientFp := ssh.FingerprintSHA256(pubKey)
+	hostPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	hostKey, _ := ssh.NewPublicKey(hostPub)
+	agentFp := ssh.FingerprintSHA256(hostKey)
+	announcement := &api.Announcement{
+		PreferredHostPublicKey: hostKey.Marshal(),
+		AgentUser:              "root",
+		AuthorizedKeys: []*api.AuthorizedKey{
+			{User: "alice", Fingerprint: clientFp},
+		},
+	}
+
+	var client api.InstructionClient
+	var mockClient *mock_api.MockInstructionClient
+	BeforeEach(func() {
+		c := NewController()
+		mockClient = mock_api.NewMockInstructionClient(gomock.NewController(GinkgoT()))
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		c.AgentConnected(ctx, announcement, mockClient)
+		var err error
+		client, err = c.Lookup(ctx, agentFp)
+		Expect(err).NotTo(HaveOccurred())
+	})
+	meta := func(clientFingerprint string) *api.InstructionMeta {
+		return &api.InstructionMeta{
+			PeerFingerprint:   agentFp,
+			ClientFingerprint: clientFingerprint,
+		}
+	}
+
+	It("should run commands as the user the client's key is authorized for", func() {
+		mockClient.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *api.CommandRequest, _ ...grpc.CallOption) (*api.CommandResponse, error) {
+				return &api.CommandResponse{Stdout: req.Command.User}, nil
+			}).
+			Times(2)
+		command := &api.Command{Command: "id"}
+		resp, err := client.Command(context.Background(), &api.CommandRequest{
+			Meta:    meta(clientFp),
+			Command: command,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Stdout).To(Equal("alice"))
+		Expect(command.User).To(BeEmpty())
+
+		resp, err = client.Command(context.Background(), &api.CommandRequest{
+			Meta:    meta(clientFp),
+			Command: &api.Command{Command: "id", User: "alice"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Stdout).To(Equal("alice"))
+	})
+	It("should reject instructions which the client's key is not authorized for", func() {
+		_, err := client.Command(context.Background(), &api.CommandRequest{
+			Meta:    meta(clientFp),
+			Command: &api.Command{Command: "id", User: "root"},
+		})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+
+		_, err = client.Script(context.Background(), &api.ScriptRequest{
+			Meta:   meta("SHA256:unknown"),
+			Script: &api.Script{Interpreter: "/bin/sh"},
+		})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+
+		_, err = client.CommandStream(context.Background(), &api.CommandRequest{
+			Meta:    meta(""),
+			Command: &api.Command{Command: "id"},
+		})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	})
+	It("should authorize jobs which run when the agent announces", func() {
+		c := NewController()
+		mockClient := mock_api.NewMockInstructionClient(gomock.NewController(GinkgoT()))
+		mockClient.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *api.CommandRequest, _ ...grpc.CallOption) (*api.CommandResponse, error) {
+				return &api.CommandResponse{Stdout: req.Command.User}, nil
+			})
+		command := func(user string) *api.JobStep {
+			return &api.JobStep{
+				Step: &api.JobStep_Command{
+					Command: &api.Command{Command: "id", User: user},
+				},
+			}
+		}
+		filter := &api.BasicFilter{
+			Operator:         api.Operator_Or,
+			HasAuthorizedKey: clientFp,
+		}
+		allowed, err := c.CreateJob(context.Background(), pubKey, &api.Job{
+			Filter: filter,
+			Steps:  []*api.JobStep{command("")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		denied, err := c.CreateJob(context.Background(), pubKey, &api.Job{
+			Filter: filter,
+			Steps:  []*api.JobStep{command("root")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c.AgentConnected(ctx, announcement, mockClient)
+
+		results := func(id string) func() ([]*api.JobResult, error) {
+			return func() ([]*api.JobResult, error) {
+				return c.JobResults(context.Background(), pubKey, id)
+			}
+		}
+		Eventually(results(allowed.ID)).Should(HaveLen(1))
+		Eventually(results(denied.ID)).Should(HaveLen(1))
+		allowedResults, _ := results(allowed.ID)()
+		Expect(allowedResults[0].Steps[0].GetCommand().GetStdout()).To(Equal("alice"))
+		deniedResults, _ := results(denied.ID)()
+		Expect(deniedResults[0].Steps[0].Error).To(ContainSubstring("PermissionDenied"))
+	})
+	It("should only allow keys authorized for the agent's user to use shells and files", func() {
+		_, err := client.Shell(context.Background(), &api.ShellRequest{
+			Meta: meta(clientFp),
+		})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		_, err = client.GetFile(context.Background(), &api.GetFileRequest{
+			Meta: meta(clientFp),
+			Path: "/etc/shadow",
+		})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	})
+})
+
+var _ = Describe("Client Certificates", func() {
+	newSigner := func() ssh.Signer {
+		_, priv, _ := ed25519.GenerateKey(rand.Reader)
+		signer, _ := ssh.NewSignerFromKey(priv)
+		return signer
+	}
+	ca, otherCA, user := newSigner(), newSigner(), newSigner()
+	newCert := func(ca ssh.Signer, principals ...string) *ssh.Certificate {
+		cert := &ssh.Certificate{
+			Key:             user.PublicKey(),
+			CertType:        ssh.UserCert,
+			KeyId:           "test",
+			ValidPrincipals: principals,
+			ValidAfter:      uint64(time.Now().Add(-time.Minute).Unix()),
+			ValidBefore:     uint64(time.Now().Add(time.Hour).Unix()),
+		}
+		Expect(cert.SignCert(rand.Reader, ca)).To(Succeed())
+		return cert
+	}
+	hostPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	hostKey, _ := ssh.NewPublicKey(hostPub)
+	agentFp := ssh.FingerprintSHA256(hostKey)
+	announcement := &api.Announcement{
+		PreferredHostPublicKey: hostKey.Marshal(),
+		AgentUser:              "root",
+		AuthorizedKeys: []*api.AuthorizedKey{
+			{User: "deploy", Fingerprint: ssh.FingerprintSHA256(ca.PublicKey()), Options: []string{"cert-authority"}},
+		},
+	}
+
+	It("should only list agents which authorize the certificate", func() {
+		c := NewController()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c.AgentConnected(ctx, announcement, nil)
+
+		agents, err := c.ListAgents(ctx, newCert(ca, "deploy"), &api.ListAgentsRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agents).To(HaveLen(1))
+
+		for _, key := range []ssh.PublicKey{
+			newCert(ca, "admin"),
+			newCert(otherCA, "deploy"),
+			user.PublicKey(),
+			ca.PublicKey(),
+		} {
+			agents, err := c.ListAgents(ctx, key, &api.ListAgentsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agents).To(BeEmpty())
+		}
+	})
+	It("should run instructions as the user which authorizes the certificate", func() {
+		c := NewController()
+		mockClient := mock_api.NewMockInstructionClient(gomock.NewController(GinkgoT()))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c.AgentConnected(ctx, announcement, mockClient)
+		client, err := c.Lookup(ctx, agentFp)
+		Expect(err).NotTo(HaveOccurred())
+
+		mockClient.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *api.CommandRequest, _ ...grpc.CallOption) (*api.CommandResponse, error) {
+				return &api.CommandResponse{Stdout: req.Command.User}, nil
+			})
+		cert := newCert(ca, "deploy")
+		resp, err := client.Command(context.Background(), &api.CommandRequest{
+			Meta: &api.InstructionMeta{
+				PeerFingerprint:   agentFp,
+				ClientFingerprint: ssh.FingerprintSHA256(user.PublicKey()),
+				ClientCertificate: cert.Marshal(),
+			},
+			Command: &api.Command{Command: "id"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Stdout).To(Equal("deploy"))
+
+		_, err = client.Command(context.Background(), &api.CommandRequest{
+			Meta: &api.InstructionMeta{
+				PeerFingerprint:   agentFp,
+				ClientFingerprint: ssh.FingerprintSHA256(user.PublicKey()),
+			},
+			Command: &api.Command{Command: "id"},
+		})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	})
+	It("should only accept certificates from trusted CAs when clients connect", func() {
+		s := NewClientAPIServer(NewController(), "127.0.0.1", []ssh.PublicKey{ca.PublicKey()})
+		Expect(s.checkCertificate(newCert(ca, "deploy"))).To(Succeed())
+		Expect(s.checkCertificate(newCert(otherCA, "deploy"))).To(MatchError(ContainSubstring("trusted")))
+
+		expired := &ssh.Certificate{
+			Key:             user.PublicKey(),
+			CertType:        ssh.UserCert,
+			ValidPrincipals: []string{"deploy"},
+			ValidBefore:     uint64(time.Now().Add(-time.Minute).Unix()),
+		}
+		Expect(expired.SignCert(rand.Reader, ca)).To(Succeed())
+		Expect(s.checkCertificate(expired)).NotTo(Succeed())
+
+		s = NewClientAPIServer(NewController(), "127.0.0.1", nil)
+		Expect(s.checkCertificate(newCert(ca, "deploy"))).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Client Streams", func() {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	pubKey, _ := ssh.NewPublicKey(pub)
+	newAgent := func(c Controller, ctx context.Context, client api.InstructionClient) string {
+		hostPub, _, _ := ed25519.GenerateKey(rand.Reader)
+		hostKey, _ := ssh.NewPublicKey(hostPub)
+		c.AgentConnected(ctx, &api.Announcement{
+			PreferredHostPublicKey: hostKey.Marshal(),
+			AgentUser:              "root",
+			AuthorizedKeys: []*api.AuthorizedKey{
+				{User: "root", Fingerprint: ssh.FingerprintSHA256(pubKey)},
+			},
+		}, client)
+		return ssh.FingerprintSHA256(hostKey)
+	}
+
+	var s, other *clientApiServer
+	var mockClient *mock_api.MockInstructionClient
+	var agentFp, otherAgentFp string
+	BeforeEach(func() {
+		c := NewController()
+		mockClient = mock_api.NewMockInstructionClient(gomock.NewController(GinkgoT()))
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		agentFp = newAgent(c, ctx, mockClient)
+		otherAgentFp = newAgent(c, ctx, mock_api.NewMockInstructionClient(gomock.NewController(GinkgoT())))
+		s = NewClientAPIServer(c, "", nil)
+		s.verifiedKey = pubKey
+		// Another client whose key is also authorized on the agents
+		other = NewClientAPIServer(c, "", nil)
+		other.verifiedKey = pubKey
+	})
+	meta := func() *api.InstructionMeta {
+		return &api.InstructionMeta{
+			PeerFingerprint: agentFp,
+			StreamID:        "stream",
+		}
+	}
+
+	It("should only send shell input to the agent the stream was opened on", func() {
+		mockClient.EXPECT().
+			ShellInput(gomock.Any(), gomock.Any()).
+			Return(&emptypb.Empty{}, nil)
+		s.streams["stream"] = otherAgentFp
+		input := func() error {
+			_, err := s.ShellInput(context.Background(), &api.ShellInputRequest{
+				Meta: &api.InstructionMeta{
+					PeerFingerprint: agentFp,
+					StreamID:        "stream",
+				},
+				Input: &api.ShellInputRequest_Data{Data: []byte("id\n")},
+			})
+			return err
+		}
+		Expect(status.Code(input())).To(Equal(codes.NotFound))
+		s.streams["stream"] = agentFp
+		Expect(input()).To(Succeed())
+	})
+	It("should only continue file uploads started by the client", func() {
+		mockClient.EXPECT().
+			PutFile(gomock.Any(), gomock.Any()).
+			Return(&emptypb.Empty{}, nil).
+			Times(2)
+		put := func(s *clientApiServer, offset int64, done bool) error {
+			req := &api.PutFileRequest{
+				Meta:   meta(),
+				Offset: offset,
+				Data:   []byte("data"),
+				Done:   done,
+			}
+			if offset == 0 {
+				req.Info = &api.FileInfo{Path: "/tmp/file"}
+			}
+			_, err := s.PutFile(context.Background(), req)
+			return err
+		}
+		Expect(put(s, 0, false)).To(Succeed())
+		Expect(status.Code(put(s, 0, false))).To(Equal(codes.AlreadyExists))
+		Expect(status.Code(put(other, 4, true))).To(Equal(codes.NotFound))
+		Expect(put(s, 4, true)).To(Succeed())
+		Expect(status.Code(put(s, 8, true))).To(Equal(codes.NotFound))
+	})
+	It("should only continue file downloads started by the client", func() {
+		mockClient.EXPECT().
+			GetFile(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *api.GetFileRequest, _ ...grpc.CallOption) (*api.GetFileResponse, error) {
+				return &api.GetFileResponse{
+					Data: []byte("data"),
+					EOF:  req.Offset > 0,
+				}, nil
+			}).
+			Times(2)
+		get := func(s *clientApiServer, offset int64) error {
+			req := &api.GetFileRequest{
+				Meta:   meta(),
+				Offset: offset,
+			}
+			if offset == 0 {
+				req.Path = "/tmp/file"
+			}
+			_, err := s.GetFile(context.Background(), req)
+			return err
+		}
+		Expect(get(s, 0)).To(Succeed())
+		Expect(status.Code(get(other, 4))).To(Equal(codes.NotFound))
+		Expect(get(s, 4)).To(Succeed())
+		Expect(status.Code(get(s, 8))).To(Equal(codes.NotFound))
+	})
+	It("should serve other requests while a command is running", func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		mockClient.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, *api.CommandRequest, ...grpc.CallOption) (*api.CommandResponse, error) {
+				close(started)
+				<-release
+				return &api.CommandResponse{}, nil
+			})
+		errC := make(chan error, 1)
+		go func() {
+			_, err := s.RunCommand(context.Background(), &api.CommandRequest{
+				Meta:    &api.InstructionMeta{PeerFingerprint: agentFp},
+				Command: &api.Command{Command: "sleep"},
+			})
+			errC <- err
+		}()
+		Eventually(started).Should(BeClosed())
+
+		// Run separately, so that the test fails instead of hanging if the
+		// requests are blocked by the command
+		listed := make(chan *api.AgentList, 1)
+		listErr := make(chan error, 1)
+		go func() {
+			agents, err := s.ListAgents(context.Background(), &api.ListAgentsRequest{})
+			if err == nil {
+				_, err = s.GetAuditLog(context.Background(), &api.TimeRange{})
+			}
+			listErr <- err
+			listed <- agents
+		}()
+		Eventually(listErr).Should(Receive(BeNil()))
+		var agents *api.AgentList
+		Eventually(listed).Should(Receive(&agents))
+		Expect(agents.Items).To(HaveLen(2))
+
+		close(release)
+		Eventually(errC).Should(Receive(BeNil()))
+	})
+	It("should identify the client to the agent when canceling instructions", func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		mockClient.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, *api.CommandRequest, ...grpc.CallOption) (*api.CommandResponse, error) {
+				close(started)
+				<-release
+				return &api.CommandResponse{}, nil
+			})
+		canceledBy := make(chan string, 1)
+		mockClient.EXPECT().
+			Cancel(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *api.CancelRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+				canceledBy <- req.Meta.ClientFingerprint
+				return &emptypb.Empty{}, nil
+			})
+		errC := make(chan error, 1)
+		go func() {
+			_, err := s.RunCommand(context.Background(), &api.CommandRequest{
+				Meta: &api.InstructionMeta{
+					PeerFingerprint: agentFp,
+					InstructionID:   "instruction",
+				},
+				Command: &api.Command{Command: "sleep"},
+			})
+			errC <- err
+		}()
+		Eventually(started).Should(BeClosed())
+
+		_, err := s.Cancel(context.Background(), &api.CancelRequest{
+			Meta: &api.InstructionMeta{
+				InstructionID:     "instruction",
+				ClientFingerprint: "SHA256:spoofed",
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(canceledBy).Should(Receive(Equal(ssh.FingerprintSHA256(pubKey))))
+		close(release)
+		Eventually(errC).Should(Receive(BeNil()))
+	})
+	It("should cancel broadcasts started by the client", func() {
+		c := NewController()
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		client := mock_api.NewMockInstructionClient(gomock.NewController(GinkgoT()))
+		started := make(chan struct{})
+		canceled := make(chan string, 1)
+		// Only the first batch is started
+		client.EXPECT().
+			Command(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *api.CommandRequest, _ ...grpc.CallOption) (*api.CommandResponse, error) {
+				close(started)
+				if id := <-canceled; id != req.Meta.InstructionID {
+					return nil, fmt.Errorf("wrong instruction canceled: %q", id)
+				}
+				return &api.CommandResponse{
+					ExitCode:          -1,
+					Terminated:        true,
+					TerminationReason: "canceled",
+				}, nil
+			})
+		client.EXPECT().
+			Cancel(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *api.CancelRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+				canceled <- req.Meta.InstructionID
+				return &emptypb.Empty{}, nil
+			})
+		newAgent(c, ctx, client)
+		newAgent(c, ctx, client)
+		s := NewClientAPIServer(c, "", nil)
+		s.verifiedKey = pubKey
+		results := make(broadcastResults, 2)
+		s.broadcastClient = results
+
+		errC := make(chan error, 1)
+		go func() {
+			_, err := s.Broadcast(context.Background(), &api.BroadcastRequest{
+				Expression: &api.Expression{Expr: &api.Expression_And{And: &api.ExpressionList{}}},
+				Instruction: &api.JobStep{Step: &api.JobStep_Command{
+					Command: &api.Command{Command: "sleep"},
+				}},
+				BroadcastID: "broadcast",
+				Rollout:     &api.Rollout{BatchSize: 1},
+			})
+			errC <- err
+		}()
+		Eventually(started).Should(BeClosed())
+
+		_, err := other.CancelBroadcast(context.Background(), &api.BroadcastReference{BroadcastID: "broadcast"})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+		_, err = s.CancelBroadcast(context.Background(), &api.BroadcastReference{BroadcastID: "broadcast"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(errC).Should(Receive(WithTransform(status.Code, Equal(codes.Canceled))))
+		var result *api.BroadcastResult
+		Expect(results).To(Receive(&result))
+		Expect(result.Result.GetCommand().GetTerminated()).To(BeTrue())
+		Expect(results).NotTo(Receive())
+	})
+})
+
+// broadcastResults receives the results a relay forwards to a client.
+type broadcastResults chan *api.BroadcastResult
+
+func (r broadcastResults) Result(_ context.Context, in *api.BroadcastResult, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+	r <- in
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,285 @@
+package relay
+
+import (
+	context "context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (c *controller) CreateJob(ctx context.Context, clientKey ssh.PublicKey, job *api.Job) (*api.Job, error) {
+	if err := validateJob(job); err != nil {
+		return nil, err
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	job = proto.Clone(job).(*api.Job)
+	job.ID = id
+	owner := api.NewClientKey(clientKey)
+	job.Owner = owner.Fingerprint
+	job.OwnerCertificate = owner.MarshalCertificate()
+	job.CreationTime = timestamppb.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.store.PutJob(job); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	logrus.Infof("Job %s created", job.ID)
+	for fp, agent := range c.activeAgents {
+		if jobAccepts(job, agent.announcement) {
+			c.startJob(agent.ctx, job, fp, agent.announcement, agent.client)
+		}
+	}
+	return job, nil
+}
+
+func (c *controller) ListJobs(ctx context.Context, clientKey ssh.PublicKey) ([]*api.Job, error) {
+	jobs, err := c.store.ListJobs()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	owner := api.NewClientKey(clientKey).Fingerprint
+	owned := []*api.Job{}
+	for _, job := range jobs {
+		if job.Owner == owner {
+			owned = append(owned, job)
+		}
+	}
+	return owned, nil
+}
+
+func (c *controller) DeleteJob(ctx context.Context, clientKey ssh.PublicKey, id string) error {
+	if _, err := c.ownedJob(clientKey, id); err != nil {
+		return err
+	}
+	if err := c.store.DeleteJob(id); err != nil {
+		return err
+	}
+	logrus.Infof("Job %s deleted", id)
+	return nil
+}
+
+func (c *controller) JobResults(ctx context.Context, clientKey ssh.PublicKey, id string) ([]*api.JobResult, error) {
+	if _, err := c.ownedJob(clientKey, id); err != nil {
+		return nil, err
+	}
+	return c.store.ListResults(id)
+}
+
+// ownedJob returns the job with the given ID if it was created by the given
+// client key. Jobs owned by other clients are reported as not found.
+func (c *controller) ownedJob(clientKey ssh.PublicKey, id string) (*api.Job, error) {
+	job, err := c.store.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Owner != api.NewClientKey(clientKey).Fingerprint {
+		return nil, errJobNotFound
+	}
+	return job, nil
+}
+
+// runPendingJobs starts every stored job which accepts the agent and has not
+// already run on it. The controller lock must be held.
+func (c *controller) runPendingJobs(ctx context.Context, fp string, an *api.Announcement, client api.InstructionClient) {
+	jobs, err := c.store.ListJobs()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list jobs")
+		return
+	}
+	for _, job := range jobs {
+		if jobAccepts(job, an) {
+			c.startJob(ctx, job, fp, an, client)
+		}
+	}
+}
+
+// startJob runs the job on the agent in the background, unless it has already
+// run or is currently running there. The controller lock must be held.
+func (c *controller) startJob(
+	ctx context.Context,
+	job *api.Job,
+	fp string,
+	an *api.Announcement,
+	client api.InstructionClient,
+) {
+	key := job.ID + "/" + fp
+	if _, ok := c.runningJobs[key]; ok {
+		return
+	}
+	if _, err := c.store.GetResult(job.ID, fp); err == nil {
+		return
+	}
+	c.runningJobs[key] = struct{}{}
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			delete(c.runningJobs, key)
+		}()
+		lg := logrus.WithField("job", job.ID).WithField("agent", fp)
+		lg.Info("Running job")
+		result := runJob(ctx, job, fp, client)
+		result.Announcement = an
+		c.Audit(&api.AuditRecord{
+			Time:              result.StartTime,
+			ClientFingerprint: job.Owner,
+			AgentFingerprint:  fp,
+			Action:            "Job",
+			Detail:            job.ID,
+			Error:             jobError(result),
+		})
+		if err := c.store.PutResult(result); err != nil {
+			lg.WithError(err).Error("Failed to store job result")
+			return
+		}
+		lg.Info("Job complete")
+	}()
+}
+
+// runJob runs each step of the job in order, stopping at the first step which
+// fails or returns a non-zero exit code.
+func runJob(ctx context.Context, job *api.Job, fp string, client api.InstructionClient) *api.JobResult {
+	result := &api.JobResult{
+		JobID:            job.ID,
+		AgentFingerprint: fp,
+		StartTime:        timestamppb.Now(),
+	}
+	meta := &api.InstructionMeta{
+		PeerFingerprint:   fp,
+		ClientFingerprint: job.Owner,
+		ClientCertificate: job.OwnerCertificate,
+	}
+	for _, step := range job.Steps {
+		stepResult := runStep(ctx, step, meta, client)
+		result.Steps = append(result.Steps, stepResult)
+		if stepFailed(stepResult) {
+			break
+		}
+	}
+	result.EndTime = timestamppb.Now()
+	return result
+}
+
+// runStep runs a single command or script on the agent.
+func runStep(ctx context.Context, step *api.JobStep, meta *api.InstructionMeta, client api.InstructionClient) *api.JobStepResult {
+	stepResult := &api.JobStepResult{}
+	switch s := step.Step.(type) {
+	case *api.JobStep_Command:
+		resp, err := client.Command(ctx, &api.CommandRequest{
+			Meta:    meta,
+			Command: s.Command,
+		})
+		if err != nil {
+			stepResult.Error = err.Error()
+			break
+		}
+		stepResult.Result = &api.JobStepResult_Command{Command: resp}
+	case *api.JobStep_Script:
+		resp, err := client.Script(ctx, &api.ScriptRequest{
+			Meta:   meta,
+			Script: s.Script,
+		})
+		if err != nil {
+			stepResult.Error = err.Error()
+			break
+		}
+		stepResult.Result = &api.JobStepResult_Script{Script: resp}
+	}
+	return stepResult
+}
+
+// stepFailed returns true if the step could not be run, or returned a
+// non-zero exit code.
+func stepFailed(result *api.JobStepResult) bool {
+	return result.Error != "" || result.GetCommand().GetExitCode() != 0 || result.GetScript().GetExitCode() != 0
+}
+
+// jobError returns the error of the step which could not be run, if any.
+func jobError(result *api.JobResult) string {
+	for _, step := range result.Steps {
+		if step.Error != "" {
+			return step.Error
+		}
+	}
+	return ""
+}
+
+// jobAccepts returns true if the job should run on the agent which sent the
+// given announcement.
+func jobAccepts(job *api.Job, an *api.Announcement) bool {
+	owner, err := job.OwnerKey()
+	if err != nil {
+		return false
+	}
+	return an.Authorizes(owner) && job.Selector().Accepts(an)
+}
+
+func validateJob(job *api.Job) error {
+	switch {
+	case job.Filter != nil && job.Expression != nil:
+		return status.Error(codes.InvalidArgument, "only one of filter or expression can be set")
+	case job.Expression != nil:
+		if err := job.Expression.Validate(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	case job.Filter == nil:
+		return status.Error(codes.InvalidArgument, "missing filter or expression")
+	}
+	if len(job.Steps) == 0 {
+		return status.Error(codes.InvalidArgument, "job has no steps")
+	}
+	for i, step := range job.Steps {
+		if err := validateStep(step); err != nil {
+			return status.Errorf(codes.InvalidArgument, "step %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func validateStep(step *api.JobStep) error {
+	switch s := step.GetStep().(type) {
+	case *api.JobStep_Command:
+		if s.Command == nil || s.Command.Command == "" {
+			return errors.New("missing command")
+		}
+	case *api.JobStep_Script:
+		if s.Script == nil || s.Script.Script == "" {
+			return errors.New("missing script")
+		}
+	default:
+		return errors.New("missing command or script")
+	}
+	return nil
+}
+
+// describeStep returns a short description of the step for the audit log.
+func describeStep(step *api.JobStep) string {
+	switch s := step.GetStep().(type) {
+	case *api.JobStep_Command:
+		return describeCommand(s.Command)
+	case *api.JobStep_Script:
+		return describeScript(s.Script)
+	}
+	return ""
+}
+
+// newID returns a random ID for a job or instruction.
+func newID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
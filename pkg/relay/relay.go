@@ -1,17 +1,23 @@
 package relay
 
 import (
+	"bytes"
 	context "context"
+	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/kralicky/post-init/pkg/api"
 	"github.com/kralicky/totem"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -20,6 +26,11 @@ type RelayServerOptions struct {
 	servingCert   string
 	servingKey    string
 	insecure      bool
+	store         Store
+	dataDir       string
+	retention     time.Duration
+	// Path to a file of CA public keys, in authorized_keys format
+	trustedUserCAKeys string
 }
 
 type RelayServerOption func(*RelayServerOptions)
@@ -49,29 +60,106 @@ func Insecure(insecure bool) RelayServerOption {
 	}
 }
 
+// TrustedUserCAKeys sets the path to a file containing the public keys of
+// CAs, one per line in authorized_keys format, whose user certificates
+// clients can authenticate with. Clients with certificates are only
+// authorized on agents which trust the same CA with a cert-authority entry
+// in their authorized keys. If not set, certificates are not accepted.
+func TrustedUserCAKeys(path string) RelayServerOption {
+	return func(o *RelayServerOptions) {
+		o.trustedUserCAKeys = path
+	}
+}
+
+// Storage sets the store used to persist jobs, announcement history and audit
+// records. If not set, the relay opens a database in its data directory when
+// it starts serving. To keep nothing across restarts, use NewMemoryStore.
+func Storage(store Store) RelayServerOption {
+	return func(o *RelayServerOptions) {
+		o.store = store
+	}
+}
+
+// DataDir sets the directory in which the relay keeps its database. It is
+// created if it does not exist, and is not used if Storage is set. Defaults
+// to DefaultDataDir().
+func DataDir(dir string) RelayServerOption {
+	return func(o *RelayServerOptions) {
+		o.dataDir = dir
+	}
+}
+
+// Retention sets how long the database in the data directory keeps
+// announcement history and audit records (see WithRetention). Defaults to
+// DefaultRetention.
+func Retention(retention time.Duration) RelayServerOption {
+	return func(o *RelayServerOptions) {
+		o.retention = retention
+	}
+}
+
+// DefaultDataDir returns /var/lib/post-init when running as root, and the
+// post-init directory in the user's data directory ($XDG_DATA_HOME, or
+// ~/.local/share) otherwise.
+func DefaultDataDir() string {
+	if os.Geteuid() == 0 {
+		return "/var/lib/post-init"
+	}
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "post-init")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".local", "share", "post-init")
+	}
+	return filepath.Join(os.TempDir(), "post-init")
+}
+
 type Server struct {
 	api.UnimplementedRelayServer
 	options RelayServerOptions
 
-	ctrl Controller
+	ctrl              Controller
+	trustedUserCAKeys []ssh.PublicKey
 }
 
 func NewRelayServer(opts ...RelayServerOption) *Server {
 	options := RelayServerOptions{
 		listenAddress: ":9292",
+		retention:     DefaultRetention,
 	}
 	options.Apply(opts...)
+	if options.dataDir == "" {
+		options.dataDir = DefaultDataDir()
+	}
 	return &Server{
-		ctrl:    NewController(),
 		options: options,
 	}
 }
 
 func (rs *Server) Serve(ctx context.Context) error {
+	store := rs.options.store
+	if store == nil {
+		var err error
+		store, err = openDataDir(rs.options.dataDir, rs.options.retention)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+	}
+	rs.ctrl = NewController(WithStore(store))
 	listener, err := net.Listen("tcp", rs.options.listenAddress)
 	if err != nil {
 		return err
 	}
+	if rs.options.trustedUserCAKeys != "" {
+		keys, err := readTrustedUserCAKeys(rs.options.trustedUserCAKeys)
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		rs.trustedUserCAKeys = keys
+		logrus.Infof("Trusting %d user CA keys", len(keys))
+	}
 	logrus.Infof("Listening on %s", listener.Addr().String())
 	options := []grpc.ServerOption{}
 	if rs.options.insecure {
@@ -90,6 +178,19 @@ func (rs *Server) Serve(ctx context.Context) error {
 	return grpcServer.Serve(listener)
 }
 
+func openDataDir(dir string, retention time.Duration) (Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, "relay.db")
+	store, err := NewBoltStore(path, WithRetention(retention))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	logrus.Infof("Storing relay state in %s", path)
+	return store, nil
+}
+
 func (rs *Server) AgentStream(stream api.Relay_AgentStreamServer) error {
 	ts := totem.NewServer(stream)
 
@@ -104,6 +205,7 @@ func (rs *Server) AgentStream(stream api.Relay_AgentStreamServer) error {
 	select {
 	case <-server.AnnouncementReceived():
 		err := <-errC
+		server.StreamClosed(err)
 		return err
 	case <-time.After(time.Second * 5):
 		return status.Error(codes.DeadlineExceeded, "timed out waiting for announcement")
@@ -115,7 +217,7 @@ func (rs *Server) AgentStream(stream api.Relay_AgentStreamServer) error {
 func (rs *Server) ClientStream(stream api.Relay_ClientStreamServer) error {
 	ts := totem.NewServer(stream)
 
-	server := NewClientAPIServer(rs.ctrl)
+	server := NewClientAPIServer(rs.ctrl, clientAddress(stream.Context()), rs.trustedUserCAKeys)
 	api.RegisterClientAPIServer(ts, server)
 
 	cond := make(chan struct{})
@@ -125,3 +227,38 @@ func (rs *Server) ClientStream(stream api.Relay_ClientStreamServer) error {
 
 	return <-errC
 }
+
+// clientAddress returns the IP address of the client connected on the stream,
+// or an empty string if it is not known.
+func clientAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// readTrustedUserCAKeys reads the CA public keys in the given file.
+func readTrustedUserCAKeys(path string) ([]ssh.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keys := []ssh.PublicKey{}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		key, _, _, _, err := ssh.ParseAuthorizedKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key in %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,273 @@
+package relay
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Store persists relay state, so that it can be queried after the agents and
+// clients it describes have disconnected, or the relay has restarted.
+type Store interface {
+	JobStore
+	AnnouncementStore
+	AuditStore
+	Close() error
+}
+
+// JobStore persists job definitions and the per-agent results of running them.
+type JobStore interface {
+	PutJob(job *api.Job) error
+	GetJob(id string) (*api.Job, error)
+	DeleteJob(id string) error
+	ListJobs() ([]*api.Job, error)
+	PutResult(result *api.JobResult) error
+	GetResult(jobID, agentFingerprint string) (*api.JobResult, error)
+	ListResults(jobID string) ([]*api.JobResult, error)
+}
+
+// AnnouncementStore keeps a history of agent connections. Records are
+// identified by their fingerprint and connect time; putting a record with the
+// same fingerprint and connect time replaces the existing one.
+type AnnouncementStore interface {
+	PutAnnouncement(record *api.AnnouncementRecord) error
+	// ListAnnouncements returns records with a connect time in the range
+	// [since, until), ordered by connect time. A zero until time means there
+	// is no upper bound.
+	ListAnnouncements(since, until time.Time) ([]*api.AnnouncementRecord, error)
+}
+
+// AuditStore keeps a log of instructions sent to agents.
+type AuditStore interface {
+	PutAuditRecord(record *api.AuditRecord) error
+	// ListAuditRecords returns records with a time in the range [since, until),
+	// ordered by time. A zero until time means there is no upper bound.
+	ListAuditRecords(since, until time.Time) ([]*api.AuditRecord, error)
+}
+
+var errJobNotFound = status.Error(codes.NotFound, "job not found")
+
+// DefaultRetention is how long stores keep announcement history and audit
+// records unless configured otherwise.
+const DefaultRetention = 30 * 24 * time.Hour
+
+type StoreOptions struct {
+	retention time.Duration
+}
+
+type StoreOption func(*StoreOptions)
+
+func (o *StoreOptions) Apply(opts ...StoreOption) {
+	for _, op := range opts {
+		op(o)
+	}
+}
+
+// WithRetention sets how long announcement history and audit records are
+// kept. Older records are removed as new ones are added. A retention of 0
+// keeps records forever.
+func WithRetention(retention time.Duration) StoreOption {
+	return func(o *StoreOptions) {
+		o.retention = retention
+	}
+}
+
+func newStoreOptions(opts ...StoreOption) StoreOptions {
+	options := StoreOptions{
+		retention: DefaultRetention,
+	}
+	options.Apply(opts...)
+	return options
+}
+
+// cutoff returns the time before which records should be removed, or the
+// zero time if they are kept forever.
+func (o StoreOptions) cutoff() time.Time {
+	if o.retention <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(-o.retention)
+}
+
+type memoryStore struct {
+	options       StoreOptions
+	mu            sync.Mutex
+	jobs          map[string]*api.Job
+	results       map[string]map[string]*api.JobResult
+	announcements []*api.AnnouncementRecord
+	auditRecords  []*api.AuditRecord
+}
+
+// NewMemoryStore returns a Store which does not persist anything across
+// relay restarts.
+func NewMemoryStore(opts ...StoreOption) Store {
+	return &memoryStore{
+		options: newStoreOptions(opts...),
+		jobs:    make(map[string]*api.Job),
+		results: make(map[string]map[string]*api.JobResult),
+	}
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
+
+func (s *memoryStore) PutJob(job *api.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = proto.Clone(job).(*api.Job)
+	return nil
+}
+
+func (s *memoryStore) GetJob(id string) (*api.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[id]; ok {
+		return proto.Clone(job).(*api.Job), nil
+	}
+	return nil, errJobNotFound
+}
+
+func (s *memoryStore) DeleteJob(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[id]; !ok {
+		return errJobNotFound
+	}
+	delete(s.jobs, id)
+	delete(s.results, id)
+	return nil
+}
+
+func (s *memoryStore) ListJobs() ([]*api.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]*api.Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, proto.Clone(job).(*api.Job))
+	}
+	sortJobs(jobs)
+	return jobs, nil
+}
+
+func (s *memoryStore) PutResult(result *api.JobResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[result.JobID]; !ok {
+		return errJobNotFound
+	}
+	if _, ok := s.results[result.JobID]; !ok {
+		s.results[result.JobID] = make(map[string]*api.JobResult)
+	}
+	s.results[result.JobID][result.AgentFingerprint] = proto.Clone(result).(*api.JobResult)
+	return nil
+}
+
+func (s *memoryStore) GetResult(jobID, agentFingerprint string) (*api.JobResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if result, ok := s.results[jobID][agentFingerprint]; ok {
+		return proto.Clone(result).(*api.JobResult), nil
+	}
+	return nil, status.Error(codes.NotFound, "result not found")
+}
+
+func (s *memoryStore) ListResults(jobID string) ([]*api.JobResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[jobID]; !ok {
+		return nil, errJobNotFound
+	}
+	results := make([]*api.JobResult, 0, len(s.results[jobID]))
+	for _, result := range s.results[jobID] {
+		results = append(results, proto.Clone(result).(*api.JobResult))
+	}
+	sortResults(results)
+	return results, nil
+}
+
+func (s *memoryStore) PutAnnouncement(record *api.AnnouncementRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record = proto.Clone(record).(*api.AnnouncementRecord)
+	for i, r := range s.announcements {
+		if r.Fingerprint == record.Fingerprint && r.ConnectTime.AsTime().Equal(record.ConnectTime.AsTime()) {
+			s.announcements[i] = record
+			return nil
+		}
+	}
+	s.announcements = append(s.announcements, record)
+	sort.SliceStable(s.announcements, func(i, j int) bool {
+		return s.announcements[i].ConnectTime.AsTime().Before(s.announcements[j].ConnectTime.AsTime())
+	})
+	if cutoff := s.options.cutoff(); !cutoff.IsZero() {
+		i := sort.Search(len(s.announcements), func(i int) bool {
+			return !s.announcements[i].ConnectTime.AsTime().Before(cutoff)
+		})
+		s.announcements = append([]*api.AnnouncementRecord(nil), s.announcements[i:]...)
+	}
+	return nil
+}
+
+func (s *memoryStore) ListAnnouncements(since, until time.Time) ([]*api.AnnouncementRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := []*api.AnnouncementRecord{}
+	for _, r := range s.announcements {
+		if inRange(r.ConnectTime, since, until) {
+			records = append(records, proto.Clone(r).(*api.AnnouncementRecord))
+		}
+	}
+	return records, nil
+}
+
+func (s *memoryStore) PutAuditRecord(record *api.AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auditRecords = append(s.auditRecords, proto.Clone(record).(*api.AuditRecord))
+	sort.SliceStable(s.auditRecords, func(i, j int) bool {
+		return s.auditRecords[i].Time.AsTime().Before(s.auditRecords[j].Time.AsTime())
+	})
+	if cutoff := s.options.cutoff(); !cutoff.IsZero() {
+		i := sort.Search(len(s.auditRecords), func(i int) bool {
+			return !s.auditRecords[i].Time.AsTime().Before(cutoff)
+		})
+		s.auditRecords = append([]*api.AuditRecord(nil), s.auditRecords[i:]...)
+	}
+	return nil
+}
+
+func (s *memoryStore) ListAuditRecords(since, until time.Time) ([]*api.AuditRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := []*api.AuditRecord{}
+	for _, r := range s.auditRecords {
+		if inRange(r.Time, since, until) {
+			records = append(records, proto.Clone(r).(*api.AuditRecord))
+		}
+	}
+	return records, nil
+}
+
+func inRange(ts *timestamppb.Timestamp, since, until time.Time) bool {
+	t := ts.AsTime()
+	return !t.Before(since) && (until.IsZero() || t.Before(until))
+}
+
+func sortJobs(jobs []*api.Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreationTime.AsTime().Before(jobs[j].CreationTime.AsTime())
+	})
+}
+
+func sortResults(results []*api.JobResult) {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].StartTime.AsTime().Before(results[j].StartTime.AsTime())
+	})
+}
@@ -0,0 +1,311 @@
+package relay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	jobsBucket          = []byte("jobs")
+	resultsBucket       = []byte("results")
+	announcementsBucket = []byte("announcements")
+	auditBucket         = []byte("audit")
+)
+
+// boltStore is a Store backed by a bbolt database. It contains the buckets:
+//
+//	jobs:          job id -> Job
+//	results:       job id -> (agent fingerprint -> JobResult)
+//	announcements: connect time + agent fingerprint -> AnnouncementRecord
+//	audit:         time + sequence -> AuditRecord
+//
+// Times are encoded as big-endian unix nanoseconds, so that keys in the
+// announcements and audit buckets are ordered by time, and records which
+// have aged out can be removed from the front of the bucket.
+type boltStore struct {
+	options StoreOptions
+	db      *bolt.DB
+}
+
+// NewBoltStore opens (or creates) a bbolt database at the given path and
+// returns a Store backed by it.
+func NewBoltStore(path string, opts ...StoreOption) (Store, error) {
+	options := newStoreOptions(opts...)
+	db, err := bolt.Open(path, 0o600, &bolt.Options{
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{jobsBucket, resultsBucket, announcementsBucket, auditBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		// Records may have aged out while the relay was not running
+		cutoff := options.cutoff()
+		if err := pruneBefore(tx.Bucket(announcementsBucket), cutoff); err != nil {
+			return err
+		}
+		return pruneBefore(tx.Bucket(auditBucket), cutoff)
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltStore{
+		options: options,
+		db:      db,
+	}, nil
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *boltStore) PutJob(job *api.Job) error {
+	data, err := proto.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(job.ID), data)
+	})
+}
+
+func (s *boltStore) GetJob(id string) (*api.Job, error) {
+	job := &api.Job{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(jobsBucket).Get([]byte(id))
+		if data == nil {
+			return errJobNotFound
+		}
+		return proto.Unmarshal(data, job)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *boltStore) DeleteJob(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		jobs := tx.Bucket(jobsBucket)
+		if jobs.Get([]byte(id)) == nil {
+			return errJobNotFound
+		}
+		if err := jobs.Delete([]byte(id)); err != nil {
+			return err
+		}
+		results := tx.Bucket(resultsBucket)
+		if results.Bucket([]byte(id)) != nil {
+			return results.DeleteBucket([]byte(id))
+		}
+		return nil
+	})
+}
+
+func (s *boltStore) ListJobs() ([]*api.Job, error) {
+	jobs := []*api.Job{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(_, data []byte) error {
+			job := &api.Job{}
+			if err := proto.Unmarshal(data, job); err != nil {
+				return err
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortJobs(jobs)
+	return jobs, nil
+}
+
+func (s *boltStore) PutResult(result *api.JobResult) error {
+	data, err := proto.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(jobsBucket).Get([]byte(result.JobID)) == nil {
+			return errJobNotFound
+		}
+		b, err := tx.Bucket(resultsBucket).CreateBucketIfNotExists([]byte(result.JobID))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(result.AgentFingerprint), data)
+	})
+}
+
+func (s *boltStore) GetResult(jobID, agentFingerprint string) (*api.JobResult, error) {
+	result := &api.JobResult{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var data []byte
+		if b := tx.Bucket(resultsBucket).Bucket([]byte(jobID)); b != nil {
+			data = b.Get([]byte(agentFingerprint))
+		}
+		if data == nil {
+			return status.Error(codes.NotFound, "result not found")
+		}
+		return proto.Unmarshal(data, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *boltStore) ListResults(jobID string) ([]*api.JobResult, error) {
+	results := []*api.JobResult{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(jobsBucket).Get([]byte(jobID)) == nil {
+			return errJobNotFound
+		}
+		b := tx.Bucket(resultsBucket).Bucket([]byte(jobID))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, data []byte) error {
+			result := &api.JobResult{}
+			if err := proto.Unmarshal(data, result); err != nil {
+				return err
+			}
+			results = append(results, result)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortResults(results)
+	return results, nil
+}
+
+func (s *boltStore) PutAnnouncement(record *api.AnnouncementRecord) error {
+	data, err := proto.Marshal(record)
+	if err != nil {
+		return err
+	}
+	key := append(timeKey(record.ConnectTime.AsTime()), record.Fingerprint...)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(announcementsBucket)
+		if err := b.Put(key, data); err != nil {
+			return err
+		}
+		return pruneBefore(b, s.options.cutoff())
+	})
+}
+
+func (s *boltStore) ListAnnouncements(since, until time.Time) ([]*api.AnnouncementRecord, error) {
+	records := []*api.AnnouncementRecord{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return scanTimeRange(tx.Bucket(announcementsBucket), since, until, func(data []byte) error {
+			record := &api.AnnouncementRecord{}
+			if err := proto.Unmarshal(data, record); err != nil {
+				return err
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (s *boltStore) PutAuditRecord(record *api.AuditRecord) error {
+	data, err := proto.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(auditBucket)
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 16)
+		copy(key, timeKey(record.Time.AsTime()))
+		binary.BigEndian.PutUint64(key[8:], seq)
+		if err := b.Put(key, data); err != nil {
+			return err
+		}
+		return pruneBefore(b, s.options.cutoff())
+	})
+}
+
+func (s *boltStore) ListAuditRecords(since, until time.Time) ([]*api.AuditRecord, error) {
+	records := []*api.AuditRecord{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return scanTimeRange(tx.Bucket(auditBucket), since, until, func(data []byte) error {
+			record := &api.AuditRecord{}
+			if err := proto.Unmarshal(data, record); err != nil {
+				return err
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	if t.After(time.Unix(0, 0)) {
+		binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	}
+	return key
+}
+
+// scanTimeRange calls fn with the value of each key in the bucket prefixed by
+// a time in the range [since, until).
+func scanTimeRange(b *bolt.Bucket, since, until time.Time, fn func(data []byte) error) error {
+	c := b.Cursor()
+	end := timeKey(until)
+	for k, v := c.Seek(timeKey(since)); k != nil; k, v = c.Next() {
+		if !until.IsZero() && bytes.Compare(k[:8], end) >= 0 {
+			break
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneBefore deletes each key in the bucket prefixed by a time before the
+// cutoff. A zero cutoff deletes nothing.
+func pruneBefore(b *bolt.Bucket, cutoff time.Time) error {
+	if cutoff.IsZero() {
+		return nil
+	}
+	end := timeKey(cutoff)
+	var keys [][]byte
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k[:8], end) < 0; k, _ = c.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+	// Deleting while iterating with a cursor can skip keys
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package relay
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var _ = Describe("Bolt Store", func() {
+	var path string
+	var store Store
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "relay.db")
+		var err error
+		store, err = NewBoltStore(path)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(func() {
+			store.Close()
+		})
+	})
+	reopen := func() {
+		Expect(store.Close()).To(Succeed())
+		var err error
+		store, err = NewBoltStore(path)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("should persist jobs and results", func() {
+		now := time.Now()
+		for i, id := range []string{"b", "a"} {
+			Expect(store.PutJob(&api.Job{
+				ID:           id,
+				CreationTime: timestamppb.New(now.Add(time.Duration(i) * time.Second)),
+			})).To(Succeed())
+		}
+		Expect(store.PutResult(&api.JobResult{
+			JobID:            "a",
+			AgentFingerprint: "SHA256:abc",
+		})).To(Succeed())
+		Expect(store.PutResult(&api.JobResult{
+			JobID: "missing",
+		})).NotTo(Succeed())
+
+		reopen()
+		jobs, err := store.ListJobs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobs).To(HaveLen(2))
+		Expect(jobs[0].ID).To(Equal("b"))
+		Expect(jobs[1].ID).To(Equal("a"))
+
+		result, err := store.GetResult("a", "SHA256:abc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.JobID).To(Equal("a"))
+
+		Expect(store.DeleteJob("a")).To(Succeed())
+		_, err = store.ListResults("a")
+		Expect(err).To(HaveOccurred())
+		_, err = store.GetResult("a", "SHA256:abc")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should list announcements and audit records by time", func() {
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			ts := timestamppb.New(start.Add(time.Duration(i) * time.Minute))
+			Expect(store.PutAnnouncement(&api.AnnouncementRecord{
+				Fingerprint: "agent",
+				ConnectTime: ts,
+			})).To(Succeed())
+			Expect(store.PutAuditRecord(&api.AuditRecord{
+				Time:   ts,
+				Action: "RunCommand",
+			})).To(Succeed())
+		}
+		// Replaces the first record
+		Expect(store.PutAnnouncement(&api.AnnouncementRecord{
+			Fingerprint:    "agent",
+			ConnectTime:    timestamppb.New(start),
+			DisconnectTime: timestamppb.New(start.Add(time.Second)),
+		})).To(Succeed())
+
+		reopen()
+		records, err := store.ListAnnouncements(time.Time{}, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(3))
+		Expect(records[0].DisconnectTime).NotTo(BeNil())
+
+		records, err = store.ListAnnouncements(start.Add(time.Minute), start.Add(2*time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].ConnectTime.AsTime()).To(BeTemporally("==", start.Add(time.Minute)))
+
+		audit, err := store.ListAuditRecords(start.Add(time.Minute), time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(audit).To(HaveLen(2))
+	})
+
+	It("should remove records older than the retention period", func() {
+		Expect(store.Close()).To(Succeed())
+		var err error
+		store, err = NewBoltStore(path, WithRetention(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		now := time.Now()
+		for _, age := range []time.Duration{3 * time.Hour, 2 * time.Hour, time.Minute} {
+			ts := timestamppb.New(now.Add(-age))
+			Expect(store.PutAnnouncement(&api.AnnouncementRecord{
+				Fingerprint: "agent",
+				ConnectTime: ts,
+			})).To(Succeed())
+			Expect(store.PutAuditRecord(&api.AuditRecord{
+				Time:   ts,
+				Action: "RunCommand",
+			})).To(Succeed())
+		}
+		records, err := store.ListAnnouncements(time.Time{}, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].ConnectTime.AsTime()).To(BeTemporally("~", now.Add(-time.Minute), time.Millisecond))
+		audit, err := store.ListAuditRecords(time.Time{}, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(audit).To(HaveLen(1))
+	})
+
+	It("should remove aged out records when opened", func() {
+		Expect(store.Close()).To(Succeed())
+		var err error
+		store, err = NewBoltStore(path, WithRetention(0))
+		Expect(err).NotTo(HaveOccurred())
+		old := timestamppb.New(time.Now().Add(-2 * time.Hour))
+		Expect(store.PutAnnouncement(&api.AnnouncementRecord{
+			Fingerprint: "agent",
+			ConnectTime: old,
+		})).To(Succeed())
+		Expect(store.PutAuditRecord(&api.AuditRecord{Time: old})).To(Succeed())
+		records, err := store.ListAnnouncements(time.Time{}, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+
+		Expect(store.Close()).To(Succeed())
+		store, err = NewBoltStore(path, WithRetention(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		records, err = store.ListAnnouncements(time.Time{}, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(BeEmpty())
+		audit, err := store.ListAuditRecords(time.Time{}, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(audit).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Memory Store", func() {
+	It("should remove records older than the retention period", func() {
+		store := NewMemoryStore(WithRetention(time.Hour))
+		now := time.Now()
+		for _, age := range []time.Duration{time.Minute, 3 * time.Hour, 2 * time.Hour} {
+			ts := timestamppb.New(now.Add(-age))
+			Expect(store.PutAnnouncement(&api.AnnouncementRecord{
+				Fingerprint: "agent",
+				ConnectTime: ts,
+			})).To(Succeed())
+			Expect(store.PutAuditRecord(&api.AuditRecord{Time: ts})).To(Succeed())
+		}
+		records, err := store.ListAnnouncements(time.Time{}, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+		audit, err := store.ListAuditRecords(time.Time{}, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(audit).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Data Directory", func() {
+	It("should create the directory and persist state in it", func() {
+		dir := filepath.Join(GinkgoT().TempDir(), "a", "b")
+		store, err := openDataDir(dir, DefaultRetention)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.PutJob(&api.Job{ID: "a"})).To(Succeed())
+		Expect(store.Close()).To(Succeed())
+
+		info, err := os.Stat(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o700)))
+
+		store, err = openDataDir(dir, DefaultRetention)
+		Expect(err).NotTo(HaveOccurred())
+		defer store.Close()
+		_, err = store.GetJob("a")
+		Expect(err).NotTo(HaveOccurred())
+	})
+	It("should default to an on-disk store in the data directory", func() {
+		Expect(NewRelayServer().options.dataDir).To(Equal(DefaultDataDir()))
+		Expect(NewRelayServer().options.store).To(BeNil())
+	})
+})
@@ -0,0 +1,189 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type BroadcastOptions struct {
+	parallelism  int32
+	timeout      time.Duration
+	batchSize    int32
+	batchPercent int32
+	batchPause   time.Duration
+	maxFailures  int32
+}
+
+type BroadcastOption func(*BroadcastOptions)
+
+func (o *BroadcastOptions) Apply(opts ...BroadcastOption) {
+	for _, op := range opts {
+		op(o)
+	}
+}
+
+// WithParallelism limits the number of agents the instruction runs on at
+// once. By default, it runs on all matching agents at once.
+func WithParallelism(n int) BroadcastOption {
+	return func(o *BroadcastOptions) {
+		o.parallelism = int32(n)
+	}
+}
+
+// WithHostTimeout limits how long the instruction can run on each agent.
+// Agents on which it times out are counted as failed.
+func WithHostTimeout(timeout time.Duration) BroadcastOption {
+	return func(o *BroadcastOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithBatchSize rolls the instruction out to the given number of agents at a
+// time. Each batch starts once the instruction has finished on every agent
+// in the previous batch. Parallelism applies within each batch.
+func WithBatchSize(n int) BroadcastOption {
+	return func(o *BroadcastOptions) {
+		o.batchSize = int32(n)
+		o.batchPercent = 0
+	}
+}
+
+// WithBatchPercent is like WithBatchSize, but each batch contains the given
+// percentage of the matching agents, rounded up.
+func WithBatchPercent(percent int) BroadcastOption {
+	return func(o *BroadcastOptions) {
+		o.batchPercent = int32(percent)
+		o.batchSize = 0
+	}
+}
+
+// WithBatchPause waits for the given duration between batches. It has no
+// effect without WithBatchSize or WithBatchPercent.
+func WithBatchPause(pause time.Duration) BroadcastOption {
+	return func(o *BroadcastOptions) {
+		o.batchPause = pause
+	}
+}
+
+// WithMaxFailures aborts a rollout once more than n agents have failed. By
+// default, a rollout is aborted after the first batch in which any agent
+// fails. If n is negative, the rollout is never aborted. It has no effect
+// without WithBatchSize or WithBatchPercent.
+func WithMaxFailures(n int) BroadcastOption {
+	return func(o *BroadcastOptions) {
+		o.maxFailures = int32(n)
+	}
+}
+
+// A BroadcastHandler is called with each agent's result as soon as the
+// instruction finishes on that agent. Calls are never concurrent.
+type BroadcastHandler func(*api.BroadcastResult)
+
+// Broadcast runs a command or script (see CommandStep and ScriptStep) on
+// every connected agent matching the selector (a *api.BasicFilter or
+// *api.Expression) on which the client's key is authorized. The relay runs
+// the instruction on the agents concurrently, and sends each result to the
+// handler as it becomes available. Broadcast returns once the instruction
+// has finished on every agent, or the rollout has been aborted. If ctx is
+// done first, the broadcast is canceled on the relay, which terminates the
+// instruction on the agents it is still running on.
+func (rc *RelayClient) Broadcast(
+	ctx context.Context,
+	selector api.Selector,
+	instruction *api.JobStep,
+	handler BroadcastHandler,
+	opts ...BroadcastOption,
+) (*api.BroadcastSummary, error) {
+	options := BroadcastOptions{}
+	options.Apply(opts...)
+
+	filter, expr, err := splitSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	id, err := rc.session.broadcasts.add(handler)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.session.broadcasts.remove(id)
+	req := &api.BroadcastRequest{
+		Filter:      filter,
+		Expression:  expr,
+		Instruction: instruction,
+		Parallelism: options.parallelism,
+		BroadcastID: id,
+	}
+	if options.timeout > 0 {
+		req.Timeout = durationpb.New(options.timeout)
+	}
+	if options.batchSize > 0 || options.batchPercent > 0 {
+		req.Rollout = &api.Rollout{
+			BatchSize:    options.batchSize,
+			BatchPercent: options.batchPercent,
+			MaxFailures:  options.maxFailures,
+		}
+		if options.batchPause > 0 {
+			req.Rollout.Pause = durationpb.New(options.batchPause)
+		}
+	}
+	// Totem does not propagate cancellation, so the broadcast is explicitly
+	// canceled on the relay if ctx is done first.
+	var summary *api.BroadcastSummary
+	err = api.RunCanceling(ctx, func(ctx context.Context) error {
+		_, err := rc.apiClient.CancelBroadcast(ctx, &api.BroadcastReference{
+			BroadcastID: id,
+		})
+		return err
+	}, func(ctx context.Context) (err error) {
+		summary, err = rc.apiClient.Broadcast(ctx, req)
+		return
+	})
+	return summary, err
+}
+
+type broadcastHandlers struct {
+	mu       sync.Mutex
+	handlers map[string]BroadcastHandler
+}
+
+func newBroadcastHandlers() *broadcastHandlers {
+	return &broadcastHandlers{
+		handlers: make(map[string]BroadcastHandler),
+	}
+}
+
+// add registers the handler under a new random broadcast ID, which is
+// returned.
+func (h *broadcastHandlers) add(handler BroadcastHandler) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[id] = handler
+	return id, nil
+}
+
+func (h *broadcastHandlers) remove(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.handlers, id)
+}
+
+func (h *broadcastHandlers) dispatch(result *api.BroadcastResult) error {
+	h.mu.Lock()
+	handler, ok := h.handlers[result.BroadcastID]
+	h.mu.Unlock()
+	if !ok {
+		return status.Error(codes.NotFound, "unknown broadcast")
+	}
+	handler(result)
+	return nil
+}
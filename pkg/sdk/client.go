@@ -3,6 +3,8 @@ package sdk
 import (
 	"context"
 	"crypto/tls"
+	"errors"
+	"fmt"
 
 	"github.com/kralicky/post-init/pkg/api"
 	"github.com/kralicky/totem"
@@ -22,22 +24,60 @@ type ClientConfig struct {
 	// SSH keypair which the relay server will verify and use to
 	// authenticate the client with any daemons that connect.
 	Signer ssh.Signer
+	// If Signer is nil and SSHAgent is true, the signer is obtained from the
+	// ssh-agent listening on $SSH_AUTH_SOCK when connecting. KeyFingerprint
+	// selects the agent's key by its SHA256 fingerprint, and may be empty if
+	// the agent holds only one key. See SSHAgentSigner.
+	SSHAgent       bool
+	KeyFingerprint string
+	// If set, the client authenticates with this OpenSSH user certificate,
+	// which must certify the signer's key. The relay must trust the CA which
+	// signed it, and agents authorize it with cert-authority entries in their
+	// authorized keys.
+	Certificate *ssh.Certificate
 }
 
 type RelayClient struct {
 	conf        *ClientConfig
 	relayClient api.RelayClient
 	apiClient   api.ClientAPIClient
-	callbacks   []NotifyCallback
+	session     *session
 }
 
 func NewRelayClient(conf *ClientConfig) (*RelayClient, error) {
+	if conf.Signer == nil && !conf.SSHAgent {
+		return nil, errors.New("either Signer or SSHAgent must be set")
+	}
+	c := *conf
 	return &RelayClient{
-		conf: conf,
+		conf: &c,
 	}, nil
 }
 
 func (rc *RelayClient) Connect(ctx context.Context) error {
+	if rc.conf.Signer == nil {
+		a, closer, err := DialSSHAgent()
+		if err != nil {
+			return err
+		}
+		signer, err := SSHAgentSigner(a, rc.conf.KeyFingerprint)
+		if err != nil {
+			closer.Close()
+			return err
+		}
+		go func() {
+			<-ctx.Done()
+			closer.Close()
+		}()
+		rc.conf.Signer = signer
+	}
+	if _, ok := rc.conf.Signer.PublicKey().(*ssh.Certificate); !ok && rc.conf.Certificate != nil {
+		signer, err := ssh.NewCertSigner(rc.conf.Certificate, rc.conf.Signer)
+		if err != nil {
+			return err
+		}
+		rc.conf.Signer = signer
+	}
 	var creds credentials.TransportCredentials
 	if rc.conf.Insecure {
 		creds = insecure.NewCredentials()
@@ -65,32 +105,23 @@ func (rc *RelayClient) Connect(ctx context.Context) error {
 	}
 	ts := totem.NewServer(stream)
 
-	ch := make(chan ControlContext)
 	session := &session{
-		conf:      rc.conf,
-		apiClient: rc.apiClient,
-		kexState:  NewKeyExchangeState(),
-		notifyC:   ch,
+		conf:       rc.conf,
+		kexState:   NewKeyExchangeState(),
+		watches:    newWatchCallbacks(),
+		outputs:    newOutputHandlers(),
+		broadcasts: newBroadcastHandlers(),
 	}
 
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case cc := <-ch:
-				for _, cb := range rc.callbacks {
-					go cb(cc)
-				}
-			}
-		}
-	}()
-
 	api.RegisterWatchServer(ts, session)
 	api.RegisterKeyExchangeServer(ts, session)
+	api.RegisterOutputStreamServer(ts, session)
+	api.RegisterBroadcastStreamServer(ts, session)
 	clientConn, _ := ts.Serve()
 
 	rc.apiClient = api.NewClientAPIClient(clientConn)
+	session.apiClient = rc.apiClient
+	rc.session = session
 	if _, err := rc.apiClient.Connect(ctx, &api.ConnectionRequest{
 		PublicClientKey: ssh.MarshalAuthorizedKey(rc.conf.Signer.PublicKey()),
 	}); err != nil {
@@ -99,17 +130,116 @@ func (rc *RelayClient) Connect(ctx context.Context) error {
 	return nil
 }
 
+// Watch requests notifications for agents matching the given selector, which
+// can be either a *api.BasicFilter or a *api.Expression. The callback is
+// called when a matching agent connects or reconnects, or when a status change
+// causes an agent to start matching. Use WatchEvents to also be notified when
+// agents disconnect. The returned ID can be passed to Unwatch.
 func (rc *RelayClient) Watch(
 	ctx context.Context,
-	filter *api.BasicFilter,
+	selector api.Selector,
 	callback NotifyCallback,
-) error {
-	rc.callbacks = append(rc.callbacks, callback) // todo: fix this logic
-	_, err := rc.apiClient.Watch(ctx, &api.WatchRequest{
-		Filter: filter,
+) (string, error) {
+	return rc.WatchEvents(ctx, selector, func(ev *api.WatchEvent, cc ControlContext) {
+		switch ev.Type {
+		case api.WatchEventType_Connected, api.WatchEventType_Reannounced:
+			callback(cc)
+		case api.WatchEventType_StatusChanged:
+			if !ev.PreviouslyMatched {
+				callback(cc)
+			}
+		}
 	})
+}
+
+// WatchEvents requests events for agents matching the given selector, which
+// can be either a *api.BasicFilter or a *api.Expression. The callback is
+// called for every event on this watch, including when a matching agent
+// disconnects. If the relay closes the watch, for example because the client
+// is not keeping up with its events, the callback is called one last time
+// with a Closed event, whose control context is nil. A client can have any number of watches, and each callback
+// only receives events for its own watch. Each watch's callback is called
+// from its own goroutine, one event at a time and in the order the relay sent
+// them. The returned ID can be passed to Unwatch.
+func (rc *RelayClient) WatchEvents(
+	ctx context.Context,
+	selector api.Selector,
+	callback EventCallback,
+) (string, error) {
+	filter, expr, err := splitSelector(selector)
 	if err != nil {
-		return err
+		return "", err
+	}
+	// The callback is registered first, since the relay sends events for
+	// agents which are already connected before Watch returns.
+	id, err := rc.session.watches.add(callback)
+	if err != nil {
+		return "", err
+	}
+	_, err = rc.apiClient.Watch(ctx, &api.WatchRequest{
+		Filter:     filter,
+		Expression: expr,
+		WatchID:    id,
+	})
+	if err != nil {
+		rc.session.watches.remove(id)
+		return "", err
+	}
+	return id, nil
+}
+
+// Unwatch stops the watch with the given ID. Events which have not been
+// passed to its callback yet are discarded, and the callback is not called
+// again after Unwatch returns, although a call already in progress may still
+// be running.
+func (rc *RelayClient) Unwatch(ctx context.Context, id string) error {
+	rc.session.watches.remove(id)
+	_, err := rc.apiClient.Unwatch(ctx, &api.WatchReference{
+		WatchID: id,
+	})
+	return err
+}
+
+// ListAgents returns the agents currently connected to the relay on which the
+// client's key is authorized. If selector is not nil, only matching agents are
+// returned.
+func (rc *RelayClient) ListAgents(ctx context.Context, selector api.Selector) ([]*api.AgentInfo, error) {
+	req := &api.ListAgentsRequest{}
+	if selector != nil {
+		var err error
+		req.Filter, req.Expression, err = splitSelector(selector)
+		if err != nil {
+			return nil, err
+		}
+	}
+	list, err := rc.apiClient.ListAgents(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func splitSelector(selector api.Selector) (*api.BasicFilter, *api.Expression, error) {
+	switch s := selector.(type) {
+	case *api.BasicFilter:
+		return s, nil, nil
+	case *api.Expression:
+		return nil, s, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported selector type %T", selector)
+	}
+}
+
+// Control returns a ControlContext for the agent with the given SHA256
+// fingerprint (see Peer), which can be used without waiting for a watch
+// notification. The relay must already know about the agent.
+func (rc *RelayClient) Control(ctx context.Context, fingerprint string) ControlContext {
+	return &controlCtxImpl{
+		ctx:       ctx,
+		apiClient: rc.apiClient,
+		peer: &Peer{
+			Fingerprint: fingerprint,
+		},
+		outputs: rc.session.outputs,
 	}
-	return nil
 }
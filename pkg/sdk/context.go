@@ -6,33 +6,178 @@ import (
 	"github.com/kralicky/post-init/pkg/api"
 )
 
+// Peer identifies the agent which a ControlContext sends instructions to.
+type Peer struct {
+	// SHA256 fingerprint of the agent's preferred host key, in the format
+	// returned by ssh.FingerprintSHA256. The relay identifies agents by this
+	// fingerprint.
+	Fingerprint string
+	// The announcement which the agent sent to the relay, or nil if the
+	// ControlContext was not obtained from a watch notification.
+	Announcement *api.Announcement
+	// The agent's labels, from its announcement
+	Labels map[string]string
+}
+
+// NewPeer returns the peer which sent the announcement.
+func NewPeer(an *api.Announcement) (*Peer, error) {
+	fp, err := an.Fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	return &Peer{
+		Fingerprint:  fp,
+		Announcement: an,
+		Labels:       an.GetLabels(),
+	}, nil
+}
+
 type ControlContext interface {
+	// Peer returns the agent which instructions are sent to.
+	Peer() *Peer
+	// RunCommand and RunScript run the instruction and return its output once
+	// it exits. If the context used to obtain the ControlContext is canceled
+	// first, the process is killed on the agent and the response reports that
+	// it was terminated.
 	RunCommand(*api.Command) (*api.CommandResponse, error)
 	RunScript(*api.Script) (*api.ScriptResponse, error)
+	// StreamCommand and StreamScript run the instruction and call the handler
+	// with its output as it is produced. They return once the exit status has
+	// been delivered to the handler, and are canceled like RunCommand.
+	StreamCommand(*api.Command, OutputHandler) error
+	StreamScript(*api.Script, OutputHandler) error
+	// Shell starts an interactive shell on the agent. Output from the shell's
+	// terminal is passed to the handler, and input can be sent to it using the
+	// returned session.
+	Shell(*api.Shell, OutputHandler) (*ShellSession, error)
+	// PutFile copies the local file or directory src to the absolute path
+	// dest on the agent. Directories are copied recursively.
+	PutFile(src, dest string, opts ...FileOption) error
+	// GetFile copies the file or directory at the absolute path src on the
+	// agent to the local path dest. Directories are copied recursively.
+	GetFile(src, dest string) error
 }
 
 type NotifyCallback func(ControlContext)
 
+// An EventCallback is called for each event on a watch. The ControlContext
+// refers to the agent which the event is about. Instructions sent to an agent
+// after it has disconnected will fail. Closed events are not about an agent,
+// and have a nil ControlContext.
+type EventCallback func(*api.WatchEvent, ControlContext)
+
 type controlCtxImpl struct {
-	ctx          context.Context
-	apiClient    api.ClientAPIClient
-	announcement *api.Announcement
+	ctx       context.Context
+	apiClient api.ClientAPIClient
+	peer      *Peer
+	outputs   *outputHandlers
+}
+
+func (cc *controlCtxImpl) Peer() *Peer {
+	return cc.peer
+}
+
+func (cc *controlCtxImpl) RunCommand(cmd *api.Command) (resp *api.CommandResponse, err error) {
+	req := &api.CommandRequest{
+		Meta: &api.InstructionMeta{
+			PeerFingerprint: cc.peer.Fingerprint,
+		},
+		Command: cmd,
+	}
+	err = cc.run(req.Meta, func(ctx context.Context) (err error) {
+		resp, err = cc.apiClient.RunCommand(ctx, req)
+		return
+	})
+	return
+}
+
+func (cc *controlCtxImpl) RunScript(sc *api.Script) (resp *api.ScriptResponse, err error) {
+	req := &api.ScriptRequest{
+		Meta: &api.InstructionMeta{
+			PeerFingerprint: cc.peer.Fingerprint,
+		},
+		Script: sc,
+	}
+	err = cc.run(req.Meta, func(ctx context.Context) (err error) {
+		resp, err = cc.apiClient.RunScript(ctx, req)
+		return
+	})
+	return
 }
 
-func (cc *controlCtxImpl) RunCommand(cmd *api.Command) (*api.CommandResponse, error) {
-	return cc.apiClient.RunCommand(cc.ctx, &api.CommandRequest{
+func (cc *controlCtxImpl) StreamCommand(cmd *api.Command, handler OutputHandler) error {
+	id, err := cc.outputs.add(handler)
+	if err != nil {
+		return err
+	}
+	defer cc.outputs.remove(id)
+	req := &api.CommandRequest{
 		Meta: &api.InstructionMeta{
-			PeerFingerprint: string(cc.announcement.PreferredHostPublicKey),
+			PeerFingerprint: cc.peer.Fingerprint,
+			StreamID:        id,
 		},
 		Command: cmd,
+	}
+	return cc.run(req.Meta, func(ctx context.Context) error {
+		_, err := cc.apiClient.RunCommandStream(ctx, req)
+		return err
 	})
 }
 
-func (cc *controlCtxImpl) RunScript(sc *api.Script) (*api.ScriptResponse, error) {
-	return cc.apiClient.RunScript(cc.ctx, &api.ScriptRequest{
+func (cc *controlCtxImpl) StreamScript(sc *api.Script, handler OutputHandler) error {
+	id, err := cc.outputs.add(handler)
+	if err != nil {
+		return err
+	}
+	defer cc.outputs.remove(id)
+	req := &api.ScriptRequest{
 		Meta: &api.InstructionMeta{
-			PeerFingerprint: string(cc.announcement.PreferredHostPublicKey),
+			PeerFingerprint: cc.peer.Fingerprint,
+			StreamID:        id,
 		},
 		Script: sc,
+	}
+	return cc.run(req.Meta, func(ctx context.Context) error {
+		_, err := cc.apiClient.RunScriptStream(ctx, req)
+		return err
+	})
+}
+
+// run assigns the instruction a new ID, and calls it so that it is canceled
+// on the agent if the control context is done first (see api.RunCancelable).
+func (cc *controlCtxImpl) run(meta *api.InstructionMeta, call func(context.Context) error) error {
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+	meta.InstructionID = id
+	return api.RunCancelable(cc.ctx, cc.apiClient, meta, call)
+}
+
+func (cc *controlCtxImpl) Shell(sh *api.Shell, handler OutputHandler) (*ShellSession, error) {
+	session := &ShellSession{
+		cc:    cc,
+		ready: make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	id, err := cc.outputs.add(func(ev *api.OutputEvent) {
+		session.markReady()
+		handler(ev)
 	})
+	if err != nil {
+		return nil, err
+	}
+	session.meta = &api.InstructionMeta{
+		PeerFingerprint: cc.peer.Fingerprint,
+		StreamID:        id,
+	}
+	go func() {
+		defer close(session.done)
+		defer cc.outputs.remove(id)
+		_, session.err = cc.apiClient.RunShell(cc.ctx, &api.ShellRequest{
+			Meta:  session.meta,
+			Shell: sh,
+		})
+	}()
+	return session, nil
 }
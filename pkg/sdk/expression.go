@@ -0,0 +1,152 @@
+package sdk
+
+import "github.com/kralicky/post-init/pkg/api"
+
+// The functions in this file are shorthands for building watch expressions,
+// for example:
+//
+//	sdk.And(
+//		sdk.Hostname(sdk.Glob("web-*")),
+//		sdk.IPAddress("10.0.0.0/8"),
+//		sdk.Not(sdk.Label("env", sdk.Exact("prod"))),
+//	)
+
+func And(exprs ...*api.Expression) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_And{
+			And: &api.ExpressionList{Items: exprs},
+		},
+	}
+}
+
+func Or(exprs ...*api.Expression) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_Or{
+			Or: &api.ExpressionList{Items: exprs},
+		},
+	}
+}
+
+func Not(expr *api.Expression) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_Not{Not: expr},
+	}
+}
+
+func Hostname(m *api.StringMatch) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_Hostname{Hostname: m},
+	}
+}
+
+// IPAddress matches agents with an address in a comma-separated list of IP
+// addresses and CIDR ranges. Ranges prefixed with "!" are excluded.
+func IPAddress(addr string) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_IPAddress{IPAddress: addr},
+	}
+}
+
+// AuthorizedKey matches agents with the given key fingerprint in their
+// authorized keys.
+func AuthorizedKey(fingerprint string) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_AuthorizedKey{AuthorizedKey: fingerprint},
+	}
+}
+
+func KernelName(m *api.StringMatch) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_KernelName{KernelName: m},
+	}
+}
+
+func KernelRelease(m *api.StringMatch) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_KernelRelease{KernelRelease: m},
+	}
+}
+
+func Machine(m *api.StringMatch) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_Machine{Machine: m},
+	}
+}
+
+// Label matches agents with the given label. If m is nil, the label's value
+// is not checked.
+func Label(key string, m *api.StringMatch) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_Label{
+			Label: &api.LabelMatch{Key: key, Value: m},
+		},
+	}
+}
+
+func cloud(m *api.CloudMatch) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_Cloud{Cloud: m},
+	}
+}
+
+// CloudProvider matches agents running on the given cloud provider (one of
+// aws, gce, azure, openstack).
+func CloudProvider(m *api.StringMatch) *api.Expression {
+	return cloud(&api.CloudMatch{Field: &api.CloudMatch_Provider{Provider: m}})
+}
+
+func InstanceID(m *api.StringMatch) *api.Expression {
+	return cloud(&api.CloudMatch{Field: &api.CloudMatch_InstanceID{InstanceID: m}})
+}
+
+func InstanceType(m *api.StringMatch) *api.Expression {
+	return cloud(&api.CloudMatch{Field: &api.CloudMatch_InstanceType{InstanceType: m}})
+}
+
+func Region(m *api.StringMatch) *api.Expression {
+	return cloud(&api.CloudMatch{Field: &api.CloudMatch_Region{Region: m}})
+}
+
+func Zone(m *api.StringMatch) *api.Expression {
+	return cloud(&api.CloudMatch{Field: &api.CloudMatch_Zone{Zone: m}})
+}
+
+// CloudTag matches agents with the given instance tag. If m is nil, the tag's
+// value is not checked.
+func CloudTag(key string, m *api.StringMatch) *api.Expression {
+	return cloud(&api.CloudMatch{
+		Field: &api.CloudMatch_Tag{
+			Tag: &api.LabelMatch{Key: key, Value: m},
+		},
+	})
+}
+
+// CloudInit matches agents whose most recently reported cloud-init state is
+// the given state. CloudInitFailed is a shorthand for the Error state.
+func CloudInit(state api.CloudInitState) *api.Expression {
+	return &api.Expression{
+		Expr: &api.Expression_CloudInit{CloudInit: state},
+	}
+}
+
+func CloudInitFailed() *api.Expression {
+	return CloudInit(api.CloudInitState_Error)
+}
+
+func Exact(s string) *api.StringMatch {
+	return &api.StringMatch{
+		Match: &api.StringMatch_Exact{Exact: s},
+	}
+}
+
+func Glob(pattern string) *api.StringMatch {
+	return &api.StringMatch{
+		Match: &api.StringMatch_Glob{Glob: pattern},
+	}
+}
+
+func Regex(re string) *api.StringMatch {
+	return &api.StringMatch{
+		Match: &api.StringMatch_Regex{Regex: re},
+	}
+}
@@ -0,0 +1,202 @@
+package sdk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"github.com/kralicky/post-init/pkg/util"
+)
+
+const fileChunkSize = 1024 * 1024
+
+type FileOptions struct {
+	owner string
+	group string
+	mode  *fs.FileMode
+}
+
+type FileOption func(*FileOptions)
+
+func (o *FileOptions) Apply(opts ...FileOption) {
+	for _, op := range opts {
+		op(o)
+	}
+}
+
+// WithOwner sets the user and group names which will own the copied file(s)
+// on the agent. Either can be empty to use the agent's default.
+func WithOwner(owner, group string) FileOption {
+	return func(o *FileOptions) {
+		o.owner = owner
+		o.group = group
+	}
+}
+
+// WithMode overrides the permission bits of the copied file on the agent.
+// By default, the permission bits of the local file are used.
+func WithMode(mode fs.FileMode) FileOption {
+	return func(o *FileOptions) {
+		o.mode = &mode
+	}
+}
+
+func (cc *controlCtxImpl) PutFile(src, dest string, opts ...FileOption) error {
+	options := FileOptions{}
+	options.Apply(opts...)
+
+	stat, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	info := &api.FileInfo{
+		Path:      dest,
+		Mode:      uint32(stat.Mode().Perm()),
+		Owner:     options.owner,
+		Group:     options.group,
+		Directory: stat.IsDir(),
+	}
+	if options.mode != nil {
+		info.Mode = uint32(options.mode.Perm())
+	}
+
+	var f *os.File
+	if stat.IsDir() {
+		f, err = os.CreateTemp("", "post-init-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(f.Name())
+		if err := util.WriteTar(f, src); err != nil {
+			f.Close()
+			return err
+		}
+	} else {
+		f, err = os.Open(src)
+		if err != nil {
+			return err
+		}
+	}
+	defer f.Close()
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if info.Size, info.Checksum, err = util.Checksum(f); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+	meta := &api.InstructionMeta{
+		PeerFingerprint: cc.peer.Fingerprint,
+		StreamID:        id,
+	}
+	buf := make([]byte, fileChunkSize)
+	var offset int64
+	for {
+		n, err := io.ReadFull(f, buf)
+		done := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !done {
+			return err
+		}
+		req := &api.PutFileRequest{
+			Meta:   meta,
+			Offset: offset,
+			Data:   buf[:n],
+			Done:   done,
+		}
+		if offset == 0 {
+			req.Info = info
+		}
+		if _, err := cc.apiClient.PutFile(cc.ctx, req); err != nil {
+			return err
+		}
+		offset += int64(n)
+		if done {
+			return nil
+		}
+	}
+}
+
+func (cc *controlCtxImpl) GetFile(src, dest string) error {
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+	meta := &api.InstructionMeta{
+		PeerFingerprint: cc.peer.Fingerprint,
+		StreamID:        id,
+	}
+	resp, err := cc.apiClient.GetFile(cc.ctx, &api.GetFileRequest{
+		Meta: meta,
+		Path: src,
+	})
+	if err != nil {
+		return err
+	}
+	info := resp.Info
+	if info == nil {
+		return fmt.Errorf("agent did not send file info")
+	}
+
+	// Regular files are written next to their destination so they can be
+	// moved into place once the checksum has been verified.
+	dir := filepath.Dir(dest)
+	if info.Directory {
+		dir = ""
+	}
+	f, err := os.CreateTemp(dir, ".post-init-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	h := sha256.New()
+	w := io.MultiWriter(f, h)
+	var offset int64
+	for {
+		if _, err := w.Write(resp.Data); err != nil {
+			return err
+		}
+		offset += int64(len(resp.Data))
+		if resp.EOF {
+			break
+		}
+		resp, err = cc.apiClient.GetFile(cc.ctx, &api.GetFileRequest{
+			Meta:   meta,
+			Offset: offset,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if offset != info.Size {
+		return fmt.Errorf("expected %d bytes, got %d", info.Size, offset)
+	}
+	if hex.EncodeToString(h.Sum(nil)) != info.Checksum {
+		return fmt.Errorf("checksum mismatch")
+	}
+
+	if info.Directory {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		return util.ExtractTar(f, dest)
+	}
+	if err := f.Chmod(fs.FileMode(info.Mode).Perm()); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), dest)
+}
@@ -0,0 +1,48 @@
+package sdk
+
+import (
+	"context"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AnnouncementHistory returns records of agents which announced within the
+// given time range and on which the client's key is authorized, including
+// agents which have since disconnected. A zero until time means the range
+// extends to the present.
+func (rc *RelayClient) AnnouncementHistory(
+	ctx context.Context,
+	since, until time.Time,
+) ([]*api.AnnouncementRecord, error) {
+	history, err := rc.apiClient.ListAnnouncementHistory(ctx, newTimeRange(since, until))
+	if err != nil {
+		return nil, err
+	}
+	return history.Items, nil
+}
+
+// AuditLog returns records of the instructions sent by the client's key
+// within the given time range. A zero until time means the range extends to
+// the present.
+func (rc *RelayClient) AuditLog(
+	ctx context.Context,
+	since, until time.Time,
+) ([]*api.AuditRecord, error) {
+	log, err := rc.apiClient.GetAuditLog(ctx, newTimeRange(since, until))
+	if err != nil {
+		return nil, err
+	}
+	return log.Items, nil
+}
+
+func newTimeRange(since, until time.Time) *api.TimeRange {
+	tr := &api.TimeRange{
+		Since: timestamppb.New(since),
+	}
+	if !until.IsZero() {
+		tr.Until = timestamppb.New(until)
+	}
+	return tr
+}
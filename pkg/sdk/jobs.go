@@ -0,0 +1,79 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CreateJob stores a job on the relay. The relay runs the job once on every
+// agent matching the selector (a *api.BasicFilter or *api.Expression) on
+// which the client's key is authorized, both agents already connected and
+// ones which announce later. The returned job has its ID set.
+func (rc *RelayClient) CreateJob(
+	ctx context.Context,
+	selector api.Selector,
+	steps ...*api.JobStep,
+) (*api.Job, error) {
+	job := &api.Job{
+		Steps: steps,
+	}
+	switch s := selector.(type) {
+	case *api.BasicFilter:
+		job.Filter = s
+	case *api.Expression:
+		job.Expression = s
+	default:
+		return nil, fmt.Errorf("unsupported selector type %T", selector)
+	}
+	return rc.apiClient.CreateJob(ctx, job)
+}
+
+// ListJobs returns all jobs created by the client's key.
+func (rc *RelayClient) ListJobs(ctx context.Context) ([]*api.Job, error) {
+	list, err := rc.apiClient.ListJobs(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// DeleteJob deletes a job and its results. Running instances of the job are
+// not interrupted.
+func (rc *RelayClient) DeleteJob(ctx context.Context, id string) error {
+	_, err := rc.apiClient.DeleteJob(ctx, &api.JobReference{
+		ID: id,
+	})
+	return err
+}
+
+// JobResults returns the results of each agent the job has run on.
+func (rc *RelayClient) JobResults(ctx context.Context, id string) ([]*api.JobResult, error) {
+	list, err := rc.apiClient.GetJobResults(ctx, &api.JobReference{
+		ID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// CommandStep returns a job step which runs the given command.
+func CommandStep(cmd *api.Command) *api.JobStep {
+	return &api.JobStep{
+		Step: &api.JobStep_Command{
+			Command: cmd,
+		},
+	}
+}
+
+// ScriptStep returns a job step which runs the given script.
+func ScriptStep(script *api.Script) *api.JobStep {
+	return &api.JobStep{
+		Step: &api.JobStep_Script{
+			Script: script,
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OutputHandler is called in order for each event produced by a streamed
+// instruction. The last event in a stream contains the exit status.
+type OutputHandler func(*api.OutputEvent)
+
+type outputHandlers struct {
+	mu       sync.Mutex
+	handlers map[string]OutputHandler
+}
+
+func newOutputHandlers() *outputHandlers {
+	return &outputHandlers{
+		handlers: make(map[string]OutputHandler),
+	}
+}
+
+// newID returns a random ID used to identify streamed instructions and watches.
+func newID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// add registers the handler under a new random stream ID, which is returned.
+func (h *outputHandlers) add(handler OutputHandler) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[id] = handler
+	return id, nil
+}
+
+func (h *outputHandlers) remove(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.handlers, id)
+}
+
+func (h *outputHandlers) dispatch(ev *api.OutputEvent) error {
+	h.mu.Lock()
+	handler, ok := h.handlers[ev.StreamID]
+	h.mu.Unlock()
+	if !ok {
+		return status.Error(codes.NotFound, "unknown stream")
+	}
+	handler(ev)
+	return nil
+}
@@ -0,0 +1,13 @@
+package sdk_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSDK(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SDK Suite")
+}
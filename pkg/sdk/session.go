@@ -14,15 +14,21 @@ import (
 type session struct {
 	api.UnimplementedWatchServer
 	api.UnimplementedKeyExchangeServer
+	api.UnimplementedOutputStreamServer
+	api.UnimplementedBroadcastStreamServer
 
-	apiClient api.ClientAPIClient
-	conf      *ClientConfig
-	kexState  *KeyExchangeState
-	notifyC   chan ControlContext
+	apiClient  api.ClientAPIClient
+	conf       *ClientConfig
+	kexState   *KeyExchangeState
+	watches    *watchCallbacks
+	outputs    *outputHandlers
+	broadcasts *broadcastHandlers
 }
 
 var _ api.WatchServer = (*session)(nil)
 var _ api.KeyExchangeServer = (*session)(nil)
+var _ api.OutputStreamServer = (*session)(nil)
+var _ api.BroadcastStreamServer = (*session)(nil)
 
 func (rc *session) ExchangeKeys(ctx context.Context, in *api.KexRequest) (*api.KexResponse, error) {
 	priv, pub, err := kex.GenerateKeyPair()
@@ -59,12 +65,52 @@ func (rc *session) Sign(ctx context.Context, in *api.SignRequest) (*api.SignResp
 	}, nil
 }
 
-func (rc *session) Notify(ctx context.Context, an *api.Announcement) (*emptypb.Empty, error) {
+func (rc *session) Notify(ctx context.Context, ev *api.WatchEvent) (*emptypb.Empty, error) {
+	if ev.Type == api.WatchEventType_Closed {
+		// Not about an agent, so there is nothing to control
+		if err := rc.watches.dispatch(ev, nil); err != nil {
+			return nil, err
+		}
+		return &emptypb.Empty{}, nil
+	}
+	if ev.Fingerprint == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing fingerprint")
+	}
+	peer := &Peer{
+		Fingerprint: ev.Fingerprint,
+	}
+	if ev.Announcement != nil {
+		var err error
+		peer, err = NewPeer(ev.Announcement)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if peer.Fingerprint != ev.Fingerprint {
+			return nil, status.Error(codes.InvalidArgument, "fingerprint does not match announcement")
+		}
+	}
 	ctrlCtx := &controlCtxImpl{
-		ctx:          ctx,
-		apiClient:    rc.apiClient,
-		announcement: an,
+		ctx:       ctx,
+		apiClient: rc.apiClient,
+		peer:      peer,
+		outputs:   rc.outputs,
+	}
+	if err := rc.watches.dispatch(ev, ctrlCtx); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (rc *session) Write(ctx context.Context, ev *api.OutputEvent) (*emptypb.Empty, error) {
+	if err := rc.outputs.dispatch(ev); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (rc *session) Result(ctx context.Context, result *api.BroadcastResult) (*emptypb.Empty, error) {
+	if err := rc.broadcasts.dispatch(result); err != nil {
+		return nil, err
 	}
-	rc.notifyC <- ctrlCtx
 	return &emptypb.Empty{}, nil
 }
@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"sync"
+
+	"github.com/kralicky/post-init/pkg/api"
+)
+
+// ShellSession is an interactive shell running on an agent.
+type ShellSession struct {
+	cc   *controlCtxImpl
+	meta *api.InstructionMeta
+
+	readyOnce sync.Once
+	ready     chan struct{}
+	done      chan struct{}
+	err       error
+}
+
+// Write sends input to the shell. It blocks until the shell has started.
+func (s *ShellSession) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := s.send(&api.ShellInputRequest{
+		Input: &api.ShellInputRequest_Data{
+			Data: data,
+		},
+	}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Resize changes the window size of the shell's terminal.
+func (s *ShellSession) Resize(rows, cols uint32) error {
+	return s.send(&api.ShellInputRequest{
+		Input: &api.ShellInputRequest_Resize{
+			Resize: &api.WindowSize{
+				Rows: rows,
+				Cols: cols,
+			},
+		},
+	})
+}
+
+// Signal sends a signal to the shell. The signal name should not contain the
+// SIG prefix, e.g. "INT" or "TERM".
+func (s *ShellSession) Signal(name string) error {
+	return s.send(&api.ShellInputRequest{
+		Input: &api.ShellInputRequest_Signal{
+			Signal: name,
+		},
+	})
+}
+
+// Wait blocks until the shell exits. The exit status is delivered to the
+// output handler the shell was started with.
+func (s *ShellSession) Wait() error {
+	<-s.done
+	return s.err
+}
+
+func (s *ShellSession) send(req *api.ShellInputRequest) error {
+	select {
+	case <-s.ready:
+	case <-s.done:
+		return s.err
+	}
+	req.Meta = s.meta
+	_, err := s.cc.apiClient.ShellInput(s.cc.ctx, req)
+	return err
+}
+
+func (s *ShellSession) markReady() {
+	s.readyOnce.Do(func() {
+		close(s.ready)
+	})
+}
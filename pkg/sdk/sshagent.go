@@ -0,0 +1,61 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// ErrNoSSHAgent is returned by DialSSHAgent if SSH_AUTH_SOCK is not set.
+var ErrNoSSHAgent = errors.New("SSH_AUTH_SOCK is not set")
+
+// DialSSHAgent connects to the ssh-agent listening on $SSH_AUTH_SOCK. The
+// returned closer closes the connection to the agent, after which signers
+// obtained from it can no longer be used.
+func DialSSHAgent() (agent.ExtendedAgent, io.Closer, error) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, nil, ErrNoSSHAgent
+	}
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to ssh-agent: %w", err)
+	}
+	return agent.NewClient(conn), conn, nil
+}
+
+// SSHAgentSigner returns a signer for the key held by the ssh-agent with the
+// given SHA256 fingerprint (SHA256:...). Private keys never leave the agent,
+// so this works with encrypted and hardware-backed keys. If fingerprint is
+// empty, the agent must hold exactly one key.
+func SSHAgentSigner(a agent.Agent, fingerprint string) (ssh.Signer, error) {
+	signers, err := a.Signers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ssh-agent keys: %w", err)
+	}
+	if len(signers) == 0 {
+		return nil, errors.New("ssh-agent has no keys")
+	}
+	available := make([]string, len(signers))
+	for i, signer := range signers {
+		available[i] = ssh.FingerprintSHA256(signer.PublicKey())
+		if fingerprint != "" && available[i] == fingerprint {
+			return signer, nil
+		}
+	}
+	if fingerprint == "" {
+		if len(signers) == 1 {
+			return signers[0], nil
+		}
+		return nil, fmt.Errorf("ssh-agent has %d keys, select one by fingerprint (available keys: %s)",
+			len(signers), strings.Join(available, ", "))
+	}
+	return nil, fmt.Errorf("key %s not found in ssh-agent (available keys: %s)",
+		fingerprint, strings.Join(available, ", "))
+}
@@ -0,0 +1,108 @@
+package sdk_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/kralicky/post-init/pkg/kex"
+	"github.com/kralicky/post-init/pkg/sdk"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+var _ = Describe("ssh-agent Signers", func() {
+	newKey := func() (ed25519.PrivateKey, string) {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		sshPub, err := ssh.NewPublicKey(pub)
+		Expect(err).NotTo(HaveOccurred())
+		return priv, ssh.FingerprintSHA256(sshPub)
+	}
+	var keyring agent.Agent
+	var fp1, fp2 string
+	BeforeEach(func() {
+		keyring = agent.NewKeyring()
+		var key1, key2 ed25519.PrivateKey
+		key1, fp1 = newKey()
+		key2, fp2 = newKey()
+		Expect(keyring.Add(agent.AddedKey{PrivateKey: key1})).To(Succeed())
+		Expect(keyring.Add(agent.AddedKey{PrivateKey: key2})).To(Succeed())
+	})
+
+	It("should select keys by fingerprint", func() {
+		signer, err := sdk.SSHAgentSigner(keyring, fp2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ssh.FingerprintSHA256(signer.PublicKey())).To(Equal(fp2))
+	})
+	It("should produce signatures which the relay can verify", func() {
+		signer, err := sdk.SSHAgentSigner(keyring, fp1)
+		Expect(err).NotTo(HaveOccurred())
+		nonce, server, client, secret := []byte("nonce"), []byte("server"), []byte("client"), []byte("secret")
+		sig, err := kex.Sign(signer, nonce, server, client, secret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kex.Verify(sig, nonce, server, client, signer.PublicKey(), secret)).To(Succeed())
+	})
+	It("should fail if the key is not in the agent", func() {
+		_, fp := newKey()
+		_, err := sdk.SSHAgentSigner(keyring, fp)
+		Expect(err).To(MatchError(ContainSubstring(fp1)))
+	})
+	It("should require a fingerprint if the agent holds more than one key", func() {
+		_, err := sdk.SSHAgentSigner(keyring, "")
+		Expect(err).To(HaveOccurred())
+
+		Expect(keyring.Remove(mustPublicKey(keyring, fp2))).To(Succeed())
+		signer, err := sdk.SSHAgentSigner(keyring, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ssh.FingerprintSHA256(signer.PublicKey())).To(Equal(fp1))
+	})
+	It("should connect to the agent at SSH_AUTH_SOCK", func() {
+		sock := filepath.Join(GinkgoT().TempDir(), "agent.sock")
+		l, err := net.Listen("unix", sock)
+		Expect(err).NotTo(HaveOccurred())
+		defer l.Close()
+		go func() {
+			for {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				go agent.ServeAgent(keyring, conn)
+			}
+		}()
+		prev, hadPrev := os.LookupEnv("SSH_AUTH_SOCK")
+		os.Setenv("SSH_AUTH_SOCK", sock)
+		defer func() {
+			if hadPrev {
+				os.Setenv("SSH_AUTH_SOCK", prev)
+			} else {
+				os.Unsetenv("SSH_AUTH_SOCK")
+			}
+		}()
+
+		a, closer, err := sdk.DialSSHAgent()
+		Expect(err).NotTo(HaveOccurred())
+		defer closer.Close()
+		signer, err := sdk.SSHAgentSigner(a, fp1)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = signer.Sign(rand.Reader, []byte("data"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+func mustPublicKey(a agent.Agent, fingerprint string) ssh.PublicKey {
+	keys, err := a.List()
+	Expect(err).NotTo(HaveOccurred())
+	for _, k := range keys {
+		if ssh.FingerprintSHA256(k) == fingerprint {
+			return k
+		}
+	}
+	Fail("key not found: " + fingerprint)
+	return nil
+}
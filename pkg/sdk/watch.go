@@ -0,0 +1,140 @@
+package sdk
+
+import (
+	"sync"
+
+	"github.com/kralicky/post-init/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchCallbacks routes watch events to the callback of the watch which
+// matched the agent.
+type watchCallbacks struct {
+	mu     sync.Mutex
+	queues map[string]*watchQueue
+}
+
+func newWatchCallbacks() *watchCallbacks {
+	return &watchCallbacks{
+		queues: make(map[string]*watchQueue),
+	}
+}
+
+// add registers the callback under a new random watch ID, which is returned.
+func (w *watchCallbacks) add(callback EventCallback) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+	q := newWatchQueue(callback)
+	w.mu.Lock()
+	w.queues[id] = q
+	w.mu.Unlock()
+	go q.run()
+	return id, nil
+}
+
+// remove stops the watch's queue. Events which have not been delivered yet
+// are discarded.
+func (w *watchCallbacks) remove(id string) {
+	w.mu.Lock()
+	q, ok := w.queues[id]
+	delete(w.queues, id)
+	w.mu.Unlock()
+	if ok {
+		q.stop()
+	}
+}
+
+// dispatch queues the event for the watch's callback. It does not wait for
+// the callback to be called. A Closed event is the last event of its watch,
+// which is forgotten once the event has been queued.
+func (w *watchCallbacks) dispatch(ev *api.WatchEvent, cc ControlContext) error {
+	w.mu.Lock()
+	q, ok := w.queues[ev.WatchID]
+	if ok && ev.Type == api.WatchEventType_Closed {
+		delete(w.queues, ev.WatchID)
+	}
+	w.mu.Unlock()
+	if !ok {
+		return status.Error(codes.NotFound, "unknown watch")
+	}
+	q.push(ev, cc)
+	if ev.Type == api.WatchEventType_Closed {
+		q.finish()
+	}
+	return nil
+}
+
+type watchEvent struct {
+	ev *api.WatchEvent
+	cc ControlContext
+}
+
+// watchQueue calls a watch's callback from a single goroutine, one event at
+// a time, in the order the events were received. Events for an agent are
+// therefore seen in the order the relay sent them, for example Connected
+// before Disconnected.
+type watchQueue struct {
+	callback EventCallback
+	mu       sync.Mutex
+	cond     *sync.Cond
+	events   []watchEvent
+	// Set by finish; queued events are still delivered
+	finished bool
+	// Set by stop; queued events are discarded
+	stopped bool
+}
+
+func newWatchQueue(callback EventCallback) *watchQueue {
+	q := &watchQueue{
+		callback: callback,
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *watchQueue) push(ev *api.WatchEvent, cc ControlContext) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped || q.finished {
+		return
+	}
+	q.events = append(q.events, watchEvent{ev: ev, cc: cc})
+	q.cond.Signal()
+}
+
+// finish stops the queue once the events already queued have been delivered.
+func (q *watchQueue) finish() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.finished = true
+	q.cond.Signal()
+}
+
+func (q *watchQueue) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stopped = true
+	q.events = nil
+	q.cond.Signal()
+}
+
+func (q *watchQueue) run() {
+	for {
+		q.mu.Lock()
+		for len(q.events) == 0 && !q.stopped && !q.finished {
+			q.cond.Wait()
+		}
+		if q.stopped || len(q.events) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		next := q.events[0]
+		q.events[0] = watchEvent{}
+		q.events = q.events[1:]
+		q.mu.Unlock()
+		q.callback(next.ev, next.cc)
+	}
+}
@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"time"
+
+	"github.com/kralicky/post-init/pkg/api"
+	// Not dot-imported, since the SDK has its own Label and Not
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Watch Callbacks", func() {
+	ginkgo.It("should deliver each watch's events in order", func() {
+		w := newWatchCallbacks()
+		received := make(chan string, 100)
+		id, err := w.add(func(ev *api.WatchEvent, _ ControlContext) {
+			// Later events must not overtake a slow callback
+			time.Sleep(time.Millisecond)
+			received <- ev.Fingerprint
+		})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		ginkgo.DeferCleanup(w.remove, id)
+
+		expected := []string{}
+		for _, fp := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			gomega.Expect(w.dispatch(&api.WatchEvent{WatchID: id, Fingerprint: fp}, nil)).To(gomega.Succeed())
+			expected = append(expected, fp)
+		}
+		for _, fp := range expected {
+			gomega.Eventually(received).Should(gomega.Receive(gomega.Equal(fp)))
+		}
+	})
+
+	ginkgo.It("should discard undelivered events when a watch is removed", func() {
+		w := newWatchCallbacks()
+		block := make(chan struct{})
+		calls := make(chan string, 10)
+		id, err := w.add(func(ev *api.WatchEvent, _ ControlContext) {
+			calls <- ev.Fingerprint
+			<-block
+		})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(w.dispatch(&api.WatchEvent{WatchID: id, Fingerprint: "a"}, nil)).To(gomega.Succeed())
+		gomega.Eventually(calls).Should(gomega.Receive(gomega.Equal("a")))
+		gomega.Expect(w.dispatch(&api.WatchEvent{WatchID: id, Fingerprint: "b"}, nil)).To(gomega.Succeed())
+		w.remove(id)
+		close(block)
+		gomega.Consistently(calls, 100*time.Millisecond).ShouldNot(gomega.Receive())
+
+		gomega.Expect(w.dispatch(&api.WatchEvent{WatchID: id}, nil)).NotTo(gomega.Succeed())
+	})
+
+	ginkgo.It("should deliver the Closed event last and forget the watch", func() {
+		w := newWatchCallbacks()
+		received := make(chan api.WatchEventType, 10)
+		id, err := w.add(func(ev *api.WatchEvent, _ ControlContext) {
+			received <- ev.Type
+		})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(w.dispatch(&api.WatchEvent{WatchID: id, Type: api.WatchEventType_Connected}, nil)).To(gomega.Succeed())
+		gomega.Expect(w.dispatch(&api.WatchEvent{WatchID: id, Type: api.WatchEventType_Closed}, nil)).To(gomega.Succeed())
+		gomega.Expect(w.dispatch(&api.WatchEvent{WatchID: id, Type: api.WatchEventType_Connected}, nil)).NotTo(gomega.Succeed())
+		gomega.Eventually(received).Should(gomega.Receive(gomega.Equal(api.WatchEventType_Connected)))
+		gomega.Eventually(received).Should(gomega.Receive(gomega.Equal(api.WatchEventType_Closed)))
+		gomega.Consistently(received, 100*time.Millisecond).ShouldNot(gomega.Receive())
+	})
+})
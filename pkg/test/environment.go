@@ -155,6 +155,7 @@ func (e *Environment) SpawnRelay() {
 		relay.Insecure(false),
 		relay.ListenAddress(e.RelayAddr),
 		relay.ServingCerts(e.Certs.CertBundle, e.Certs.Key),
+		relay.DataDir(filepath.Join(e.TempDir, "relay")),
 	)
 	go func() {
 		defer GinkgoRecover()
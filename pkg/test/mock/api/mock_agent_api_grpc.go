@@ -11,6 +11,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	api "github.com/kralicky/post-init/pkg/api"
 	grpc "google.golang.org/grpc"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // MockAgentAPIClient is a mock of AgentAPIClient interface.
@@ -56,6 +57,46 @@ func (mr *MockAgentAPIClientMockRecorder) Announce(ctx, in interface{}, opts ...
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Announce", reflect.TypeOf((*MockAgentAPIClient)(nil).Announce), varargs...)
 }
 
+// UpdateStatus mocks base method.
+func (m *MockAgentAPIClient) UpdateStatus(ctx context.Context, in *api.StatusUpdate, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateStatus", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockAgentAPIClientMockRecorder) UpdateStatus(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockAgentAPIClient)(nil).UpdateStatus), varargs...)
+}
+
+// WriteOutput mocks base method.
+func (m *MockAgentAPIClient) WriteOutput(ctx context.Context, in *api.OutputEvent, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WriteOutput", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WriteOutput indicates an expected call of WriteOutput.
+func (mr *MockAgentAPIClientMockRecorder) WriteOutput(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteOutput", reflect.TypeOf((*MockAgentAPIClient)(nil).WriteOutput), varargs...)
+}
+
 // MockAgentAPIServer is a mock of AgentAPIServer interface.
 type MockAgentAPIServer struct {
 	ctrl     *gomock.Controller
@@ -94,6 +135,36 @@ func (mr *MockAgentAPIServerMockRecorder) Announce(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Announce", reflect.TypeOf((*MockAgentAPIServer)(nil).Announce), arg0, arg1)
 }
 
+// UpdateStatus mocks base method.
+func (m *MockAgentAPIServer) UpdateStatus(arg0 context.Context, arg1 *api.StatusUpdate) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockAgentAPIServerMockRecorder) UpdateStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockAgentAPIServer)(nil).UpdateStatus), arg0, arg1)
+}
+
+// WriteOutput mocks base method.
+func (m *MockAgentAPIServer) WriteOutput(arg0 context.Context, arg1 *api.OutputEvent) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteOutput", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WriteOutput indicates an expected call of WriteOutput.
+func (mr *MockAgentAPIServerMockRecorder) WriteOutput(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteOutput", reflect.TypeOf((*MockAgentAPIServer)(nil).WriteOutput), arg0, arg1)
+}
+
 // mustEmbedUnimplementedAgentAPIServer mocks base method.
 func (m *MockAgentAPIServer) mustEmbedUnimplementedAgentAPIServer() {
 	m.ctrl.T.Helper()
@@ -164,6 +235,26 @@ func (m *MockInstructionClient) EXPECT() *MockInstructionClientMockRecorder {
 	return m.recorder
 }
 
+// Cancel mocks base method.
+func (m *MockInstructionClient) Cancel(ctx context.Context, in *api.CancelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Cancel", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockInstructionClientMockRecorder) Cancel(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockInstructionClient)(nil).Cancel), varargs...)
+}
+
 // Command mocks base method.
 func (m *MockInstructionClient) Command(ctx context.Context, in *api.CommandRequest, opts ...grpc.CallOption) (*api.CommandResponse, error) {
 	m.ctrl.T.Helper()
@@ -184,6 +275,66 @@ func (mr *MockInstructionClientMockRecorder) Command(ctx, in interface{}, opts .
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Command", reflect.TypeOf((*MockInstructionClient)(nil).Command), varargs...)
 }
 
+// CommandStream mocks base method.
+func (m *MockInstructionClient) CommandStream(ctx context.Context, in *api.CommandRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CommandStream", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommandStream indicates an expected call of CommandStream.
+func (mr *MockInstructionClientMockRecorder) CommandStream(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommandStream", reflect.TypeOf((*MockInstructionClient)(nil).CommandStream), varargs...)
+}
+
+// GetFile mocks base method.
+func (m *MockInstructionClient) GetFile(ctx context.Context, in *api.GetFileRequest, opts ...grpc.CallOption) (*api.GetFileResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFile", varargs...)
+	ret0, _ := ret[0].(*api.GetFileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockInstructionClientMockRecorder) GetFile(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockInstructionClient)(nil).GetFile), varargs...)
+}
+
+// PutFile mocks base method.
+func (m *MockInstructionClient) PutFile(ctx context.Context, in *api.PutFileRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutFile", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutFile indicates an expected call of PutFile.
+func (mr *MockInstructionClientMockRecorder) PutFile(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutFile", reflect.TypeOf((*MockInstructionClient)(nil).PutFile), varargs...)
+}
+
 // Script mocks base method.
 func (m *MockInstructionClient) Script(ctx context.Context, in *api.ScriptRequest, opts ...grpc.CallOption) (*api.ScriptResponse, error) {
 	m.ctrl.T.Helper()
@@ -204,6 +355,66 @@ func (mr *MockInstructionClientMockRecorder) Script(ctx, in interface{}, opts ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Script", reflect.TypeOf((*MockInstructionClient)(nil).Script), varargs...)
 }
 
+// ScriptStream mocks base method.
+func (m *MockInstructionClient) ScriptStream(ctx context.Context, in *api.ScriptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ScriptStream", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScriptStream indicates an expected call of ScriptStream.
+func (mr *MockInstructionClientMockRecorder) ScriptStream(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScriptStream", reflect.TypeOf((*MockInstructionClient)(nil).ScriptStream), varargs...)
+}
+
+// Shell mocks base method.
+func (m *MockInstructionClient) Shell(ctx context.Context, in *api.ShellRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Shell", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Shell indicates an expected call of Shell.
+func (mr *MockInstructionClientMockRecorder) Shell(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shell", reflect.TypeOf((*MockInstructionClient)(nil).Shell), varargs...)
+}
+
+// ShellInput mocks base method.
+func (m *MockInstructionClient) ShellInput(ctx context.Context, in *api.ShellInputRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ShellInput", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShellInput indicates an expected call of ShellInput.
+func (mr *MockInstructionClientMockRecorder) ShellInput(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShellInput", reflect.TypeOf((*MockInstructionClient)(nil).ShellInput), varargs...)
+}
+
 // MockInstructionServer is a mock of InstructionServer interface.
 type MockInstructionServer struct {
 	ctrl     *gomock.Controller
@@ -227,6 +438,21 @@ func (m *MockInstructionServer) EXPECT() *MockInstructionServerMockRecorder {
 	return m.recorder
 }
 
+// Cancel mocks base method.
+func (m *MockInstructionServer) Cancel(arg0 context.Context, arg1 *api.CancelRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockInstructionServerMockRecorder) Cancel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockInstructionServer)(nil).Cancel), arg0, arg1)
+}
+
 // Command mocks base method.
 func (m *MockInstructionServer) Command(arg0 context.Context, arg1 *api.CommandRequest) (*api.CommandResponse, error) {
 	m.ctrl.T.Helper()
@@ -242,6 +468,51 @@ func (mr *MockInstructionServerMockRecorder) Command(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Command", reflect.TypeOf((*MockInstructionServer)(nil).Command), arg0, arg1)
 }
 
+// CommandStream mocks base method.
+func (m *MockInstructionServer) CommandStream(arg0 context.Context, arg1 *api.CommandRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommandStream", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommandStream indicates an expected call of CommandStream.
+func (mr *MockInstructionServerMockRecorder) CommandStream(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommandStream", reflect.TypeOf((*MockInstructionServer)(nil).CommandStream), arg0, arg1)
+}
+
+// GetFile mocks base method.
+func (m *MockInstructionServer) GetFile(arg0 context.Context, arg1 *api.GetFileRequest) (*api.GetFileResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFile", arg0, arg1)
+	ret0, _ := ret[0].(*api.GetFileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockInstructionServerMockRecorder) GetFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockInstructionServer)(nil).GetFile), arg0, arg1)
+}
+
+// PutFile mocks base method.
+func (m *MockInstructionServer) PutFile(arg0 context.Context, arg1 *api.PutFileRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutFile", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutFile indicates an expected call of PutFile.
+func (mr *MockInstructionServerMockRecorder) PutFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutFile", reflect.TypeOf((*MockInstructionServer)(nil).PutFile), arg0, arg1)
+}
+
 // Script mocks base method.
 func (m *MockInstructionServer) Script(arg0 context.Context, arg1 *api.ScriptRequest) (*api.ScriptResponse, error) {
 	m.ctrl.T.Helper()
@@ -257,6 +528,51 @@ func (mr *MockInstructionServerMockRecorder) Script(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Script", reflect.TypeOf((*MockInstructionServer)(nil).Script), arg0, arg1)
 }
 
+// ScriptStream mocks base method.
+func (m *MockInstructionServer) ScriptStream(arg0 context.Context, arg1 *api.ScriptRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScriptStream", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScriptStream indicates an expected call of ScriptStream.
+func (mr *MockInstructionServerMockRecorder) ScriptStream(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScriptStream", reflect.TypeOf((*MockInstructionServer)(nil).ScriptStream), arg0, arg1)
+}
+
+// Shell mocks base method.
+func (m *MockInstructionServer) Shell(arg0 context.Context, arg1 *api.ShellRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shell", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Shell indicates an expected call of Shell.
+func (mr *MockInstructionServerMockRecorder) Shell(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shell", reflect.TypeOf((*MockInstructionServer)(nil).Shell), arg0, arg1)
+}
+
+// ShellInput mocks base method.
+func (m *MockInstructionServer) ShellInput(arg0 context.Context, arg1 *api.ShellInputRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShellInput", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShellInput indicates an expected call of ShellInput.
+func (mr *MockInstructionServerMockRecorder) ShellInput(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShellInput", reflect.TypeOf((*MockInstructionServer)(nil).ShellInput), arg0, arg1)
+}
+
 // mustEmbedUnimplementedInstructionServer mocks base method.
 func (m *MockInstructionServer) mustEmbedUnimplementedInstructionServer() {
 	m.ctrl.T.Helper()
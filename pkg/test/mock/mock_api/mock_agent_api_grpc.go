@@ -37,6 +37,26 @@ func (m *MockInstructionClient) EXPECT() *MockInstructionClientMockRecorder {
 	return m.recorder
 }
 
+// Cancel mocks base method.
+func (m *MockInstructionClient) Cancel(arg0 context.Context, arg1 *api.CancelRequest, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Cancel", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockInstructionClientMockRecorder) Cancel(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockInstructionClient)(nil).Cancel), varargs...)
+}
+
 // Command mocks base method.
 func (m *MockInstructionClient) Command(arg0 context.Context, arg1 *api.CommandRequest, arg2 ...grpc.CallOption) (*api.CommandResponse, error) {
 	m.ctrl.T.Helper()
@@ -57,24 +77,64 @@ func (mr *MockInstructionClientMockRecorder) Command(arg0, arg1 interface{}, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Command", reflect.TypeOf((*MockInstructionClient)(nil).Command), varargs...)
 }
 
-// Dismiss mocks base method.
-func (m *MockInstructionClient) Dismiss(arg0 context.Context, arg1 *emptypb.Empty, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
+// CommandStream mocks base method.
+func (m *MockInstructionClient) CommandStream(arg0 context.Context, arg1 *api.CommandRequest, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CommandStream", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommandStream indicates an expected call of CommandStream.
+func (mr *MockInstructionClientMockRecorder) CommandStream(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommandStream", reflect.TypeOf((*MockInstructionClient)(nil).CommandStream), varargs...)
+}
+
+// GetFile mocks base method.
+func (m *MockInstructionClient) GetFile(arg0 context.Context, arg1 *api.GetFileRequest, arg2 ...grpc.CallOption) (*api.GetFileResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "Dismiss", varargs...)
+	ret := m.ctrl.Call(m, "GetFile", varargs...)
+	ret0, _ := ret[0].(*api.GetFileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockInstructionClientMockRecorder) GetFile(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockInstructionClient)(nil).GetFile), varargs...)
+}
+
+// PutFile mocks base method.
+func (m *MockInstructionClient) PutFile(arg0 context.Context, arg1 *api.PutFileRequest, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutFile", varargs...)
 	ret0, _ := ret[0].(*emptypb.Empty)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Dismiss indicates an expected call of Dismiss.
-func (mr *MockInstructionClientMockRecorder) Dismiss(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// PutFile indicates an expected call of PutFile.
+func (mr *MockInstructionClientMockRecorder) PutFile(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dismiss", reflect.TypeOf((*MockInstructionClient)(nil).Dismiss), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutFile", reflect.TypeOf((*MockInstructionClient)(nil).PutFile), varargs...)
 }
 
 // Script mocks base method.
@@ -96,3 +156,63 @@ func (mr *MockInstructionClientMockRecorder) Script(arg0, arg1 interface{}, arg2
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Script", reflect.TypeOf((*MockInstructionClient)(nil).Script), varargs...)
 }
+
+// ScriptStream mocks base method.
+func (m *MockInstructionClient) ScriptStream(arg0 context.Context, arg1 *api.ScriptRequest, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ScriptStream", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScriptStream indicates an expected call of ScriptStream.
+func (mr *MockInstructionClientMockRecorder) ScriptStream(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScriptStream", reflect.TypeOf((*MockInstructionClient)(nil).ScriptStream), varargs...)
+}
+
+// Shell mocks base method.
+func (m *MockInstructionClient) Shell(arg0 context.Context, arg1 *api.ShellRequest, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Shell", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Shell indicates an expected call of Shell.
+func (mr *MockInstructionClientMockRecorder) Shell(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shell", reflect.TypeOf((*MockInstructionClient)(nil).Shell), varargs...)
+}
+
+// ShellInput mocks base method.
+func (m *MockInstructionClient) ShellInput(arg0 context.Context, arg1 *api.ShellInputRequest, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ShellInput", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShellInput indicates an expected call of ShellInput.
+func (mr *MockInstructionClientMockRecorder) ShellInput(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShellInput", reflect.TypeOf((*MockInstructionClient)(nil).ShellInput), varargs...)
+}
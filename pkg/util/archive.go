@@ -0,0 +1,208 @@
+package util
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Checksum reads r until EOF and returns the number of bytes read and the
+// hex encoded SHA256 checksum of its contents.
+func Checksum(r io.Reader) (int64, string, error) {
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteTar writes a tar archive containing the contents of dir to w. Paths
+// in the archive are relative to dir.
+func WriteTar(w io.Writer, dir string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// ExtractTar extracts a tar archive read from r into dir, creating dir if it
+// does not exist. Entries and symlinks which would point outside of dir are
+// rejected. Symlinks which already exist on disk, including ones created
+// earlier in the archive, are resolved before writing, so they cannot be used
+// to write outside of dir either. Entry types other than directories, regular
+// files and symlinks are skipped.
+func ExtractTar(r io.Reader, dir string) error {
+	dir = filepath.Clean(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	within := func(path string) bool {
+		return strings.HasPrefix(path, dir+string(os.PathSeparator))
+	}
+	// resolvesWithin returns true if path is root or inside of it once
+	// symlinks on disk are resolved.
+	resolvesWithin := func(path string) (string, bool) {
+		resolved, err := resolveExisting(path)
+		if err != nil {
+			return "", false
+		}
+		return resolved, resolved == root ||
+			strings.HasPrefix(resolved, root+string(os.PathSeparator))
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !within(target) {
+			return fmt.Errorf("invalid path in archive: %s", hdr.Name)
+		}
+		// Directories may be existing symlinks, which are followed. Files and
+		// symlinks replace existing symlinks, so only their parent is resolved.
+		check := filepath.Dir(target)
+		if hdr.Typeflag == tar.TypeDir {
+			check = target
+		}
+		parent, ok := resolvesWithin(check)
+		if !ok {
+			return fmt.Errorf("invalid path in archive: %s", hdr.Name)
+		}
+		mode := hdr.FileInfo().Mode().Perm()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode); err != nil {
+				return err
+			}
+			if err := os.Chmod(target, mode); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := removeSymlink(target); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+			if err := os.Chmod(target, mode); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if filepath.IsAbs(hdr.Linkname) ||
+				!within(filepath.Join(filepath.Dir(target), hdr.Linkname)) {
+				return fmt.Errorf("invalid symlink in archive: %s -> %s", hdr.Name, hdr.Linkname)
+			}
+			if _, ok := resolvesWithin(filepath.Join(parent, hdr.Linkname)); !ok {
+				return fmt.Errorf("invalid symlink in archive: %s -> %s", hdr.Name, hdr.Linkname)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			os.Remove(target)
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// resolveExisting resolves symlinks in the longest prefix of path which
+// exists, and appends the rest of path to it. An error is returned if the
+// prefix ends in a dangling symlink.
+func resolveExisting(path string) (string, error) {
+	rest := ""
+	for {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		if _, err := os.Lstat(path); err == nil {
+			return "", fmt.Errorf("dangling symlink: %s", path)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", err
+		}
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
+
+// removeSymlink removes path if it is a symlink, so that it is replaced
+// instead of written through.
+func removeSymlink(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+		return nil
+	}
+	return os.Remove(path)
+}
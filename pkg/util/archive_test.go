@@ -0,0 +1,112 @@
+package util_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/kralicky/post-init/pkg/util"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type entry struct {
+	name     string
+	typeflag byte
+	linkname string
+	contents string
+}
+
+func archive(entries ...entry) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, e := range entries {
+		Expect(tw.WriteHeader(&tar.Header{
+			Name:     e.name,
+			Typeflag: e.typeflag,
+			Linkname: e.linkname,
+			Mode:     0644,
+			Size:     int64(len(e.contents)),
+		})).To(Succeed())
+		_, err := tw.Write([]byte(e.contents))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	return buf
+}
+
+var _ = Describe("Archives", func() {
+	var parent, dest string
+	BeforeEach(func() {
+		parent = GinkgoT().TempDir()
+		dest = filepath.Join(parent, "dest")
+	})
+
+	It("should round trip directories", func() {
+		src := GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(src, "a", "b"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(src, "a", "b", "file"), []byte("hello"), 0600)).To(Succeed())
+		Expect(os.Symlink("b/file", filepath.Join(src, "a", "link"))).To(Succeed())
+
+		buf := new(bytes.Buffer)
+		Expect(util.WriteTar(buf, src)).To(Succeed())
+		Expect(util.ExtractTar(buf, dest)).To(Succeed())
+
+		data, err := os.ReadFile(filepath.Join(dest, "a", "link"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("hello"))
+		info, err := os.Stat(filepath.Join(dest, "a", "b", "file"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+	DescribeTable("entries outside of the destination",
+		func(entries ...entry) {
+			outside := filepath.Join(parent, "z")
+			Expect(os.Mkdir(outside, 0755)).To(Succeed())
+			Expect(util.ExtractTar(archive(entries...), dest)).NotTo(Succeed())
+			Expect(os.ReadDir(outside)).To(BeEmpty())
+		},
+		Entry("relative path",
+			entry{name: "../z/evil", typeflag: tar.TypeReg},
+		),
+		Entry("absolute symlink",
+			entry{name: "s", typeflag: tar.TypeSymlink, linkname: filepath.Join("/", "tmp")},
+		),
+		Entry("relative symlink",
+			entry{name: "s", typeflag: tar.TypeSymlink, linkname: "../z"},
+		),
+		Entry("chained symlinks",
+			entry{name: "m/", typeflag: tar.TypeDir},
+			entry{name: "n/", typeflag: tar.TypeDir},
+			entry{name: "m/L", typeflag: tar.TypeSymlink, linkname: "../n"},
+			entry{name: "m/L/s", typeflag: tar.TypeSymlink, linkname: "../../z"},
+			entry{name: "m/L/s/evil", typeflag: tar.TypeReg, contents: "evil"},
+		),
+	)
+	It("should not write through existing symlinks which leave the destination", func() {
+		Expect(os.MkdirAll(dest, 0755)).To(Succeed())
+		Expect(os.Symlink("..", filepath.Join(dest, "up"))).To(Succeed())
+		err := util.ExtractTar(archive(
+			entry{name: "up/z", typeflag: tar.TypeReg, contents: "evil"},
+		), dest)
+		Expect(err).To(HaveOccurred())
+		_, err = os.Lstat(filepath.Join(parent, "z"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+	It("should replace symlinks with files instead of writing through them", func() {
+		Expect(os.MkdirAll(dest, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(parent, "z"), []byte("outside"), 0644)).To(Succeed())
+		Expect(os.Symlink("../z", filepath.Join(dest, "f"))).To(Succeed())
+		Expect(util.ExtractTar(archive(
+			entry{name: "f", typeflag: tar.TypeReg, contents: "inside"},
+		), dest)).To(Succeed())
+
+		data, err := os.ReadFile(filepath.Join(parent, "z"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("outside"))
+		data, err = os.ReadFile(filepath.Join(dest, "f"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("inside"))
+	})
+})
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff computes delays between retries which grow exponentially from
+// Initial up to Max. Each delay is randomized to between half and all of the
+// current exponential value, so that many clients retrying at once do not all
+// retry at the same time.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+
+	current time.Duration
+}
+
+// Next returns the delay before the next retry.
+func (b *Backoff) Next() time.Duration {
+	if b.current == 0 {
+		b.current = b.Initial
+	} else {
+		b.current *= 2
+	}
+	if b.current > b.Max {
+		b.current = b.Max
+	}
+	half := b.current / 2
+	return half + time.Duration(rand.Int63n(int64(b.current-half)+1))
+}
+
+// Reset restarts the delays from Initial.
+func (b *Backoff) Reset() {
+	b.current = 0
+}
@@ -0,0 +1,68 @@
+package util_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kralicky/post-init/pkg/util"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shared Timer", func() {
+	It("should expire after the timeout", func() {
+		t := util.NewSharedTimer(100 * time.Millisecond)
+		Consistently(t.C(), 50*time.Millisecond, 10*time.Millisecond).ShouldNot(BeClosed())
+		Eventually(t.C()).Should(BeClosed())
+	})
+	It("should not expire while blocked", func() {
+		t := util.NewSharedTimer(100 * time.Millisecond)
+		t.Block()
+		Consistently(t.C(), 300*time.Millisecond).ShouldNot(BeClosed())
+		t.Unblock()
+		Eventually(t.C()).Should(BeClosed())
+	})
+	It("should be unblocked immediately after it is created", func() {
+		t := util.NewSharedTimer(time.Hour)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			t.Block()
+			t.Unblock()
+		}()
+		Eventually(done).Should(BeClosed())
+	})
+	It("should wait for every holder to unblock it", func() {
+		t := util.NewSharedTimer(100 * time.Millisecond)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			t.Block()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				time.Sleep(10 * time.Millisecond)
+				t.Unblock()
+			}()
+		}
+		t.Block()
+		wg.Wait()
+		Consistently(t.C(), 300*time.Millisecond).ShouldNot(BeClosed())
+		t.Unblock()
+		Eventually(t.C()).Should(BeClosed())
+	})
+	It("should restart the timeout when the last holder unblocks it", func() {
+		t := util.NewSharedTimer(200 * time.Millisecond)
+		time.Sleep(150 * time.Millisecond)
+		t.Block()
+		t.Unblock()
+		Consistently(t.C(), 150*time.Millisecond).ShouldNot(BeClosed())
+		Eventually(t.C()).Should(BeClosed())
+	})
+	It("should ignore blocks after expiring", func() {
+		t := util.NewSharedTimer(10 * time.Millisecond)
+		Eventually(t.C()).Should(BeClosed())
+		t.Block()
+		t.Unblock()
+		t.Unblock()
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/kralicky/post-init/pkg/api"
+	"github.com/kralicky/post-init/pkg/host"
 	"github.com/kralicky/post-init/pkg/sdk"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -13,34 +14,68 @@ import (
 
 var _ = Describe("Basic Integration", func() {
 	signer := testEnv.NewEd25519Keypair()
+	var c *sdk.RelayClient
+	var peer *sdk.Peer
 
 	Specify("setup relay", func() {
 		testEnv.SpawnRelay()
 	})
-	// Specify("setup agent", func() {
-	// 	testEnv.SpawnAgent(signer.PublicKey())
-	// })
 	Specify("setup client", func() {
-		c := testEnv.NewClient(signer)
-		clientCtx, ca := context.WithTimeout(context.Background(), 5*time.Second)
+		c = testEnv.NewClient(signer)
+		// The client stays connected for the following specs
+		Expect(c.Connect(testEnv.Context)).To(Succeed())
+	})
+	Specify("run a command from a watch callback", func() {
+		ctx, ca := context.WithTimeout(context.Background(), 5*time.Second)
 		defer ca()
-		Expect(c.Connect(clientCtx)).To(Succeed())
-		done := make(chan struct{})
-		err := c.Watch(clientCtx, &api.BasicFilter{
+		type result struct {
+			peer   *sdk.Peer
+			output *api.CommandResponse
+			err    error
+		}
+		results := make(chan result, 1)
+		_, err := c.Watch(ctx, &api.BasicFilter{
 			Operator:         api.Operator_Or,
-			HasAuthorizedKey: string(ssh.MarshalAuthorizedKey(signer.PublicKey())),
+			HasAuthorizedKey: ssh.FingerprintSHA256(signer.PublicKey()),
 		}, func(cc sdk.ControlContext) {
+			// Assertions cannot be made in the callback, which is not called
+			// from the test's goroutine.
 			output, err := cc.RunCommand(&api.Command{
 				Command: "echo",
 				Args:    []string{"hello", "world"},
 			})
-			Expect(err).To(BeNil())
-			Expect(output.Stdout).To(Equal("hello world\n"))
-			close(done)
+			select {
+			case results <- result{peer: cc.Peer(), output: output, err: err}:
+			default:
+			}
 		})
+		Expect(err).NotTo(HaveOccurred())
 		testEnv.SpawnAgent(signer.PublicKey())
 
-		Eventually(done, 3*time.Second, 100*time.Millisecond).Should(BeClosed())
-		Expect(err).To(BeNil())
+		var r result
+		Eventually(results, 3*time.Second, 100*time.Millisecond).Should(Receive(&r))
+		Expect(r.err).NotTo(HaveOccurred())
+		Expect(r.output.Stdout).To(Equal("hello world\n"))
+		Expect(r.output.ExitCode).To(BeEquivalentTo(0))
+
+		peer = r.peer
+		Expect(peer.Fingerprint).To(Equal(ssh.FingerprintSHA256(host.GetPreferredHostPublicKey())))
+		Expect(peer.Announcement).NotTo(BeNil())
+		Expect(peer.Labels).To(Equal(peer.Announcement.Labels))
+	})
+	Specify("control the agent by fingerprint", func() {
+		ctx, ca := context.WithTimeout(context.Background(), 5*time.Second)
+		defer ca()
+		agents, err := c.ListAgents(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agents).To(HaveLen(1))
+		Expect(agents[0].Fingerprint).To(Equal(peer.Fingerprint))
+
+		output, err := c.Control(ctx, peer.Fingerprint).RunCommand(&api.Command{
+			Command: "echo",
+			Args:    []string{"again"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Stdout).To(Equal("again\n"))
 	})
 })